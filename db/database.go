@@ -23,7 +23,9 @@ func GetDSN(prefix string) string {
 }
 
 func InitDB(dsn string) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		TranslateError: true,
+	})
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to connect to the database")
 		return nil, err
@@ -32,6 +34,22 @@ func InitDB(dsn string) (*gorm.DB, error) {
 	err = db.AutoMigrate(
 		&models.User{},
 		&models.Task{},
+		&models.Tag{},
+		&models.List{},
+		&models.TaskEvent{},
+		&models.Comment{},
+		&models.Attachment{},
+		&models.Share{},
+		&models.Workspace{},
+		&models.WorkspaceMember{},
+		&models.WorkspaceInvitation{},
+		&models.TaskDependency{},
+		&models.TaskStatus{},
+		&models.Reminder{},
+		&models.Notification{},
+		&models.TaskTemplate{},
+		&models.TemplateTask{},
+		&models.TimeEntry{},
 	)
 
 	if err != nil {
@@ -39,6 +57,11 @@ func InitDB(dsn string) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	if err := runPostMigrations(db); err != nil {
+		logger.Error().Err(err).Msg("Failed to run post-migration statements")
+		return nil, err
+	}
+
 	logger.Info().Msg("Database connection established and migrations run")
 	return db, nil
 }
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"gorm.io/gorm"
+)
+
+// postMigrations holds the schema changes AutoMigrate can't express. Every
+// statement must be idempotent, since they run on each startup.
+var postMigrations = []string{
+	// Full-text search over task titles (weighted higher) and contents.
+	`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (
+			setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('simple', coalesce(content, '')), 'B')
+		) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_tasks_search_vector ON tasks USING GIN (search_vector)`,
+
+	// Every user has exactly one inbox, which holds tasks created without a
+	// list, including those that predate lists.
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_lists_user_inbox ON lists (user_id)
+		WHERE is_inbox AND deleted_at IS NULL`,
+	`INSERT INTO lists (created_at, updated_at, name, is_inbox, archived, user_id)
+		SELECT now(), now(), 'Inbox', true, false, users.id FROM users
+		WHERE NOT EXISTS (
+			SELECT 1 FROM lists WHERE lists.user_id = users.id AND lists.is_inbox AND lists.deleted_at IS NULL
+		)`,
+	`UPDATE tasks SET list_id = lists.id FROM lists
+		WHERE tasks.list_id IS NULL AND lists.user_id = tasks.user_id AND lists.is_inbox AND lists.deleted_at IS NULL`,
+
+	// Lists that predate statuses get the default ones, and their tasks the
+	// first status matching whether they are completed.
+	`INSERT INTO task_statuses (list_id, name, category, position, created_at, updated_at)
+		SELECT lists.id, defaults.name, defaults.category, defaults.position, now(), now()
+		FROM lists CROSS JOIN (VALUES
+			('Backlog', 'todo', 1), ('In Progress', 'in_progress', 2), ('Review', 'in_progress', 3), ('Done', 'done', 4)
+		) AS defaults (name, category, position)
+		WHERE NOT EXISTS (SELECT 1 FROM task_statuses WHERE task_statuses.list_id = lists.id)`,
+	`UPDATE tasks SET status_id = (
+			SELECT task_statuses.id FROM task_statuses
+			WHERE task_statuses.list_id = tasks.list_id AND (task_statuses.category = 'done') = tasks.completed
+			ORDER BY task_statuses.position, task_statuses.id
+			LIMIT 1
+		)
+		WHERE tasks.status_id IS NULL AND tasks.list_id IS NOT NULL`,
+
+	// Each user has at most one running timer.
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries (user_id)
+		WHERE ended_at IS NULL`,
+}
+
+func runPostMigrations(db *gorm.DB) error {
+	for _, statement := range postMigrations {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
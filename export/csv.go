@@ -0,0 +1,91 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+var csvHeader = []string{
+	"id", "title", "content", "completed", "priority", "due_date", "all_day", "time_zone",
+	"list_id", "parent_id", "status_id", "assignee_id", "recurrence_rule", "tags",
+	"tracked_seconds", "created_at", "updated_at",
+}
+
+// csvExporter writes one row per task, with tags separated by semicolons.
+type csvExporter struct{}
+
+func (csvExporter) ContentType() string { return "text/csv; charset=utf-8" }
+func (csvExporter) Extension() string   { return "csv" }
+
+func (csvExporter) NewWriter(w io.Writer) Writer {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (c *csvWriter) WriteTask(task models.Task) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	r := toRecord(task)
+	return c.w.Write([]string{
+		strconv.FormatUint(uint64(r.ID), 10),
+		escapeFormula(r.Title),
+		escapeFormula(r.Content),
+		strconv.FormatBool(r.Completed),
+		string(r.Priority),
+		formatDueDate(task),
+		strconv.FormatBool(r.AllDay),
+		r.TimeZone,
+		formatID(r.ListID),
+		formatID(r.ParentID),
+		formatID(r.StatusID),
+		formatID(r.AssigneeID),
+		r.RecurrenceRule,
+		escapeFormula(strings.Join(r.Tags, ";")),
+		strconv.FormatInt(r.TrackedSeconds, 10),
+		r.CreatedAt.UTC().Format(time.RFC3339),
+		r.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// writeHeader writes the header row once, so even an empty export has one.
+func (c *csvWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	return c.w.Write(csvHeader)
+}
+
+func (c *csvWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// escapeFormula prefixes a quote to text that spreadsheets would otherwise
+// run as a formula, so opening an export can't execute what a user typed.
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+func formatID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
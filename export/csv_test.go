@@ -0,0 +1,55 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+func TestCSVEscapesFormulas(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		content string
+		tags    []string
+		want    [3]string
+	}{
+		{name: "equals", title: "=HYPERLINK(\"https://example.com\")", want: [3]string{"'=HYPERLINK(\"https://example.com\")"}},
+		{name: "plus", title: "+1 call", want: [3]string{"'+1 call"}},
+		{name: "minus", content: "-2+3", want: [3]string{"", "'-2+3"}},
+		{name: "at", title: "@SUM(A1)", want: [3]string{"'@SUM(A1)"}},
+		{name: "tab", title: "\t=1", want: [3]string{"'\t=1"}},
+		{name: "carriage return", content: "\r=1", want: [3]string{"", "'\r=1"}},
+		{name: "tags", tags: []string{"=cmd", "home"}, want: [3]string{"", "", "'=cmd;home"}},
+		{name: "plain text", title: "Pay rent", content: "Due on the 1st = soon", tags: []string{"home"}, want: [3]string{"Pay rent", "Due on the 1st = soon", "home"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := models.Task{Title: tt.title, Content: tt.content}
+			for _, name := range tt.tags {
+				task.Tags = append(task.Tags, models.Tag{Name: name})
+			}
+
+			var buf bytes.Buffer
+			w := csvExporter{}.NewWriter(&buf)
+			if err := w.WriteTask(task); err != nil {
+				t.Fatalf("WriteTask: %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			rows, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("reading the export: %v", err)
+			}
+			row := rows[1]
+			if got := [3]string{row[1], row[2], row[13]}; got != tt.want {
+				t.Errorf("title, content and tags = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Package export writes tasks out in formats other applications can read. Each
+// format is an Exporter registered under its name, so new formats only need
+// registering here to become available.
+package export
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+// Exporter describes one export format.
+type Exporter interface {
+	// ContentType is the media type of the exported file.
+	ContentType() string
+	// Extension is the file name extension, without the dot.
+	Extension() string
+	// NewWriter starts an export to w.
+	NewWriter(w io.Writer) Writer
+}
+
+// Writer writes tasks one at a time, so exports of any size can be streamed.
+// Close must be called once every task is written, to complete the file.
+type Writer interface {
+	WriteTask(task models.Task) error
+	Close() error
+}
+
+var exporters = map[string]Exporter{}
+
+// Register makes the exporter available under the format name, replacing any
+// exporter registered under it before.
+func Register(format string, exporter Exporter) {
+	exporters[format] = exporter
+}
+
+// Lookup returns the exporter registered under the format name.
+func Lookup(format string) (Exporter, bool) {
+	exporter, ok := exporters[format]
+	return exporter, ok
+}
+
+// Formats lists the registered format names in alphabetical order.
+func Formats() []string {
+	formats := make([]string, 0, len(exporters))
+	for format := range exporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+func init() {
+	Register("csv", csvExporter{})
+	Register("json", jsonExporter{})
+	Register("md", markdownExporter{})
+	Register("ics", icalExporter{})
+}
+
+// record is the flat form of a task shared by the tabular formats.
+type record struct {
+	ID             uint                `json:"id"`
+	Title          string              `json:"title"`
+	Content        string              `json:"content"`
+	Completed      bool                `json:"completed"`
+	Priority       models.TaskPriority `json:"priority"`
+	DueDate        *time.Time          `json:"due_date"`
+	AllDay         bool                `json:"all_day"`
+	TimeZone       string              `json:"time_zone"`
+	ListID         *uint               `json:"list_id"`
+	ParentID       *uint               `json:"parent_id"`
+	StatusID       *uint               `json:"status_id"`
+	AssigneeID     *uint               `json:"assignee_id"`
+	RecurrenceRule string              `json:"recurrence_rule"`
+	Tags           []string            `json:"tags"`
+	TrackedSeconds int64               `json:"tracked_seconds"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+func toRecord(task models.Task) record {
+	tags := make([]string, len(task.Tags))
+	for i, tag := range task.Tags {
+		tags[i] = tag.Name
+	}
+	return record{
+		ID:             task.ID,
+		Title:          task.Title,
+		Content:        task.Content,
+		Completed:      task.Completed,
+		Priority:       task.Priority,
+		DueDate:        task.DueDate,
+		AllDay:         task.AllDay,
+		TimeZone:       task.TimeZone,
+		ListID:         task.ListID,
+		ParentID:       task.ParentID,
+		StatusID:       task.StatusID,
+		AssigneeID:     task.AssigneeID,
+		RecurrenceRule: task.RecurrenceRule,
+		Tags:           tags,
+		TrackedSeconds: task.TrackedSeconds,
+		CreatedAt:      task.CreatedAt,
+		UpdatedAt:      task.UpdatedAt,
+	}
+}
+
+// formatDueDate renders the due date as a calendar date for all-day tasks
+// and an RFC 3339 timestamp otherwise, or nothing if there is none.
+func formatDueDate(task models.Task) string {
+	if task.DueDate == nil {
+		return ""
+	}
+	if task.AllDay {
+		return task.DueDate.UTC().Format(time.DateOnly)
+	}
+	return task.DueDate.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,139 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+// icalExporter writes an RFC 5545 calendar with a VTODO per task.
+type icalExporter struct{}
+
+func (icalExporter) ContentType() string { return "text/calendar; charset=utf-8" }
+func (icalExporter) Extension() string   { return "ics" }
+
+func (icalExporter) NewWriter(w io.Writer) Writer {
+	return &icalWriter{w: w, stamp: time.Now().UTC()}
+}
+
+type icalWriter struct {
+	w             io.Writer
+	stamp         time.Time
+	headerWritten bool
+}
+
+const icalTimeLayout = "20060102T150405Z"
+
+// icalPriorities maps priorities to the 1 (highest) to 9 (lowest) scale of
+// RFC 5545, where 0 means undefined.
+var icalPriorities = map[models.TaskPriority]int{
+	models.PriorityUrgent: 1,
+	models.PriorityHigh:   3,
+	models.PriorityMedium: 5,
+	models.PriorityLow:    7,
+}
+
+func (c *icalWriter) WriteTask(task models.Task) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	lines := []string{
+		"BEGIN:VTODO",
+		fmt.Sprintf("UID:task-%d@listario", task.ID),
+		"DTSTAMP:" + c.stamp.Format(icalTimeLayout),
+		"CREATED:" + task.CreatedAt.UTC().Format(icalTimeLayout),
+		"LAST-MODIFIED:" + task.UpdatedAt.UTC().Format(icalTimeLayout),
+		"SUMMARY:" + escapeICalText(task.Title),
+	}
+	if task.Content != "" {
+		lines = append(lines, "DESCRIPTION:"+escapeICalText(task.Content))
+	}
+	if task.DueDate != nil {
+		if task.AllDay {
+			lines = append(lines, "DUE;VALUE=DATE:"+task.DueDate.UTC().Format("20060102"))
+		} else {
+			lines = append(lines, "DUE:"+task.DueDate.UTC().Format(icalTimeLayout))
+		}
+		if task.RecurrenceRule != "" {
+			lines = append(lines, "RRULE:"+task.RecurrenceRule)
+		}
+	}
+	if task.Completed {
+		lines = append(lines, "STATUS:COMPLETED")
+	} else {
+		lines = append(lines, "STATUS:NEEDS-ACTION")
+	}
+	if priority, ok := icalPriorities[task.Priority]; ok {
+		lines = append(lines, fmt.Sprintf("PRIORITY:%d", priority))
+	}
+	if len(task.Tags) > 0 {
+		categories := make([]string, len(task.Tags))
+		for i, tag := range task.Tags {
+			categories[i] = escapeICalText(tag.Name)
+		}
+		lines = append(lines, "CATEGORIES:"+strings.Join(categories, ","))
+	}
+	if task.ParentID != nil {
+		lines = append(lines, fmt.Sprintf("RELATED-TO:task-%d@listario", *task.ParentID))
+	}
+	lines = append(lines, "END:VTODO")
+	return c.writeLines(lines...)
+}
+
+func (c *icalWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	return c.writeLines("BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//Listario//Tasks//EN", "CALSCALE:GREGORIAN")
+}
+
+func (c *icalWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	return c.writeLines("END:VCALENDAR")
+}
+
+func (c *icalWriter) writeLines(lines ...string) error {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldICalLine(line))
+	}
+	_, err := io.WriteString(c.w, b.String())
+	return err
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escapeICalText(text string) string {
+	return icalEscaper.Replace(text)
+}
+
+// foldICalLine ends the line with CRLF, splitting it into continuation lines
+// of at most 75 octets without breaking UTF-8 characters.
+func foldICalLine(line string) string {
+	const limit = 75
+	var b strings.Builder
+	for len(line) > limit {
+		cut := limit
+		if b.Len() > 0 {
+			// Continuation lines start with a space, which counts.
+			cut--
+		}
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+// jsonExporter writes a JSON array with an object per task.
+type jsonExporter struct{}
+
+func (jsonExporter) ContentType() string { return "application/json; charset=utf-8" }
+func (jsonExporter) Extension() string   { return "json" }
+
+func (jsonExporter) NewWriter(w io.Writer) Writer {
+	return &jsonWriter{w: w}
+}
+
+type jsonWriter struct {
+	w       io.Writer
+	written int
+}
+
+func (j *jsonWriter) WriteTask(task models.Task) error {
+	separator := ",\n"
+	if j.written == 0 {
+		separator = "[\n"
+	}
+	data, err := json.Marshal(toRecord(task))
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(j.w, separator); err != nil {
+		return err
+	}
+	if _, err := j.w.Write(data); err != nil {
+		return err
+	}
+	j.written++
+	return nil
+}
+
+func (j *jsonWriter) Close() error {
+	closing := "\n]\n"
+	if j.written == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(j.w, closing)
+	return err
+}
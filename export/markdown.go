@@ -0,0 +1,90 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+// markdownExporter writes a checklist with an item per task, followed by its
+// content.
+type markdownExporter struct{}
+
+func (markdownExporter) ContentType() string { return "text/markdown; charset=utf-8" }
+func (markdownExporter) Extension() string   { return "md" }
+
+func (markdownExporter) NewWriter(w io.Writer) Writer {
+	return &markdownWriter{w: w}
+}
+
+type markdownWriter struct {
+	w             io.Writer
+	headerWritten bool
+}
+
+func (m *markdownWriter) WriteTask(task models.Task) error {
+	if err := m.writeHeader(); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	check := " "
+	if task.Completed {
+		check = "x"
+	}
+	fmt.Fprintf(&b, "- [%s] %s", check, escapeMarkdown(task.Title))
+
+	var details []string
+	if due := formatDueDate(task); due != "" {
+		details = append(details, "due "+due)
+	}
+	if task.Priority != "" && task.Priority != models.PriorityNone {
+		details = append(details, "priority "+string(task.Priority))
+	}
+	for _, tag := range task.Tags {
+		details = append(details, "#"+tag.Name)
+	}
+	if len(details) > 0 {
+		fmt.Fprintf(&b, " (%s)", escapeMarkdown(strings.Join(details, ", ")))
+	}
+	b.WriteString("\n")
+
+	// The content is indented to stay part of the list item.
+	if content := strings.TrimSpace(task.Content); content != "" {
+		b.WriteString("\n")
+		for _, line := range strings.Split(content, "\n") {
+			if line = strings.TrimRight(line, "\r"); line != "" {
+				b.WriteString("  " + line)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(m.w, b.String())
+	return err
+}
+
+func (m *markdownWriter) writeHeader() error {
+	if m.headerWritten {
+		return nil
+	}
+	m.headerWritten = true
+	_, err := io.WriteString(m.w, "# Tasks\n\n")
+	return err
+}
+
+func (m *markdownWriter) Close() error {
+	return m.writeHeader()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, "\n", " ",
+)
+
+// escapeMarkdown keeps single-line text from being read as markup.
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
@@ -6,6 +6,7 @@ toolchain go1.24.6
 
 require (
 	github.com/joho/godotenv v1.5.1
+	github.com/teambition/rrule-go v1.8.2
 	github.com/throttled/throttled/v2 v2.13.0
 	gorm.io/driver/postgres v1.6.0
 	gorm.io/gorm v1.30.1
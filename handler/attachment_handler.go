@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/service"
+	"github.com/RLRama/listario-backend/storage"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/jwt"
+)
+
+// multipartOverhead leaves room for the multipart boundaries and headers
+// around an uploaded file when limiting the request body.
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	attachmentService service.AttachmentService
+}
+
+func NewAttachmentHandler(as service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: as}
+}
+
+func toAttachmentResponse(attachment models.Attachment) models.AttachmentResponse {
+	return models.AttachmentResponse{
+		ID:          attachment.ID,
+		TaskID:      attachment.TaskID,
+		UploaderID:  attachment.UploaderID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
+
+// readAttachmentIDs reads the task and attachment IDs from the path, writing
+// a 400 response when either is invalid.
+func readAttachmentIDs(ctx iris.Context) (taskID, attachmentID uint, ok bool) {
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return 0, 0, false
+	}
+	attachmentID, err = ctx.Params().GetUint("attachmentID")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid attachment ID"})
+		return 0, 0, false
+	}
+	return taskID, attachmentID, true
+}
+
+// UploadAttachment
+// @Summary      Attach a file to a task
+// @Description  Uploads a file, sent as multipart form data in the "file" field, to a task the authenticated user can access. Files are limited in size (10 MB by default) and to common document, image and archive types, told apart by their contents rather than the declared type. SVG images are not accepted.
+// @Tags         Attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int   true  "Task ID"
+// @Param        file  formData  file  true  "File to upload"
+// @Success      201 {object} models.AttachmentResponse
+// @Failure      400 {object} object{error=string} "Invalid ID or missing file"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      413 {object} object{error=string} "File too large"
+// @Failure      415 {object} object{error=string} "File type not allowed"
+// @Failure      500 {object} object{error=string} "Failed to upload attachment"
+// @Router       /tasks/{id}/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	ctx.SetMaxRequestBodySize(h.attachmentService.MaxSize() + multipartOverhead)
+	file, header, err := ctx.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.StatusCode(iris.StatusRequestEntityTooLarge)
+			ctx.JSON(iris.Map{"error": service.ErrAttachmentTooLarge.Error()})
+			return
+		}
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "A file must be sent in the file form field", "details": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.UploadAttachment(taskID, userID, service.AttachmentUpload{
+		FileName:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Size:        header.Size,
+		Body:        file,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrAttachmentTooLarge) {
+			ctx.StatusCode(iris.StatusRequestEntityTooLarge)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrAttachmentTypeNotAllowed) {
+			ctx.StatusCode(iris.StatusUnsupportedMediaType)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to upload attachment")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Failed to upload attachment"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toAttachmentResponse(*attachment))
+}
+
+// GetAttachments
+// @Summary      Get a task's attachments
+// @Description  Lists the files attached to a task the authenticated user can access, oldest first.
+// @Tags         Attachments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Task ID"
+// @Success      200 {array} models.AttachmentResponse
+// @Failure      400 {object} object{error=string} "Invalid task ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve attachments"
+// @Router       /tasks/{id}/attachments [get]
+func (h *AttachmentHandler) GetAttachments(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	attachments, err := h.attachmentService.GetAttachments(taskID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to get attachments for task")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not retrieve attachments"})
+		}
+		return
+	}
+
+	response := make([]models.AttachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		response[i] = toAttachmentResponse(attachment)
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(response)
+}
+
+// DownloadAttachment
+// @Summary      Download an attachment
+// @Description  Streams the contents of a file attached to a task the authenticated user can access, as a download under its original name.
+// @Tags         Attachments
+// @Produce      octet-stream
+// @Security     BearerAuth
+// @Param        id            path  int  true  "Task ID"
+// @Param        attachmentID  path  int  true  "Attachment ID"
+// @Success      200 {file} file
+// @Failure      400 {object} object{error=string} "Invalid ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task or attachment not found"
+// @Failure      500 {object} object{error=string} "Could not download attachment"
+// @Router       /tasks/{id}/attachments/{attachmentID} [get]
+func (h *AttachmentHandler) DownloadAttachment(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, attachmentID, ok := readAttachmentIDs(ctx)
+	if !ok {
+		return
+	}
+
+	attachment, body, err := h.attachmentService.OpenAttachment(taskID, attachmentID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) || errors.Is(err, repository.ErrAttachmentNotFound) || errors.Is(err, storage.ErrBlobNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": repository.ErrAttachmentNotFound.Error()})
+		} else {
+			logger.Error().Err(err).Uint("attachmentID", attachmentID).Msg("Failed to open attachment")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not download attachment"})
+		}
+		return
+	}
+	defer body.Close()
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+	ctx.Header("Content-Type", attachment.ContentType)
+	ctx.Header("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	ctx.Header("Content-Disposition", disposition)
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.StatusCode(iris.StatusOK)
+
+	if _, err := io.Copy(ctx, body); err != nil {
+		logger.Error().Err(err).Uint("attachmentID", attachmentID).Msg("Failed to stream attachment")
+	}
+}
+
+// DeleteAttachment
+// @Summary      Delete an attachment
+// @Description  Deletes a file the authenticated user uploaded, or any file attached to a task they own.
+// @Tags         Attachments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id            path  int  true  "Task ID"
+// @Param        attachmentID  path  int  true  "Attachment ID"
+// @Success      204 "No Content"
+// @Failure      400 {object} object{error=string} "Invalid ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task or attachment not found"
+// @Failure      500 {object} object{error=string} "Could not delete attachment"
+// @Router       /tasks/{id}/attachments/{attachmentID} [delete]
+func (h *AttachmentHandler) DeleteAttachment(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, attachmentID, ok := readAttachmentIDs(ctx)
+	if !ok {
+		return
+	}
+
+	err := h.attachmentService.DeleteAttachment(taskID, attachmentID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) || errors.Is(err, service.ErrAttachmentAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) || errors.Is(err, repository.ErrAttachmentNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("attachmentID", attachmentID).Msg("Failed to delete attachment")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not delete attachment"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/service"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/jwt"
+)
+
+type CommentHandler struct {
+	commentService service.CommentService
+}
+
+func NewCommentHandler(cs service.CommentService) *CommentHandler {
+	return &CommentHandler{commentService: cs}
+}
+
+func toCommentResponse(comment models.Comment) models.CommentResponse {
+	return models.CommentResponse{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		AuthorID:  comment.AuthorID,
+		Body:      comment.Body,
+		EditedAt:  comment.EditedAt,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
+
+// readCommentIDs reads the task and comment IDs from the path, writing a 400
+// response when either is invalid.
+func readCommentIDs(ctx iris.Context) (taskID, commentID uint, ok bool) {
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return 0, 0, false
+	}
+	commentID, err = ctx.Params().GetUint("commentID")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid comment ID"})
+		return 0, 0, false
+	}
+	return taskID, commentID, true
+}
+
+// CreateComment
+// @Summary      Comment on a task
+// @Description  Adds a markdown comment to a task the authenticated user can access.
+// @Tags         Comments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                          true  "Task ID"
+// @Param        payload body  models.CreateCommentRequest  true  "Comment Payload"
+// @Success      201 {object} models.CommentResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Failed to create comment"
+// @Router       /tasks/{id}/comments [post]
+func (h *CommentHandler) CreateComment(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate create comment request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(taskID, userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to create comment")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Failed to create comment"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toCommentResponse(*comment))
+}
+
+// GetComments
+// @Summary      Get a task's comments
+// @Description  Retrieves the comments on a task the authenticated user can access, oldest first.
+// @Tags         Comments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Task ID"
+// @Success      200 {array} models.CommentResponse
+// @Failure      400 {object} object{error=string} "Invalid task ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve comments"
+// @Router       /tasks/{id}/comments [get]
+func (h *CommentHandler) GetComments(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	comments, err := h.commentService.GetComments(taskID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to get comments for task")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not retrieve comments"})
+		}
+		return
+	}
+
+	response := make([]models.CommentResponse, len(comments))
+	for i, comment := range comments {
+		response[i] = toCommentResponse(comment)
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(response)
+}
+
+// UpdateComment
+// @Summary      Edit a comment
+// @Description  Changes the body of a comment written by the authenticated user. Edited comments report when they were last edited.
+// @Tags         Comments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path  int                          true  "Task ID"
+// @Param        commentID  path  int                          true  "Comment ID"
+// @Param        payload    body  models.UpdateCommentRequest  true  "Comment Update Payload"
+// @Success      200 {object} models.CommentResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task or comment not found"
+// @Failure      500 {object} object{error=string} "Failed to update comment"
+// @Router       /tasks/{id}/comments/{commentID} [put]
+func (h *CommentHandler) UpdateComment(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, commentID, ok := readCommentIDs(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCommentRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate update comment request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(taskID, commentID, userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) || errors.Is(err, service.ErrCommentAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) || errors.Is(err, repository.ErrCommentNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("commentID", commentID).Msg("Failed to update comment")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Failed to update comment"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toCommentResponse(*comment))
+}
+
+// DeleteComment
+// @Summary      Delete a comment
+// @Description  Deletes a comment written by the authenticated user, or any comment on a task they own.
+// @Tags         Comments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path  int  true  "Task ID"
+// @Param        commentID  path  int  true  "Comment ID"
+// @Success      204 "No Content"
+// @Failure      400 {object} object{error=string} "Invalid ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task or comment not found"
+// @Failure      500 {object} object{error=string} "Could not delete comment"
+// @Router       /tasks/{id}/comments/{commentID} [delete]
+func (h *CommentHandler) DeleteComment(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, commentID, ok := readCommentIDs(ctx)
+	if !ok {
+		return
+	}
+
+	err := h.commentService.DeleteComment(taskID, commentID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) || errors.Is(err, service.ErrCommentAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) || errors.Is(err, repository.ErrCommentNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("commentID", commentID).Msg("Failed to delete comment")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not delete comment"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RLRama/listario-backend/importer"
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/service"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/jwt"
+)
+
+type ImportHandler struct {
+	importService service.ImportService
+}
+
+func NewImportHandler(is service.ImportService) *ImportHandler {
+	return &ImportHandler{importService: is}
+}
+
+func toImportResponse(report *service.ImportReport) models.ImportResponse {
+	response := models.ImportResponse{
+		Format:  report.Format,
+		DryRun:  report.DryRun,
+		Created: report.Created,
+		Tasks:   make([]models.ImportedTaskResponse, len(report.Tasks)),
+		Errors:  make([]models.ImportRowError, len(report.Errors)),
+	}
+	for i, task := range report.Tasks {
+		var parentRow *int
+		if task.ParentRow != 0 {
+			parentRow = &task.ParentRow
+		}
+		tags := task.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		response.Tasks[i] = models.ImportedTaskResponse{
+			Row:            task.Row,
+			ParentRow:      parentRow,
+			Title:          task.Title,
+			Priority:       task.Priority,
+			DueDate:        task.DueDate,
+			AllDay:         task.AllDay,
+			Completed:      task.Completed,
+			RecurrenceRule: task.RecurrenceRule,
+			Tags:           tags,
+		}
+	}
+	for i, rowErr := range report.Errors {
+		response.Errors[i] = models.ImportRowError{Row: rowErr.Row, Field: rowErr.Field, Message: rowErr.Message}
+	}
+	return response
+}
+
+// ImportTasks
+// @Summary      Import tasks from a file
+// @Description  Creates tasks from a file sent as multipart form data in the "file" field, in the given format: Listario's own CSV or JSON export, a Todoist CSV export, a Trello board JSON export, Microsoft To Do tasks as returned by Microsoft Graph, or Google Tasks from Google Takeout or the Google Tasks API. Subtasks, completion, due dates and tags carry over, with tags created when missing. Every task is validated first and the file is imported in a single transaction, so if any row has errors nothing is created and the errors are returned. With dry_run, the file is only validated and the tasks that would be created are listed. Files are limited to 5 MB and 2000 tasks.
+// @Tags         Tasks
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file     formData  file    true   "File to import"
+// @Param        format   formData  string  true   "Format of the file"  Enums(csv, json, todoist, trello, microsoft_todo, google_tasks)
+// @Param        dry_run  formData  bool    false  "Only validate the file and report what would be created"
+// @Param        list_id  formData  int     false  "List to put the top-level tasks in"
+// @Success      200 {object} models.ImportResponse "Dry run report"
+// @Success      201 {object} models.ImportResponse "Tasks created"
+// @Failure      400 {object} object{error=string} "Missing or unreadable file, invalid format or invalid list"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Guests cannot create tasks in the workspace"
+// @Failure      413 {object} object{error=string} "File too large"
+// @Failure      422 {object} models.ImportResponse "Some rows are invalid, so nothing was imported"
+// @Failure      500 {object} object{error=string} "Failed to import tasks"
+// @Router       /tasks/import [post]
+func (h *ImportHandler) ImportTasks(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	ctx.SetMaxRequestBodySize(service.MaxImportFileSize + multipartOverhead)
+	file, _, err := ctx.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.StatusCode(iris.StatusRequestEntityTooLarge)
+			ctx.JSON(iris.Map{"error": fmt.Sprintf("Import files are limited to %d MB", service.MaxImportFileSize>>20)})
+			return
+		}
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "A file must be sent in the file form field", "details": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	req := service.TaskImport{Format: ctx.FormValue("format"), File: file}
+	if value := ctx.FormValue("dry_run"); value != "" {
+		if req.DryRun, err = strconv.ParseBool(value); err != nil {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": "dry_run must be true or false"})
+			return
+		}
+	}
+	if value := ctx.FormValue("list_id"); value != "" {
+		listID, err := strconv.ParseUint(value, 10, 0)
+		if err != nil {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": "Invalid list ID"})
+			return
+		}
+		id := uint(listID)
+		req.ListID = &id
+	}
+
+	report, err := h.importService.ImportTasks(userID, activeWorkspaceID(ctx), req, time.Now())
+	if err != nil {
+		if errors.Is(err, service.ErrImportInvalid) {
+			ctx.StatusCode(iris.StatusUnprocessableEntity)
+			ctx.JSON(toImportResponse(report))
+		} else if errors.Is(err, service.ErrImportFormat) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": fmt.Sprintf("Invalid format %q, expected one of: %s", req.Format, strings.Join(importer.Formats(), ", "))})
+		} else if errors.Is(err, service.ErrInvalidImportFile) || errors.Is(err, service.ErrImportTooLarge) || isTaskInputError(err) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrWorkspaceAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("userID", userID).Str("format", req.Format).Msg("Failed to import tasks")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Failed to import tasks"})
+		}
+		return
+	}
+
+	if report.DryRun {
+		ctx.StatusCode(iris.StatusOK)
+	} else {
+		ctx.StatusCode(iris.StatusCreated)
+	}
+	ctx.JSON(toImportResponse(report))
+}
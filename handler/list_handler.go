@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/service"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/jwt"
+)
+
+type ListHandler struct {
+	listService service.ListService
+}
+
+func NewListHandler(ls service.ListService) *ListHandler {
+	return &ListHandler{listService: ls}
+}
+
+func toListResponse(list models.List) models.ListResponse {
+	return models.ListResponse{
+		ID:          list.ID,
+		Name:        list.Name,
+		Color:       list.Color,
+		Icon:        list.Icon,
+		Archived:    list.Archived,
+		IsInbox:     list.IsInbox,
+		UserID:      list.UserID,
+		WorkspaceID: list.WorkspaceID,
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+	}
+}
+
+// CreateList
+// @Summary      Create a new list
+// @Description  Creates a new list (project) for the authenticated user, in the active workspace if there is one.
+// @Tags         Lists
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload body models.CreateListRequest true "List Creation Payload"
+// @Success      201 {object} models.ListResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or validation failed"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Guests cannot create lists in the workspace"
+// @Failure      500 {object} object{error=string} "Failed to create list"
+// @Router       /lists [post]
+func (h *ListHandler) CreateList(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	var req models.CreateListRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate create list request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	list, err := h.listService.CreateList(userID, activeWorkspaceID(ctx), req)
+	if err != nil {
+		if errors.Is(err, service.ErrWorkspaceAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+			return
+		}
+		logger.Error().Err(err).Msg("Failed to create list")
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": "Failed to create list"})
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toListResponse(*list))
+}
+
+// GetMyLists
+// @Summary      Get all lists for the current user
+// @Description  Retrieves the lists of the active workspace, or the authenticated user's personal lists with the inbox first. Archived lists are only included when archived=true.
+// @Tags         Lists
+// @Produce      json
+// @Security     BearerAuth
+// @Param        archived  query  bool  false  "Include archived lists"
+// @Success      200 {array} models.ListResponse
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      500 {object} object{error=string} "Could not retrieve lists"
+// @Router       /lists [get]
+func (h *ListHandler) GetMyLists(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	lists, err := h.listService.GetLists(userID, activeWorkspaceID(ctx), ctx.URLParamBoolDefault("archived", false))
+	if err != nil {
+		logger.Error().Err(err).Uint("userID", userID).Msg("Failed to get lists for user")
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": "Could not retrieve lists"})
+		return
+	}
+
+	response := make([]models.ListResponse, len(lists))
+	for i, list := range lists {
+		response[i] = toListResponse(list)
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(response)
+}
+
+// GetList
+// @Summary      Get a single list by ID
+// @Description  Retrieves details for a specific list if it belongs to the authenticated user.
+// @Tags         Lists
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "List ID"
+// @Success      200 {object} models.ListResponse
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "List not found"
+// @Router       /lists/{id} [get]
+func (h *ListHandler) GetList(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	listID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid list ID"})
+		return
+	}
+
+	list, err := h.listService.GetList(listID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrListAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrListNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("listID", listID).Msg("Failed to get list")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not retrieve list"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toListResponse(*list))
+}
+
+// UpdateList
+// @Summary      Update a list
+// @Description  Updates a list's name, color or icon, or archives/unarchives it. The inbox cannot be archived.
+// @Tags         Lists
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                      true  "List ID"
+// @Param        payload body  models.UpdateListRequest true  "List Update Payload"
+// @Success      200 {object} models.ListResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "List not found"
+// @Failure      409 {object} object{error=string} "The inbox list cannot be archived"
+// @Failure      500 {object} object{error=string} "Could not update list"
+// @Router       /lists/{id} [put]
+func (h *ListHandler) UpdateList(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	listID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid list ID"})
+		return
+	}
+
+	var req models.UpdateListRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate update list request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	list, err := h.listService.UpdateList(listID, userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrListAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrListNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrInboxImmutable) {
+			ctx.StatusCode(iris.StatusConflict)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("listID", listID).Msg("Failed to update list")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not update list"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toListResponse(*list))
+}
+
+// DeleteList
+// @Summary      Delete a list
+// @Description  Deletes a list belonging to the authenticated user and moves its tasks to the inbox. The inbox cannot be deleted.
+// @Tags         Lists
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "List ID"
+// @Success      204 "No Content"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "List not found"
+// @Failure      409 {object} object{error=string} "The inbox list cannot be deleted"
+// @Failure      500 {object} object{error=string} "Could not delete list"
+// @Router       /lists/{id} [delete]
+func (h *ListHandler) DeleteList(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	listID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid list ID"})
+		return
+	}
+
+	err = h.listService.DeleteList(listID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrListAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrListNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrInboxImmutable) {
+			ctx.StatusCode(iris.StatusConflict)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("listID", listID).Msg("Failed to delete list")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not delete list"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/service"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/jwt"
+)
+
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+func NewNotificationHandler(ns service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: ns}
+}
+
+func toNotificationResponse(notification models.Notification) models.NotificationResponse {
+	return models.NotificationResponse{
+		ID:         notification.ID,
+		TaskID:     notification.TaskID,
+		ReminderID: notification.ReminderID,
+		Title:      notification.Title,
+		Body:       notification.Body,
+		ReadAt:     notification.ReadAt,
+		CreatedAt:  notification.CreatedAt,
+	}
+}
+
+// GetNotifications
+// @Summary      Get my notifications
+// @Description  Retrieves the authenticated user's 100 newest in-app notifications, newest first.
+// @Tags         Notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        unread  query  bool  false  "Only return unread notifications"
+// @Success      200 {array} models.NotificationResponse
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      500 {object} object{error=string} "Could not retrieve notifications"
+// @Router       /notifications [get]
+func (h *NotificationHandler) GetNotifications(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	notifications, err := h.notificationService.GetNotifications(userID, ctx.URLParamBoolDefault("unread", false))
+	if err != nil {
+		logger.Error().Err(err).Uint("userID", userID).Msg("Failed to get notifications for user")
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": "Could not retrieve notifications"})
+		return
+	}
+
+	response := make([]models.NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		response[i] = toNotificationResponse(notification)
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(response)
+}
+
+// MarkNotificationRead
+// @Summary      Mark a notification as read
+// @Description  Marks one of the authenticated user's notifications as read.
+// @Tags         Notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Notification ID"
+// @Success      204 "No Content"
+// @Failure      400 {object} object{error=string} "Invalid notification ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      404 {object} object{error=string} "Notification not found"
+// @Failure      500 {object} object{error=string} "Could not update notification"
+// @Router       /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	notificationID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid notification ID"})
+		return
+	}
+
+	err = h.notificationService.MarkRead(notificationID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotificationNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("notificationID", notificationID).Msg("Failed to mark notification as read")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not update notification"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
+
+// MarkAllNotificationsRead
+// @Summary      Mark all notifications as read
+// @Description  Marks every unread notification of the authenticated user as read.
+// @Tags         Notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      204 "No Content"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      500 {object} object{error=string} "Could not update notifications"
+// @Router       /notifications/read [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	if err := h.notificationService.MarkAllRead(userID); err != nil {
+		logger.Error().Err(err).Uint("userID", userID).Msg("Failed to mark notifications as read")
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": "Could not update notifications"})
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
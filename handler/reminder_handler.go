@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/service"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/jwt"
+)
+
+type ReminderHandler struct {
+	reminderService service.ReminderService
+}
+
+func NewReminderHandler(rs service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{reminderService: rs}
+}
+
+func toReminderResponse(reminder models.Reminder) models.ReminderResponse {
+	return models.ReminderResponse{
+		ID:            reminder.ID,
+		TaskID:        reminder.TaskID,
+		RemindAt:      reminder.RemindAt,
+		MinutesBefore: reminder.MinutesBefore,
+		Channel:       reminder.Channel,
+		WebhookURL:    reminder.WebhookURL,
+		Status:        reminder.Status,
+		Attempts:      reminder.Attempts,
+		SentAt:        reminder.SentAt,
+		CreatedAt:     reminder.CreatedAt,
+	}
+}
+
+// CreateReminder
+// @Summary      Set a reminder on a task
+// @Description  Reminds the authenticated user about a task they can access, either at remind_at or minutes_before its due date. All-day tasks count as due at 9:00 in their time zone. Reminders are delivered by email, webhook or in-app notification, and skipped if the task is completed or deleted by then. Webhook URLs must use https and reach a public address; redirects are not followed.
+// @Tags         Reminders
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                           true  "Task ID"
+// @Param        payload body  models.CreateReminderRequest  true  "Reminder Payload"
+// @Success      201 {object} models.ReminderResponse
+// @Failure      400 {object} object{error=string} "Invalid request, unavailable channel, past time or task without a due date"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Failed to create reminder"
+// @Router       /tasks/{id}/reminders [post]
+func (h *ReminderHandler) CreateReminder(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.CreateReminderRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate create reminder request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	reminder, err := h.reminderService.CreateReminder(taskID, userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrReminderChannelUnavailable) || errors.Is(err, service.ErrReminderNeedsDueDate) || errors.Is(err, service.ErrReminderInPast) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to create reminder")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Failed to create reminder"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toReminderResponse(*reminder))
+}
+
+// GetReminders
+// @Summary      Get my reminders on a task
+// @Description  Retrieves the reminders the authenticated user set on a task, oldest first.
+// @Tags         Reminders
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Task ID"
+// @Success      200 {array} models.ReminderResponse
+// @Failure      400 {object} object{error=string} "Invalid task ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve reminders"
+// @Router       /tasks/{id}/reminders [get]
+func (h *ReminderHandler) GetReminders(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	reminders, err := h.reminderService.GetReminders(taskID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to get reminders for task")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not retrieve reminders"})
+		}
+		return
+	}
+
+	response := make([]models.ReminderResponse, len(reminders))
+	for i, reminder := range reminders {
+		response[i] = toReminderResponse(reminder)
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(response)
+}
+
+// DeleteReminder
+// @Summary      Delete a reminder
+// @Description  Removes a reminder the authenticated user set on a task.
+// @Tags         Reminders
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path  int  true  "Task ID"
+// @Param        reminderID  path  int  true  "Reminder ID"
+// @Success      204 "No Content"
+// @Failure      400 {object} object{error=string} "Invalid ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task or reminder not found"
+// @Failure      500 {object} object{error=string} "Could not delete reminder"
+// @Router       /tasks/{id}/reminders/{reminderID} [delete]
+func (h *ReminderHandler) DeleteReminder(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+	reminderID, err := ctx.Params().GetUint("reminderID")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid reminder ID"})
+		return
+	}
+
+	err = h.reminderService.DeleteReminder(taskID, reminderID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) || errors.Is(err, repository.ErrReminderNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("reminderID", reminderID).Msg("Failed to delete reminder")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not delete reminder"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
@@ -0,0 +1,347 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/service"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/jwt"
+)
+
+type ShareHandler struct {
+	shareService service.ShareService
+}
+
+func NewShareHandler(ss service.ShareService) *ShareHandler {
+	return &ShareHandler{shareService: ss}
+}
+
+func toShareResponse(share models.Share) models.ShareResponse {
+	return models.ShareResponse{
+		ID:         share.ID,
+		TaskID:     share.TaskID,
+		ListID:     share.ListID,
+		UserID:     share.UserID,
+		Username:   share.User.Username,
+		Email:      share.User.Email,
+		SharedByID: share.SharedByID,
+		Role:       share.Role,
+		CreatedAt:  share.CreatedAt,
+		UpdatedAt:  share.UpdatedAt,
+	}
+}
+
+func toShareResponses(shares []models.Share) []models.ShareResponse {
+	responses := make([]models.ShareResponse, len(shares))
+	for i, share := range shares {
+		responses[i] = toShareResponse(share)
+	}
+	return responses
+}
+
+// isShareAccessError reports whether err means the user may not see or change
+// the shared task or list.
+func isShareAccessError(err error) bool {
+	return errors.Is(err, service.ErrTaskAccessDenied) ||
+		errors.Is(err, service.ErrListAccessDenied) ||
+		errors.Is(err, service.ErrShareAccessDenied)
+}
+
+// isShareTargetNotFound reports whether err means the task or list to share
+// doesn't exist.
+func isShareTargetNotFound(err error) bool {
+	return errors.Is(err, repository.ErrTaskNotFound) || errors.Is(err, repository.ErrListNotFound)
+}
+
+// ShareTask
+// @Summary      Share a task
+// @Description  Shares a task the authenticated user owns, including its subtasks, with another user by email. Viewers can see the task; editors can also change it.
+// @Tags         Shares
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                        true  "Task ID"
+// @Param        payload body  models.CreateShareRequest  true  "Share Payload"
+// @Success      201 {object} models.ShareResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID, or sharing with the owner"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task or user not found"
+// @Failure      409 {object} object{error=string} "Already shared with this user"
+// @Failure      500 {object} object{error=string} "Failed to share task"
+// @Router       /tasks/{id}/shares [post]
+func (h *ShareHandler) ShareTask(ctx iris.Context) {
+	h.createShare(ctx, "task", h.shareService.ShareTask)
+}
+
+// ShareList
+// @Summary      Share a list
+// @Description  Shares a list the authenticated user owns, including all of its tasks, with another user by email. Viewers can see the list's tasks; editors can also change them and add new ones.
+// @Tags         Shares
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                        true  "List ID"
+// @Param        payload body  models.CreateShareRequest  true  "Share Payload"
+// @Success      201 {object} models.ShareResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID, or sharing with the owner"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "List or user not found"
+// @Failure      409 {object} object{error=string} "Already shared with this user"
+// @Failure      500 {object} object{error=string} "Failed to share list"
+// @Router       /lists/{id}/shares [post]
+func (h *ShareHandler) ShareList(ctx iris.Context) {
+	h.createShare(ctx, "list", h.shareService.ShareList)
+}
+
+// createShare handles sharing either kind of item, named by target, through
+// the given service method.
+func (h *ShareHandler) createShare(ctx iris.Context, target string, share func(id, userID uint, req models.CreateShareRequest) (*models.Share, error)) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	id, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid " + target + " ID"})
+		return
+	}
+
+	var req models.CreateShareRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate create share request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	created, err := share(id, userID, req)
+	if err != nil {
+		if isShareAccessError(err) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if isShareTargetNotFound(err) || errors.Is(err, repository.ErrUserNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrCannotShareWithSelf) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrShareAlreadyExists) {
+			ctx.StatusCode(iris.StatusConflict)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("id", id).Msg("Failed to share " + target)
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Failed to share " + target})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toShareResponse(*created))
+}
+
+// GetTaskShares
+// @Summary      Get a task's shares
+// @Description  Lists the users a task the authenticated user can access is shared with directly.
+// @Tags         Shares
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Task ID"
+// @Success      200 {array} models.ShareResponse
+// @Failure      400 {object} object{error=string} "Invalid task ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve shares"
+// @Router       /tasks/{id}/shares [get]
+func (h *ShareHandler) GetTaskShares(ctx iris.Context) {
+	h.getShares(ctx, "task", h.shareService.GetTaskShares)
+}
+
+// GetListShares
+// @Summary      Get a list's shares
+// @Description  Lists the users a list the authenticated user can access is shared with.
+// @Tags         Shares
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "List ID"
+// @Success      200 {array} models.ShareResponse
+// @Failure      400 {object} object{error=string} "Invalid list ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "List not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve shares"
+// @Router       /lists/{id}/shares [get]
+func (h *ShareHandler) GetListShares(ctx iris.Context) {
+	h.getShares(ctx, "list", h.shareService.GetListShares)
+}
+
+func (h *ShareHandler) getShares(ctx iris.Context, target string, find func(id, userID uint) ([]models.Share, error)) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	id, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid " + target + " ID"})
+		return
+	}
+
+	shares, err := find(id, userID)
+	if err != nil {
+		if isShareAccessError(err) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if isShareTargetNotFound(err) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("id", id).Msg("Failed to get " + target + " shares")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not retrieve shares"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toShareResponses(shares))
+}
+
+// UpdateShare
+// @Summary      Change a share's role
+// @Description  Changes the role a share grants. Only the owner of the shared task or list can do this.
+// @Tags         Shares
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                        true  "Share ID"
+// @Param        payload body  models.UpdateShareRequest  true  "Share Update Payload"
+// @Success      200 {object} models.ShareResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Share not found"
+// @Failure      500 {object} object{error=string} "Could not update share"
+// @Router       /shares/{id} [put]
+func (h *ShareHandler) UpdateShare(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	shareID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid share ID"})
+		return
+	}
+
+	var req models.UpdateShareRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate update share request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	share, err := h.shareService.UpdateShare(shareID, userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrShareAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrShareNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("shareID", shareID).Msg("Failed to update share")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not update share"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toShareResponse(*share))
+}
+
+// DeleteShare
+// @Summary      Revoke a share
+// @Description  Revokes a share. The owner of the shared task or list can revoke any of its shares, and a user can leave a share given to them.
+// @Tags         Shares
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Share ID"
+// @Success      204 "No Content"
+// @Failure      400 {object} object{error=string} "Invalid share ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Share not found"
+// @Failure      500 {object} object{error=string} "Could not delete share"
+// @Router       /shares/{id} [delete]
+func (h *ShareHandler) DeleteShare(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	shareID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid share ID"})
+		return
+	}
+
+	if err := h.shareService.DeleteShare(shareID, userID); err != nil {
+		if errors.Is(err, service.ErrShareAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrShareNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("shareID", shareID).Msg("Failed to delete share")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not delete share"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
+
+// GetSharedWithMe
+// @Summary      Get what is shared with me
+// @Description  Lists the tasks and lists other users have shared with the authenticated user, along with the role each share grants.
+// @Tags         Shares
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} models.SharedWithMeResponse
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      500 {object} object{error=string} "Could not retrieve shared items"
+// @Router       /shared [get]
+func (h *ShareHandler) GetSharedWithMe(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	shared, err := h.shareService.GetSharedWithMe(userID)
+	if err != nil {
+		logger.Error().Err(err).Uint("userID", userID).Msg("Failed to get shared items")
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": "Could not retrieve shared items"})
+		return
+	}
+
+	response := models.SharedWithMeResponse{
+		Tasks: make([]models.SharedTaskResponse, len(shared.Tasks)),
+		Lists: make([]models.SharedListResponse, len(shared.Lists)),
+	}
+	for i, item := range shared.Tasks {
+		response.Tasks[i] = models.SharedTaskResponse{Role: item.Share.Role, SharedByID: item.Share.SharedByID, Task: toTaskResponse(item.Task)}
+	}
+	for i, item := range shared.Lists {
+		response.Lists[i] = models.SharedListResponse{Role: item.Share.Role, SharedByID: item.Share.SharedByID, List: toListResponse(item.List)}
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(response)
+}
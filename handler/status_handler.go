@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/service"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/jwt"
+)
+
+type StatusHandler struct {
+	statusService service.StatusService
+}
+
+func NewStatusHandler(ss service.StatusService) *StatusHandler {
+	return &StatusHandler{statusService: ss}
+}
+
+func toStatusResponse(status models.TaskStatus) models.StatusResponse {
+	return models.StatusResponse{
+		ID:       status.ID,
+		ListID:   status.ListID,
+		Name:     status.Name,
+		Category: status.Category,
+		Position: status.Position,
+	}
+}
+
+func toStatusResponses(statuses []models.TaskStatus) []models.StatusResponse {
+	responses := make([]models.StatusResponse, len(statuses))
+	for i, status := range statuses {
+		responses[i] = toStatusResponse(status)
+	}
+	return responses
+}
+
+// readStatusIDs reads the list and status IDs from the path, writing a 400
+// response when either is invalid.
+func readStatusIDs(ctx iris.Context) (listID, statusID uint, ok bool) {
+	listID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid list ID"})
+		return 0, 0, false
+	}
+	statusID, err = ctx.Params().GetUint("statusID")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid status ID"})
+		return 0, 0, false
+	}
+	return listID, statusID, true
+}
+
+// GetStatuses
+// @Summary      Get a list's statuses
+// @Description  Lists the workflow statuses of a list the authenticated user can access, in board order.
+// @Tags         Statuses
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "List ID"
+// @Success      200 {array} models.StatusResponse
+// @Failure      400 {object} object{error=string} "Invalid list ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "List not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve statuses"
+// @Router       /lists/{id}/statuses [get]
+func (h *StatusHandler) GetStatuses(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	listID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid list ID"})
+		return
+	}
+
+	statuses, err := h.statusService.GetStatuses(listID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrListAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrListNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("listID", listID).Msg("Failed to retrieve statuses")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not retrieve statuses"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toStatusResponses(statuses))
+}
+
+// CreateStatus
+// @Summary      Add a status to a list
+// @Description  Adds a workflow status to a list owned by the authenticated user. Tasks with a status in the done category are completed.
+// @Tags         Statuses
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                          true  "List ID"
+// @Param        payload body  models.CreateStatusRequest   true  "Status Payload"
+// @Success      201 {object} models.StatusResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "List not found"
+// @Failure      409 {object} object{error=string} "A status with this name already exists"
+// @Failure      500 {object} object{error=string} "Could not create status"
+// @Router       /lists/{id}/statuses [post]
+func (h *StatusHandler) CreateStatus(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	listID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid list ID"})
+		return
+	}
+
+	var req models.CreateStatusRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate create status request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	status, err := h.statusService.CreateStatus(listID, userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrListAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrListNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrStatusExists) {
+			ctx.StatusCode(iris.StatusConflict)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("listID", listID).Msg("Failed to create status")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not create status"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toStatusResponse(*status))
+}
+
+// UpdateStatus
+// @Summary      Update a status
+// @Description  Renames, reorders or recategorizes a status of a list owned by the authenticated user. A status can only move into or out of the done category once none of the list's tasks has it; move them to another status first. A list must keep at least one open and one done status.
+// @Tags         Statuses
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path  int                          true  "List ID"
+// @Param        statusID  path  int                          true  "Status ID"
+// @Param        payload   body  models.UpdateStatusRequest   true  "Status Update Payload"
+// @Success      200 {object} models.StatusResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "List or status not found"
+// @Failure      409 {object} object{error=string} "Duplicate name, the list's last open or done status, or a status still in use changing category"
+// @Failure      500 {object} object{error=string} "Could not update status"
+// @Router       /lists/{id}/statuses/{statusID} [put]
+func (h *StatusHandler) UpdateStatus(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	listID, statusID, ok := readStatusIDs(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateStatusRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate update status request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	status, err := h.statusService.UpdateStatus(listID, userID, statusID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrListAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrListNotFound) || errors.Is(err, repository.ErrStatusNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrStatusExists) || errors.Is(err, service.ErrStatusRequired) || errors.Is(err, service.ErrStatusInUse) {
+			ctx.StatusCode(iris.StatusConflict)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("statusID", statusID).Msg("Failed to update status")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not update status"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toStatusResponse(*status))
+}
+
+// DeleteStatus
+// @Summary      Delete a status
+// @Description  Deletes a status of a list owned by the authenticated user. Its tasks move to the list's first other status with the same completion. A list must keep at least one open and one done status.
+// @Tags         Statuses
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path  int  true  "List ID"
+// @Param        statusID  path  int  true  "Status ID"
+// @Success      204 "No Content"
+// @Failure      400 {object} object{error=string} "Invalid list or status ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "List or status not found"
+// @Failure      409 {object} object{error=string} "The list's last open or done status"
+// @Failure      500 {object} object{error=string} "Could not delete status"
+// @Router       /lists/{id}/statuses/{statusID} [delete]
+func (h *StatusHandler) DeleteStatus(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	listID, statusID, ok := readStatusIDs(ctx)
+	if !ok {
+		return
+	}
+
+	err := h.statusService.DeleteStatus(listID, userID, statusID)
+	if err != nil {
+		if errors.Is(err, service.ErrListAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrListNotFound) || errors.Is(err, repository.ErrStatusNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrStatusRequired) {
+			ctx.StatusCode(iris.StatusConflict)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("statusID", statusID).Msg("Failed to delete status")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not delete status"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
+
+// GetBoard
+// @Summary      Get a list's board
+// @Description  Returns the top-level tasks of a list the authenticated user can access, grouped into a column per status in board order.
+// @Tags         Statuses
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "List ID"
+// @Success      200 {object} models.BoardResponse
+// @Failure      400 {object} object{error=string} "Invalid list ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "List not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve board"
+// @Router       /lists/{id}/board [get]
+func (h *StatusHandler) GetBoard(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	listID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid list ID"})
+		return
+	}
+
+	board, err := h.statusService.GetBoard(listID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrListAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrListNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("listID", listID).Msg("Failed to retrieve board")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not retrieve board"})
+		}
+		return
+	}
+
+	response := models.BoardResponse{
+		List:    toListResponse(*board.List),
+		Columns: make([]models.BoardColumn, len(board.Columns)),
+	}
+	for i, column := range board.Columns {
+		response.Columns[i] = models.BoardColumn{
+			Status: toStatusResponse(column.Status),
+			Tasks:  toTaskResponses(column.Tasks),
+		}
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(response)
+}
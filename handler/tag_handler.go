@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/service"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/jwt"
+)
+
+type TagHandler struct {
+	tagService service.TagService
+}
+
+func NewTagHandler(ts service.TagService) *TagHandler {
+	return &TagHandler{tagService: ts}
+}
+
+func toTagResponse(tag models.Tag) models.TagResponse {
+	return models.TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		Color:     tag.Color,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
+
+func toTagResponses(tags []models.Tag) []models.TagResponse {
+	response := make([]models.TagResponse, len(tags))
+	for i, tag := range tags {
+		response[i] = toTagResponse(tag)
+	}
+	return response
+}
+
+// CreateTag
+// @Summary      Create a new tag
+// @Description  Creates a new tag for the authenticated user. Tag names are unique per user; when no color is given one is picked from the tag name.
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload body models.CreateTagRequest true "Tag Creation Payload"
+// @Success      201 {object} models.TagResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or validation failed"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      409 {object} object{error=string} "Tag with this name already exists"
+// @Failure      500 {object} object{error=string} "Failed to create tag"
+// @Router       /tags [post]
+func (h *TagHandler) CreateTag(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	var req models.CreateTagRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate create tag request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	tag, err := h.tagService.CreateTag(userID, req)
+	if err != nil {
+		if errors.Is(err, repository.ErrTagAlreadyExists) {
+			ctx.StatusCode(iris.StatusConflict)
+			ctx.JSON(iris.Map{"error": err.Error()})
+			return
+		}
+		logger.Error().Err(err).Msg("Failed to create tag")
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": "Failed to create tag"})
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toTagResponse(*tag))
+}
+
+// GetMyTags
+// @Summary      Get all tags for the current user
+// @Description  Retrieves every tag belonging to the authenticated user, ordered by name.
+// @Tags         Tags
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} models.TagResponse
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      500 {object} object{error=string} "Could not retrieve tags"
+// @Router       /tags [get]
+func (h *TagHandler) GetMyTags(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	tags, err := h.tagService.GetTags(userID)
+	if err != nil {
+		logger.Error().Err(err).Uint("userID", userID).Msg("Failed to get tags for user")
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": "Could not retrieve tags"})
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTagResponses(tags))
+}
+
+// UpdateTag
+// @Summary      Update a tag
+// @Description  Renames and/or recolors a tag belonging to the authenticated user.
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                     true  "Tag ID"
+// @Param        payload body  models.UpdateTagRequest true  "Tag Update Payload"
+// @Success      200 {object} models.TagResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Tag not found"
+// @Failure      409 {object} object{error=string} "Tag with this name already exists"
+// @Failure      500 {object} object{error=string} "Could not update tag"
+// @Router       /tags/{id} [put]
+func (h *TagHandler) UpdateTag(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	tagID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid tag ID"})
+		return
+	}
+
+	var req models.UpdateTagRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate update tag request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	tag, err := h.tagService.UpdateTag(tagID, userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrTagAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTagNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTagAlreadyExists) {
+			ctx.StatusCode(iris.StatusConflict)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("tagID", tagID).Msg("Failed to update tag")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not update tag"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTagResponse(*tag))
+}
+
+// DeleteTag
+// @Summary      Delete a tag
+// @Description  Permanently deletes a tag belonging to the authenticated user and detaches it from every task.
+// @Tags         Tags
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Tag ID"
+// @Success      204 "No Content"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Tag not found"
+// @Failure      500 {object} object{error=string} "Could not delete tag"
+// @Router       /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	tagID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid tag ID"})
+		return
+	}
+
+	err = h.tagService.DeleteTag(tagID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTagAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTagNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("tagID", tagID).Msg("Failed to delete tag")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not delete tag"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/service"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/jwt"
+)
+
+// AddDependency
+// @Summary      Block a task by another
+// @Description  Declares that a task is blocked by another task the user can see. A blocked task can't be completed while any of its blockers is open, unless the completion is forced. Dependencies that would make a task block itself, directly or through other tasks, are refused.
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                          true  "Task ID"
+// @Param        payload body  models.AddDependencyRequest  true  "Blocking task"
+// @Success      201 {object} models.TaskResponse
+// @Failure      400 {object} object{error=string} "Invalid request format, ID or blocking task"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      409 {object} object{error=string} "Dependency already exists or would form a cycle"
+// @Failure      500 {object} object{error=string} "Could not add dependency"
+// @Router       /tasks/{id}/dependencies [post]
+func (h *TaskHandler) AddDependency(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.AddDependencyRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate add dependency request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	task, err := h.taskService.AddDependency(taskID, userID, req.BlockedByID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDependency) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrDependencyCycle) || errors.Is(err, repository.ErrDependencyExists) {
+			ctx.StatusCode(iris.StatusConflict)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to add task dependency")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not add dependency"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toTaskResponse(*task))
+}
+
+// GetDependencies
+// @Summary      Get a task's dependencies
+// @Description  Lists the tasks a task is blocked by and the tasks it blocks, leaving out those the user can't see.
+// @Tags         Tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Task ID"
+// @Success      200 {object} models.TaskDependenciesResponse
+// @Failure      400 {object} object{error=string} "Invalid task ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve dependencies"
+// @Router       /tasks/{id}/dependencies [get]
+func (h *TaskHandler) GetDependencies(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	blockedBy, blocking, err := h.taskService.GetDependencies(taskID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to retrieve task dependencies")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not retrieve dependencies"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(models.TaskDependenciesResponse{
+		BlockedBy: toTaskResponses(blockedBy),
+		Blocking:  toTaskResponses(blocking),
+	})
+}
+
+// RemoveDependency
+// @Summary      Unblock a task
+// @Description  Removes the dependency of a task on another task.
+// @Tags         Tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path  int  true  "Task ID"
+// @Param        blockerID  path  int  true  "Blocking task ID"
+// @Success      200 {object} models.TaskResponse
+// @Failure      400 {object} object{error=string} "Invalid task ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task or dependency not found"
+// @Failure      500 {object} object{error=string} "Could not remove dependency"
+// @Router       /tasks/{id}/dependencies/{blockerID} [delete]
+func (h *TaskHandler) RemoveDependency(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+	blockerID, err := ctx.Params().GetUint("blockerID")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid blocking task ID"})
+		return
+	}
+
+	task, err := h.taskService.RemoveDependency(taskID, userID, blockerID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) || errors.Is(err, repository.ErrDependencyNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to remove task dependency")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not remove dependency"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTaskResponse(*task))
+}
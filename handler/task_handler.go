@@ -2,9 +2,12 @@ package handler
 
 import (
 	"errors"
+	"math"
+	"time"
 
 	"github.com/RLRama/listario-backend/logger"
 	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/quickadd"
 	"github.com/RLRama/listario-backend/repository"
 	"github.com/RLRama/listario-backend/service"
 	"github.com/kataras/iris/v12"
@@ -20,20 +23,70 @@ func NewTaskHandler(ts service.TaskService) *TaskHandler {
 }
 
 func toTaskResponse(task models.Task) models.TaskResponse {
+	var progress *models.TaskProgress
+	if task.SubtaskCount > 0 {
+		progress = &models.TaskProgress{
+			Completed: task.CompletedSubtaskCount,
+			Total:     task.SubtaskCount,
+		}
+	}
+
+	var deletedAt *time.Time
+	if task.DeletedAt.Valid {
+		deletedAt = &task.DeletedAt.Time
+	}
+
 	return models.TaskResponse{
-		ID:        task.ID,
-		Title:     task.Title,
-		Content:   task.Content,
-		Completed: task.Completed,
-		UserID:    task.UserID,
-		CreatedAt: task.CreatedAt,
-		UpdatedAt: task.UpdatedAt,
+		ID:             task.ID,
+		Title:          task.Title,
+		Content:        task.Content,
+		Completed:      task.Completed,
+		Priority:       task.Priority,
+		DueDate:        task.DueDate,
+		AllDay:         task.AllDay,
+		TimeZone:       task.TimeZone,
+		UserID:         task.UserID,
+		WorkspaceID:    task.WorkspaceID,
+		ListID:         task.ListID,
+		ParentID:       task.ParentID,
+		Position:       task.Position,
+		AssigneeID:     task.AssigneeID,
+		StatusID:       task.StatusID,
+		Progress:       progress,
+		CommentCount:   task.CommentCount,
+		Blocked:        task.Blocked,
+		TrackedSeconds: task.TrackedSeconds,
+		RecurrenceRule: task.RecurrenceRule,
+		SeriesID:       task.SeriesID,
+		Tags:           toTagResponses(task.Tags),
+		CreatedAt:      task.CreatedAt,
+		UpdatedAt:      task.UpdatedAt,
+		DeletedAt:      deletedAt,
+	}
+}
+
+func toTaskResponses(tasks []models.Task) []models.TaskResponse {
+	response := make([]models.TaskResponse, len(tasks))
+	for i, task := range tasks {
+		response[i] = toTaskResponse(task)
 	}
+	return response
+}
+
+// isTaskInputError reports whether err means the task payload itself was
+// invalid, as opposed to a lookup or server failure.
+func isTaskInputError(err error) bool {
+	return errors.Is(err, service.ErrInvalidTimeZone) ||
+		errors.Is(err, service.ErrInvalidTags) ||
+		errors.Is(err, service.ErrInvalidList) ||
+		errors.Is(err, service.ErrInvalidStatus) ||
+		errors.Is(err, service.ErrInvalidRecurrence) ||
+		errors.Is(err, service.ErrRecurrenceNeedsDueDate)
 }
 
 // CreateTask
 // @Summary      Create a new task
-// @Description  Creates a new task for the authenticated user.
+// @Description  Creates a new task for the authenticated user. In the personal space it goes in the given list or the inbox; in the active workspace, in the given list of the workspace or none. The time zone defaults to the user's one; for all-day tasks only the calendar date of due_date is kept.
 // @Tags         Tasks
 // @Accept       json
 // @Produce      json
@@ -42,6 +95,7 @@ func toTaskResponse(task models.Task) models.TaskResponse {
 // @Success      201 {object} models.TaskResponse
 // @Failure      400 {object} object{error=string} "Invalid request format or validation failed"
 // @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Guests cannot create tasks in the workspace"
 // @Failure      500 {object} object{error=string} "Failed to create task"
 // @Router       /tasks [post]
 func (h *TaskHandler) CreateTask(ctx iris.Context) {
@@ -56,8 +110,18 @@ func (h *TaskHandler) CreateTask(ctx iris.Context) {
 		return
 	}
 
-	task, err := h.taskService.CreateTask(userID, req.Title, req.Content)
+	task, err := h.taskService.CreateTask(userID, activeWorkspaceID(ctx), req)
 	if err != nil {
+		if isTaskInputError(err) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrWorkspaceAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+			return
+		}
 		logger.Error().Err(err).Msg("Failed to create task")
 		ctx.StatusCode(iris.StatusInternalServerError)
 		ctx.JSON(iris.Map{"error": "Failed to create task"})
@@ -68,13 +132,100 @@ func (h *TaskHandler) CreateTask(ctx iris.Context) {
 	ctx.JSON(toTaskResponse(*task))
 }
 
+// QuickAddTask
+// @Summary      Quick-add a task
+// @Description  Creates a task from a single line of text such as "Pay rent tomorrow 9am #home !high every month", read in the given time zone or the user's own. Dates, times, recurrences ("every monday", "every 2 weeks"), #tags and !priorities are taken out and the rest becomes the title; tags the user doesn't have yet are created. Returns the task along with what was understood.
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload body models.QuickAddTaskRequest true "Quick-add Payload"
+// @Success      201 {object} models.QuickAddTaskResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or nothing left for a title"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Guests cannot create tasks in the workspace"
+// @Failure      500 {object} object{error=string} "Failed to create task"
+// @Router       /tasks/quick [post]
+func (h *TaskHandler) QuickAddTask(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	var req models.QuickAddTaskRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate quick-add task request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	task, parsed, err := h.taskService.QuickAddTask(userID, activeWorkspaceID(ctx), req, time.Now())
+	if err != nil {
+		if isTaskInputError(err) || errors.Is(err, service.ErrQuickAddTitle) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrWorkspaceAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+			return
+		}
+		logger.Error().Err(err).Msg("Failed to quick-add task")
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": "Failed to create task"})
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(models.QuickAddTaskResponse{
+		Task:   toTaskResponse(*task),
+		Parsed: toQuickAddParse(*parsed),
+	})
+}
+
+func toQuickAddParse(result quickadd.Result) models.QuickAddParse {
+	matches := make([]models.QuickAddMatch, len(result.Matches))
+	for i, match := range result.Matches {
+		matches[i] = models.QuickAddMatch{Kind: string(match.Kind), Text: match.Text}
+	}
+	tags := result.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return models.QuickAddParse{
+		Title:          result.Title,
+		DueDate:        result.DueDate,
+		AllDay:         result.AllDay,
+		RecurrenceRule: result.RecurrenceRule,
+		Tags:           tags,
+		Priority:       result.Priority,
+		Matches:        matches,
+	}
+}
+
 // GetMyTasks
-// @Summary      Get all tasks for the current user
-// @Description  Retrieves a list of all tasks belonging to the authenticated user.
+// @Summary      Get tasks for the current user
+// @Description  Retrieves a filtered, sorted page of the authenticated user's personal tasks, or of every task in the active workspace. Pass next_cursor back as cursor to fetch the following page. With group=due, open tasks are instead returned grouped into overdue, today, upcoming and no_due_date buckets (models.TaskBucketsResponse).
 // @Tags         Tasks
 // @Produce      json
 // @Security     BearerAuth
-// @Success      200 {array} models.TaskResponse
+// @Param        group         query  string  false  "Set to 'due' to group open tasks by due date"  Enums(due)
+// @Param        completed     query  bool    false  "Only completed or only open tasks"
+// @Param        due_from      query  string  false  "Due on or after this RFC 3339 timestamp"
+// @Param        due_to        query  string  false  "Due before this RFC 3339 timestamp"
+// @Param        created_from  query  string  false  "Created on or after this RFC 3339 timestamp"
+// @Param        created_to    query  string  false  "Created before this RFC 3339 timestamp"
+// @Param        q             query  string  false  "Case-insensitive match on title or content"
+// @Param        list_id       query  int     false  "Only tasks in this list"
+// @Param        assigned      query  string  false  "List the tasks assigned to the user instead of the ones they own"  Enums(me)
+// @Param        tag_ids       query  string  false  "Comma-separated tag IDs; tasks with any of them match"
+// @Param        include_subtasks  query  bool  false  "Include subtasks alongside top-level tasks"
+// @Param        sort          query  string  false  "Sort field; defaults to the manual order"  Enums(position, created_at, updated_at, due_date, title, priority)
+// @Param        direction     query  string  false  "Sort direction"  Enums(asc, desc)
+// @Param        cursor        query  string  false  "Cursor returned by the previous page"
+// @Param        limit         query  int     false  "Page size (1-100, default 50)"
+// @Success      200 {object} models.TaskPageResponse
+// @Failure      400 {object} object{error=string} "Invalid query parameters"
 // @Failure      401 {object} object{error=string} "Unauthorized"
 // @Failure      500 {object} object{error=string} "Could not retrieve tasks"
 // @Router       /tasks [get]
@@ -82,17 +233,114 @@ func (h *TaskHandler) GetMyTasks(ctx iris.Context) {
 	claims := jwt.Get(ctx).(*models.UserClaims)
 	userID := claims.UserID
 
-	tasks, err := h.taskService.GetTasksByUser(userID)
+	switch ctx.URLParam("group") {
+	case "":
+	case "due":
+		h.getMyTaskBuckets(ctx, userID)
+		return
+	default:
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid group, expected 'due'"})
+		return
+	}
+
+	query, err := parseTaskQuery(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid query parameters", "details": err.Error()})
+		return
+	}
+
+	query.WorkspaceID = activeWorkspaceID(ctx)
+	page, err := h.taskService.ListTasks(userID, query)
 	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+			return
+		}
 		logger.Error().Err(err).Uint("userID", userID).Msg("Failed to get tasks for user")
 		ctx.StatusCode(iris.StatusInternalServerError)
 		ctx.JSON(iris.Map{"error": "Could not retrieve tasks"})
 		return
 	}
 
-	response := make([]models.TaskResponse, len(tasks))
-	for i, task := range tasks {
-		response[i] = toTaskResponse(task)
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(models.TaskPageResponse{
+		Tasks:      toTaskResponses(page.Tasks),
+		NextCursor: page.NextCursor,
+	})
+}
+
+func (h *TaskHandler) getMyTaskBuckets(ctx iris.Context, userID uint) {
+	buckets, err := h.taskService.GetTaskBuckets(userID, activeWorkspaceID(ctx), time.Now())
+	if err != nil {
+		logger.Error().Err(err).Uint("userID", userID).Msg("Failed to get task buckets for user")
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": "Could not retrieve tasks"})
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(models.TaskBucketsResponse{
+		Overdue:   toTaskResponses(buckets.Overdue),
+		Today:     toTaskResponses(buckets.Today),
+		Upcoming:  toTaskResponses(buckets.Upcoming),
+		NoDueDate: toTaskResponses(buckets.NoDueDate),
+	})
+}
+
+// SearchTasks
+// @Summary      Search tasks
+// @Description  Full-text search over the titles and contents of the authenticated user's personal tasks, or of every task in the active workspace. Every word is matched as a prefix; results are ranked by relevance and include an HTML snippet, with the task text escaped and matches wrapped in <mark> tags.
+// @Tags         Tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q       query  string  true   "Search text"
+// @Param        limit   query  int     false  "Maximum number of results (1-50, default 20)"
+// @Param        offset  query  int     false  "Number of results to skip"
+// @Success      200 {array} models.TaskSearchResultResponse
+// @Failure      400 {object} object{error=string} "Invalid search query"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      500 {object} object{error=string} "Could not search tasks"
+// @Router       /tasks/search [get]
+func (h *TaskHandler) SearchTasks(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	limit, err := parseIntParam(ctx, "limit", 1, repository.MaxSearchLimit)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": err.Error()})
+		return
+	}
+	offset, err := parseIntParam(ctx, "offset", 0, math.MaxInt32)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": err.Error()})
+		return
+	}
+
+	results, err := h.taskService.SearchTasks(userID, activeWorkspaceID(ctx), ctx.URLParam("q"), limit, offset)
+	if err != nil {
+		if errors.Is(err, repository.ErrEmptySearchQuery) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+			return
+		}
+		logger.Error().Err(err).Uint("userID", userID).Msg("Failed to search tasks")
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": "Could not search tasks"})
+		return
+	}
+
+	response := make([]models.TaskSearchResultResponse, len(results))
+	for i, result := range results {
+		response[i] = models.TaskSearchResultResponse{
+			Task:    toTaskResponse(result.Task),
+			Rank:    result.Rank,
+			Snippet: result.Snippet,
+		}
 	}
 
 	ctx.StatusCode(iris.StatusOK)
@@ -141,9 +389,78 @@ func (h *TaskHandler) GetTask(ctx iris.Context) {
 	ctx.JSON(toTaskResponse(*task))
 }
 
+// GetTaskHistory
+// @Summary      Get a task's change history
+// @Description  Retrieves the changes made to a task, newest first, with the user who made each change and the before and after values of every changed field. Pass next_cursor back as cursor to fetch older events.
+// @Tags         Tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path   int     true   "Task ID"
+// @Param        cursor  query  string  false  "Cursor from a previous page's next_cursor"
+// @Param        limit   query  int     false  "Page size (1-100, default 50)"
+// @Success      200 {object} models.TaskHistoryResponse
+// @Failure      400 {object} object{error=string} "Invalid ID, cursor or limit"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve history"
+// @Router       /tasks/{id}/history [get]
+func (h *TaskHandler) GetTaskHistory(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	limit, err := parseIntParam(ctx, "limit", 1, repository.MaxTaskHistoryPageSize)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": err.Error()})
+		return
+	}
+
+	page, err := h.taskService.GetTaskHistory(taskID, userID, ctx.URLParam("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to retrieve task history")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not retrieve history"})
+		}
+		return
+	}
+
+	events := make([]models.TaskEventResponse, len(page.Events))
+	for i, event := range page.Events {
+		events[i] = models.TaskEventResponse{
+			ID:        event.ID,
+			TaskID:    event.TaskID,
+			ActorID:   event.ActorID,
+			Type:      event.Type,
+			Changes:   event.Changes,
+			CreatedAt: event.CreatedAt,
+		}
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(models.TaskHistoryResponse{Events: events, NextCursor: page.NextCursor})
+}
+
 // UpdateTask
 // @Summary      Update a task
-// @Description  Updates a specific task's details if it belongs to the authenticated user.
+// @Description  Updates a specific task's details if the authenticated user can edit it. A task's assignee may only change whether it is completed and its status. Setting status_id moves the task to another status of its list, completing or reopening it to match. Setting list_id moves the task and its subtasks to another of the user's lists. Completing a task completes all of its subtasks; reopening a subtask reopens the tasks above it. Completing an occurrence of a recurring task creates the next occurrence. A task blocked by open tasks can only be completed by also setting force.
 // @Tags         Tasks
 // @Accept       json
 // @Produce      json
@@ -154,6 +471,7 @@ func (h *TaskHandler) GetTask(ctx iris.Context) {
 // @Failure      400 {object} object{error=string} "Invalid request format or ID"
 // @Failure      401 {object} object{error=string} "Unauthorized or access denied"
 // @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      409 {object} object{error=string} "Task is blocked"
 // @Failure      500 {object} object{error=string} "Could not update task"
 // @Router       /tasks/{id} [put]
 func (h *TaskHandler) UpdateTask(ctx iris.Context) {
@@ -175,14 +493,20 @@ func (h *TaskHandler) UpdateTask(ctx iris.Context) {
 		return
 	}
 
-	task, err := h.taskService.UpdateTask(taskID, userID, req.Title, req.Content, req.Completed)
+	task, err := h.taskService.UpdateTask(taskID, userID, req)
 	if err != nil {
-		if errors.Is(err, service.ErrTaskAccessDenied) {
+		if isTaskInputError(err) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTaskAccessDenied) {
 			ctx.StatusCode(iris.StatusForbidden)
 			ctx.JSON(iris.Map{"error": err.Error()})
 		} else if errors.Is(err, repository.ErrTaskNotFound) {
 			ctx.StatusCode(iris.StatusNotFound)
 			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTaskBlocked) {
+			ctx.StatusCode(iris.StatusConflict)
+			ctx.JSON(iris.Map{"error": err.Error()})
 		} else {
 			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to update task")
 			ctx.StatusCode(iris.StatusInternalServerError)
@@ -197,7 +521,7 @@ func (h *TaskHandler) UpdateTask(ctx iris.Context) {
 
 // DeleteTask
 // @Summary      Delete a task
-// @Description  Deletes a specific task if it belongs to the authenticated user.
+// @Description  Moves a specific task, along with all of its subtasks, to the trash if it belongs to the authenticated user. Trashed tasks can be restored until they are purged, which happens automatically after the retention period.
 // @Tags         Tasks
 // @Produce      json
 // @Security     BearerAuth
@@ -236,3 +560,583 @@ func (h *TaskHandler) DeleteTask(ctx iris.Context) {
 
 	ctx.StatusCode(iris.StatusNoContent)
 }
+
+// BatchTasks
+// @Summary      Run bulk operations on tasks
+// @Description  Applies a list of operations (complete, uncomplete, delete, move, tag, set_priority) to many tasks in a single transaction, and reports the outcome for every task. Tasks that don't exist, aren't accessible or are blocked (unless force is set) are reported as failed and skipped; any other failure rolls the whole batch back.
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload body  models.BatchTaskRequest  true  "Operations to run, in order"
+// @Success      200 {object} models.BatchTaskResponse
+// @Failure      400 {object} object{error=string} "Invalid operation, list or tags"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      500 {object} object{error=string} "Could not run batch"
+// @Router       /tasks/batch [post]
+func (h *TaskHandler) BatchTasks(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	var req models.BatchTaskRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate batch task request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	results, err := h.taskService.BatchTasks(userID, req.Operations)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidBatchOperation) || isTaskInputError(err) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("userID", userID).Msg("Failed to run batch task operations")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not run batch"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(models.BatchTaskResponse{Results: results})
+}
+
+// GetTrash
+// @Summary      Get trashed tasks
+// @Description  Retrieves the authenticated user's deleted tasks, most recently deleted first. Subtasks deleted along with their parent are not listed separately.
+// @Tags         Tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} models.TaskResponse
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      500 {object} object{error=string} "Could not retrieve trash"
+// @Router       /tasks/trash [get]
+func (h *TaskHandler) GetTrash(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	tasks, err := h.taskService.GetTrash(userID)
+	if err != nil {
+		logger.Error().Err(err).Uint("userID", userID).Msg("Failed to retrieve trashed tasks")
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": "Could not retrieve trash"})
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTaskResponses(tasks))
+}
+
+// RestoreTask
+// @Summary      Restore a trashed task
+// @Description  Restores a deleted task along with the subtasks deleted with it. If its parent is still in the trash the task becomes a top-level task, and if its list was deleted it goes to the inbox.
+// @Tags         Tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Task ID"
+// @Success      200 {object} models.TaskResponse
+// @Failure      400 {object} object{error=string} "Invalid task ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found in the trash"
+// @Failure      500 {object} object{error=string} "Could not restore task"
+// @Router       /tasks/{id}/restore [post]
+func (h *TaskHandler) RestoreTask(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	task, err := h.taskService.RestoreTask(taskID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to restore task")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not restore task"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTaskResponse(*task))
+}
+
+// PurgeTask
+// @Summary      Permanently delete a trashed task
+// @Description  Permanently deletes a task in the trash along with all of its subtasks. This cannot be undone.
+// @Tags         Tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Task ID"
+// @Success      204 "No Content"
+// @Failure      400 {object} object{error=string} "Invalid task ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found in the trash"
+// @Failure      500 {object} object{error=string} "Could not purge task"
+// @Router       /tasks/{id}/purge [delete]
+func (h *TaskHandler) PurgeTask(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	err = h.taskService.PurgeTask(taskID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to purge task")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not purge task"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
+
+// CreateSubtask
+// @Summary      Create a subtask
+// @Description  Creates a subtask under the given task, in the parent's list. Adding an open subtask reopens the tasks above it.
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                      true  "Parent task ID"
+// @Param        payload body  models.CreateTaskRequest true  "Task Creation Payload"
+// @Success      201 {object} models.TaskResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Failed to create subtask"
+// @Router       /tasks/{id}/subtasks [post]
+func (h *TaskHandler) CreateSubtask(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	parentID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.CreateTaskRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate create subtask request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	task, err := h.taskService.CreateSubtask(parentID, userID, req)
+	if err != nil {
+		if isTaskInputError(err) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", parentID).Msg("Failed to create subtask")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Failed to create subtask"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toTaskResponse(*task))
+}
+
+// GetSubtasks
+// @Summary      Get the subtasks of a task
+// @Description  Retrieves the direct subtasks of a task, in their manual order.
+// @Tags         Tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Parent task ID"
+// @Success      200 {array} models.TaskResponse
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve subtasks"
+// @Router       /tasks/{id}/subtasks [get]
+func (h *TaskHandler) GetSubtasks(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	parentID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	tasks, err := h.taskService.GetSubtasks(parentID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", parentID).Msg("Failed to get subtasks")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not retrieve subtasks"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTaskResponses(tasks))
+}
+
+// ReorderSubtasks
+// @Summary      Reorder the subtasks of a task
+// @Description  Sets the manual order of a task's direct subtasks. The payload must list every subtask exactly once.
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                            true  "Parent task ID"
+// @Param        payload body  models.ReorderSubtasksRequest  true  "Subtask IDs in their new order"
+// @Success      200 {array} models.TaskResponse
+// @Failure      400 {object} object{error=string} "Invalid request format, ID or order"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not reorder subtasks"
+// @Router       /tasks/{id}/subtasks/order [put]
+func (h *TaskHandler) ReorderSubtasks(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	parentID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.ReorderSubtasksRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate reorder subtasks request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	tasks, err := h.taskService.ReorderSubtasks(parentID, userID, req.TaskIDs)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSubtaskOrder) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", parentID).Msg("Failed to reorder subtasks")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not reorder subtasks"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTaskResponses(tasks))
+}
+
+// MoveTask
+// @Summary      Move a task in the manual order
+// @Description  Places a task between two of its siblings (tasks with the same parent). before_id is the task that should come right before it and after_id the one right after it; give only one of them to move the task right next to that neighbour. Only the moved task is rewritten in most cases.
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                     true  "Task ID"
+// @Param        payload body  models.MoveTaskRequest  true  "New neighbours"
+// @Success      200 {object} models.TaskResponse
+// @Failure      400 {object} object{error=string} "Invalid ID or neighbours"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not move task"
+// @Router       /tasks/{id}/move [patch]
+func (h *TaskHandler) MoveTask(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.MoveTaskRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate move task request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	task, err := h.taskService.MoveTask(taskID, userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMove) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to move task")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not move task"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTaskResponse(*task))
+}
+
+// SetRecurrence
+// @Summary      Make a task recur
+// @Description  Sets an RFC 5545 RRULE (e.g. FREQ=WEEKLY;BYDAY=MO,WE or FREQ=MONTHLY;BYDAY=2TU) on a task with a due date, or changes the rule of the series it belongs to. Completing an occurrence creates the next one with its due date advanced.
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                          true  "Task ID"
+// @Param        payload body  models.SetRecurrenceRequest  true  "Recurrence rule"
+// @Success      200 {object} models.TaskResponse
+// @Failure      400 {object} object{error=string} "Invalid rule, ID or missing due date"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not set recurrence"
+// @Router       /tasks/{id}/recurrence [put]
+func (h *TaskHandler) SetRecurrence(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.SetRecurrenceRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate set recurrence request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	task, err := h.taskService.SetRecurrence(taskID, userID, req.Rule)
+	if err != nil {
+		if isTaskInputError(err) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to set task recurrence")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not set recurrence"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTaskResponse(*task))
+}
+
+// StopRecurrence
+// @Summary      Stop a recurring series
+// @Description  Removes the recurrence rule from every open occurrence of the task's series, so completing them no longer creates new ones.
+// @Tags         Tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Task ID"
+// @Success      200 {object} models.TaskResponse
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not stop recurrence"
+// @Router       /tasks/{id}/recurrence [delete]
+func (h *TaskHandler) StopRecurrence(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	task, err := h.taskService.StopRecurrence(taskID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to stop task recurrence")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not stop recurrence"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTaskResponse(*task))
+}
+
+// AssignTask
+// @Summary      Assign a task
+// @Description  Delegates a task to a user who can already see it, such as its owner or someone it is shared with. The assignee can then view the task and complete or reopen it.
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                       true  "Task ID"
+// @Param        payload body  models.AssignTaskRequest  true  "Assignee"
+// @Success      200 {object} models.TaskResponse
+// @Failure      400 {object} object{error=string} "Invalid request format, ID or assignee"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not assign task"
+// @Router       /tasks/{id}/assignee [put]
+func (h *TaskHandler) AssignTask(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.AssignTaskRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate assign task request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	task, err := h.taskService.AssignTask(taskID, userID, req.UserID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAssignee) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to assign task")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not assign task"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTaskResponse(*task))
+}
+
+// UnassignTask
+// @Summary      Unassign a task
+// @Description  Removes a task's assignee. Editors of the task and the assignee themselves can do this.
+// @Tags         Tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Task ID"
+// @Success      200 {object} models.TaskResponse
+// @Failure      400 {object} object{error=string} "Invalid task ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not unassign task"
+// @Router       /tasks/{id}/assignee [delete]
+func (h *TaskHandler) UnassignTask(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	task, err := h.taskService.UnassignTask(taskID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to unassign task")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not unassign task"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTaskResponse(*task))
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/kataras/iris/v12"
+)
+
+// parseTaskQuery reads the filtering, sorting and pagination parameters
+// shared by the task listing endpoints.
+func parseTaskQuery(ctx iris.Context) (repository.TaskQuery, error) {
+	query := repository.TaskQuery{
+		Text:      ctx.URLParamTrim("q"),
+		Sort:      repository.TaskSort(ctx.URLParam("sort")),
+		Direction: repository.SortDirection(ctx.URLParam("direction")),
+		Cursor:    ctx.URLParam("cursor"),
+	}
+	query.TopLevelOnly = !ctx.URLParamBoolDefault("include_subtasks", false)
+
+	if !query.Sort.IsValid() {
+		return query, fmt.Errorf("invalid sort %q", query.Sort)
+	}
+	if !query.Direction.IsValid() {
+		return query, fmt.Errorf("invalid direction %q, expected 'asc' or 'desc'", query.Direction)
+	}
+
+	if value := ctx.URLParam("completed"); value != "" {
+		completed, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, fmt.Errorf("invalid completed %q, expected a boolean", value)
+		}
+		query.Completed = &completed
+	}
+
+	switch value := ctx.URLParam("assigned"); value {
+	case "":
+	case "me":
+		query.AssignedToMe = true
+	default:
+		return query, fmt.Errorf("invalid assigned %q, expected 'me'", value)
+	}
+
+	if value := ctx.URLParam("list_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || id == 0 {
+			return query, fmt.Errorf("invalid list_id %q", value)
+		}
+		listID := uint(id)
+		query.ListID = &listID
+	}
+
+	if value := ctx.URLParam("tag_ids"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+			if err != nil || id == 0 {
+				return query, fmt.Errorf("invalid tag_ids %q, expected comma-separated IDs", value)
+			}
+			query.TagIDs = append(query.TagIDs, uint(id))
+		}
+	}
+
+	limit, err := parseIntParam(ctx, "limit", 1, repository.MaxTaskPageSize)
+	if err != nil {
+		return query, err
+	}
+	query.Limit = limit
+
+	timeParams := []struct {
+		name   string
+		target **time.Time
+	}{
+		{"due_from", &query.DueFrom},
+		{"due_to", &query.DueTo},
+		{"created_from", &query.CreatedFrom},
+		{"created_to", &query.CreatedTo},
+	}
+	for _, param := range timeParams {
+		value := ctx.URLParam(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, fmt.Errorf("invalid %s %q, expected an RFC 3339 timestamp", param.name, value)
+		}
+		*param.target = &t
+	}
+
+	return query, nil
+}
+
+// parseIntParam reads an optional integer query parameter within [min, max],
+// returning 0 when it is absent.
+func parseIntParam(ctx iris.Context, name string, min, max int) (int, error) {
+	value := ctx.URLParam(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("invalid %s %q, expected %d to %d", name, value, min, max)
+	}
+	return n, nil
+}
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/service"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/jwt"
+)
+
+type TemplateHandler struct {
+	templateService service.TemplateService
+}
+
+func NewTemplateHandler(ts service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{templateService: ts}
+}
+
+func toTemplateTaskResponse(task models.TemplateTask) models.TemplateTaskResponse {
+	response := models.TemplateTaskResponse{
+		Title:            task.Title,
+		Content:          task.Content,
+		Priority:         task.Priority,
+		AllDay:           task.AllDay,
+		DueOffsetMinutes: task.DueOffsetMinutes,
+		Tags:             task.Tags,
+		Subtasks:         make([]models.TemplateTaskResponse, len(task.Subtasks)),
+	}
+	if response.Tags == nil {
+		response.Tags = []string{}
+	}
+	for i, subtask := range task.Subtasks {
+		response.Subtasks[i] = toTemplateTaskResponse(subtask)
+	}
+	return response
+}
+
+func toTemplateResponse(template models.TaskTemplate) models.TemplateResponse {
+	return models.TemplateResponse{
+		ID:          template.ID,
+		Name:        template.Name,
+		UserID:      template.UserID,
+		WorkspaceID: template.WorkspaceID,
+		Root:        toTemplateTaskResponse(template.Root),
+		CreatedAt:   template.CreatedAt,
+		UpdatedAt:   template.UpdatedAt,
+	}
+}
+
+// SaveTemplate
+// @Summary      Save a task as a template
+// @Description  Captures a task the authenticated user can access, with its subtasks, tags and priorities, as a template in the active workspace or their personal space. Due dates are kept relative to when the task was created.
+// @Tags         Templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                         true  "Task ID"
+// @Param        payload body  models.SaveTemplateRequest  true  "Template Payload"
+// @Success      201 {object} models.TemplateResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Failed to save template"
+// @Router       /tasks/{id}/template [post]
+func (h *TemplateHandler) SaveTemplate(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.SaveTemplateRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate save template request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.SaveTemplate(taskID, userID, activeWorkspaceID(ctx), req)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) || errors.Is(err, service.ErrWorkspaceAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to save task as template")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Failed to save template"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toTemplateResponse(*template))
+}
+
+// GetTemplates
+// @Summary      Get my templates
+// @Description  Retrieves the templates in the active workspace, or in the authenticated user's personal space, by name.
+// @Tags         Templates
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} models.TemplateResponse
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      500 {object} object{error=string} "Could not retrieve templates"
+// @Router       /templates [get]
+func (h *TemplateHandler) GetTemplates(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	templates, err := h.templateService.GetTemplates(userID, activeWorkspaceID(ctx))
+	if err != nil {
+		logger.Error().Err(err).Uint("userID", userID).Msg("Failed to get templates for user")
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": "Could not retrieve templates"})
+		return
+	}
+
+	response := make([]models.TemplateResponse, len(templates))
+	for i, template := range templates {
+		response[i] = toTemplateResponse(template)
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(response)
+}
+
+// GetTemplate
+// @Summary      Get a template
+// @Description  Retrieves a template the authenticated user can access, with its whole task tree.
+// @Tags         Templates
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Template ID"
+// @Success      200 {object} models.TemplateResponse
+// @Failure      400 {object} object{error=string} "Invalid template ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Template not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve template"
+// @Router       /templates/{id} [get]
+func (h *TemplateHandler) GetTemplate(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	templateID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid template ID"})
+		return
+	}
+
+	template, err := h.templateService.GetTemplate(templateID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTemplateAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTemplateNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("templateID", templateID).Msg("Failed to get template")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not retrieve template"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTemplateResponse(*template))
+}
+
+// DeleteTemplate
+// @Summary      Delete a template
+// @Description  Deletes a template created by the authenticated user, or any template of a workspace they administer. Tasks created from it are kept.
+// @Tags         Templates
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Template ID"
+// @Success      204 "No Content"
+// @Failure      400 {object} object{error=string} "Invalid template ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Template not found"
+// @Failure      500 {object} object{error=string} "Could not delete template"
+// @Router       /templates/{id} [delete]
+func (h *TemplateHandler) DeleteTemplate(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	templateID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid template ID"})
+		return
+	}
+
+	err = h.templateService.DeleteTemplate(templateID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTemplateAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTemplateNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("templateID", templateID).Msg("Failed to delete template")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not delete template"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
+
+// InstantiateTemplate
+// @Summary      Create tasks from a template
+// @Description  Creates the template's whole task tree at once, in the template's workspace or the authenticated user's personal space, and returns its top-level task. Due dates count from start_at, or now; tags the user doesn't have yet are created.
+// @Tags         Templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                                true  "Template ID"
+// @Param        payload body  models.InstantiateTemplateRequest  true  "Instantiation Payload"
+// @Success      201 {object} models.TaskResponse
+// @Failure      400 {object} object{error=string} "Invalid request format, ID, list or time zone"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Template not found"
+// @Failure      500 {object} object{error=string} "Failed to create tasks"
+// @Router       /templates/{id}/instantiate [post]
+func (h *TemplateHandler) InstantiateTemplate(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	templateID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid template ID"})
+		return
+	}
+
+	var req models.InstantiateTemplateRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate instantiate template request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	task, err := h.templateService.InstantiateTemplate(templateID, userID, req, time.Now())
+	if err != nil {
+		if isTaskInputError(err) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTemplateAccessDenied) || errors.Is(err, service.ErrWorkspaceAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTemplateNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("templateID", templateID).Msg("Failed to instantiate template")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Failed to create tasks"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toTaskResponse(*task))
+}
@@ -0,0 +1,472 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/service"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/jwt"
+)
+
+// reportDateLayout is the format of the days in time reports.
+const reportDateLayout = "2006-01-02"
+
+type TimeEntryHandler struct {
+	timeEntryService service.TimeEntryService
+}
+
+func NewTimeEntryHandler(ts service.TimeEntryService) *TimeEntryHandler {
+	return &TimeEntryHandler{timeEntryService: ts}
+}
+
+func toTimeEntryResponse(entry models.TimeEntry, now time.Time) models.TimeEntryResponse {
+	return models.TimeEntryResponse{
+		ID:              entry.ID,
+		TaskID:          entry.TaskID,
+		UserID:          entry.UserID,
+		StartedAt:       entry.StartedAt,
+		EndedAt:         entry.EndedAt,
+		Running:         entry.EndedAt == nil,
+		DurationSeconds: int64(entry.Duration(now).Seconds()),
+		Note:            entry.Note,
+		CreatedAt:       entry.CreatedAt,
+		UpdatedAt:       entry.UpdatedAt,
+	}
+}
+
+func toTimeReportResponse(report service.TimeReport) models.TimeReportResponse {
+	response := models.TimeReportResponse{
+		From:         report.First.Format(reportDateLayout),
+		To:           report.Last.Format(reportDateLayout),
+		TimeZone:     report.TimeZone,
+		TotalSeconds: int64(report.Total.Seconds()),
+		Days:         make([]models.TimeReportDay, len(report.Days)),
+	}
+	for i, day := range report.Days {
+		tasks := make([]models.TimeReportTask, len(day.Tasks))
+		for j, task := range day.Tasks {
+			tasks[j] = models.TimeReportTask{
+				TaskID:  task.Task.ID,
+				Title:   task.Task.Title,
+				Seconds: int64(task.Total.Seconds()),
+			}
+		}
+		response.Days[i] = models.TimeReportDay{
+			Date:    day.Date.Format(reportDateLayout),
+			Seconds: int64(day.Total.Seconds()),
+			Tasks:   tasks,
+		}
+	}
+	return response
+}
+
+// StartTimer
+// @Summary      Start a timer on a task
+// @Description  Starts tracking the authenticated user's time on a task they may complete. Their timer running on any other task is stopped, since each user runs one timer at a time; a timer already running on this task is returned as it is.
+// @Tags         Time Tracking
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                       true   "Task ID"
+// @Param        payload body  models.StartTimerRequest  false  "Timer Payload"
+// @Success      200 {object} models.TimeEntryResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      409 {object} object{error=string} "Another timer was started at the same time"
+// @Failure      500 {object} object{error=string} "Failed to start timer"
+// @Router       /tasks/{id}/timer/start [post]
+func (h *TimeEntryHandler) StartTimer(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.StartTimerRequest
+	if err := ctx.ReadJSON(&req); err != nil && !iris.IsErrEmptyJSON(err) {
+		logger.Error().Err(err).Msg("Failed to read or validate start timer request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	entry, err := h.timeEntryService.StartTimer(taskID, userID, req, now)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTimerRunning) {
+			ctx.StatusCode(iris.StatusConflict)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to start timer")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Failed to start timer"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTimeEntryResponse(*entry, now))
+}
+
+// StopTimer
+// @Summary      Stop the timer on a task
+// @Description  Stops the authenticated user's timer running on a task, keeping the time tracked as an entry.
+// @Tags         Time Tracking
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Task ID"
+// @Success      200 {object} models.TimeEntryResponse
+// @Failure      400 {object} object{error=string} "Invalid task ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      409 {object} object{error=string} "No timer is running on the task"
+// @Failure      500 {object} object{error=string} "Failed to stop timer"
+// @Router       /tasks/{id}/timer/stop [post]
+func (h *TimeEntryHandler) StopTimer(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	now := time.Now()
+	entry, err := h.timeEntryService.StopTimer(taskID, userID, now)
+	if err != nil {
+		if errors.Is(err, service.ErrNoRunningTimer) {
+			ctx.StatusCode(iris.StatusConflict)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to stop timer")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Failed to stop timer"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTimeEntryResponse(*entry, now))
+}
+
+// GetRunningTimer
+// @Summary      Get my running timer
+// @Description  Retrieves the authenticated user's running timer, if any.
+// @Tags         Time Tracking
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} models.TimeEntryResponse
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      404 {object} object{error=string} "No timer is running"
+// @Failure      500 {object} object{error=string} "Could not retrieve timer"
+// @Router       /time-entries/running [get]
+func (h *TimeEntryHandler) GetRunningTimer(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	entry, err := h.timeEntryService.GetRunningTimer(userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTimeEntryNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": "no timer is running"})
+		} else {
+			logger.Error().Err(err).Uint("userID", userID).Msg("Failed to get running timer")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not retrieve timer"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTimeEntryResponse(*entry, time.Now()))
+}
+
+// CreateTimeEntry
+// @Summary      Add a time entry to a task
+// @Description  Records time the authenticated user already spent on a task they may complete. Entries must end after they start, and not in the future.
+// @Tags         Time Tracking
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                            true  "Task ID"
+// @Param        payload body  models.CreateTimeEntryRequest  true  "Time Entry Payload"
+// @Success      201 {object} models.TimeEntryResponse
+// @Failure      400 {object} object{error=string} "Invalid request format, ID or times"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Failed to create time entry"
+// @Router       /tasks/{id}/time-entries [post]
+func (h *TimeEntryHandler) CreateTimeEntry(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.CreateTimeEntryRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate create time entry request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	entry, err := h.timeEntryService.CreateTimeEntry(taskID, userID, req, now)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTimeEntry) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to create time entry")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Failed to create time entry"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toTimeEntryResponse(*entry, now))
+}
+
+// GetTimeEntries
+// @Summary      Get a task's time entries
+// @Description  Retrieves the time everyone tracked on a task the authenticated user can access, newest first, including running timers.
+// @Tags         Time Tracking
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Task ID"
+// @Success      200 {array} models.TimeEntryResponse
+// @Failure      400 {object} object{error=string} "Invalid task ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Task not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve time entries"
+// @Router       /tasks/{id}/time-entries [get]
+func (h *TimeEntryHandler) GetTimeEntries(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	taskID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid task ID"})
+		return
+	}
+
+	entries, err := h.timeEntryService.GetTimeEntries(taskID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTaskNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("taskID", taskID).Msg("Failed to get time entries for task")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not retrieve time entries"})
+		}
+		return
+	}
+
+	now := time.Now()
+	response := make([]models.TimeEntryResponse, len(entries))
+	for i, entry := range entries {
+		response[i] = toTimeEntryResponse(entry, now)
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(response)
+}
+
+// UpdateTimeEntry
+// @Summary      Update a time entry
+// @Description  Changes the times or note of an entry tracked by the authenticated user. Setting ended_at on a running timer stops it.
+// @Tags         Time Tracking
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                            true  "Time Entry ID"
+// @Param        payload body  models.UpdateTimeEntryRequest  true  "Time Entry Update Payload"
+// @Success      200 {object} models.TimeEntryResponse
+// @Failure      400 {object} object{error=string} "Invalid request format, ID or times"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Time entry not found"
+// @Failure      500 {object} object{error=string} "Failed to update time entry"
+// @Router       /time-entries/{id} [put]
+func (h *TimeEntryHandler) UpdateTimeEntry(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	entryID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid time entry ID"})
+		return
+	}
+
+	var req models.UpdateTimeEntryRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate update time entry request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	entry, err := h.timeEntryService.UpdateTimeEntry(entryID, userID, req, now)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTimeEntry) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTimeEntryAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTimeEntryNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("entryID", entryID).Msg("Failed to update time entry")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Failed to update time entry"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTimeEntryResponse(*entry, now))
+}
+
+// DeleteTimeEntry
+// @Summary      Delete a time entry
+// @Description  Deletes an entry tracked by the authenticated user, including a running timer.
+// @Tags         Time Tracking
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Time Entry ID"
+// @Success      204 "No Content"
+// @Failure      400 {object} object{error=string} "Invalid time entry ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Time entry not found"
+// @Failure      500 {object} object{error=string} "Could not delete time entry"
+// @Router       /time-entries/{id} [delete]
+func (h *TimeEntryHandler) DeleteTimeEntry(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	entryID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid time entry ID"})
+		return
+	}
+
+	err = h.timeEntryService.DeleteTimeEntry(entryID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTimeEntryAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else if errors.Is(err, repository.ErrTimeEntryNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("entryID", entryID).Msg("Failed to delete time entry")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not delete time entry"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
+
+// GetTimeReport
+// @Summary      Get my time report
+// @Description  Totals the time the authenticated user tracked on each day from `from` to `to`, inclusive, broken down by task. Days are calendar days in time_zone or the user's own, and running timers count up to now. Defaults to the last 7 days; at most 366 days are covered.
+// @Tags         Time Tracking
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from       query  string  false  "First day (YYYY-MM-DD)"
+// @Param        to         query  string  false  "Last day (YYYY-MM-DD)"
+// @Param        time_zone  query  string  false  "IANA time zone"
+// @Success      200 {object} models.TimeReportResponse
+// @Failure      400 {object} object{error=string} "Invalid period or time zone"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      500 {object} object{error=string} "Could not build time report"
+// @Router       /time-entries/report [get]
+func (h *TimeEntryHandler) GetTimeReport(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	first, err := parseReportDate(ctx.URLParam("from"))
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid from, expected YYYY-MM-DD"})
+		return
+	}
+	last, err := parseReportDate(ctx.URLParam("to"))
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid to, expected YYYY-MM-DD"})
+		return
+	}
+
+	report, err := h.timeEntryService.GetTimeReport(userID, first, last, ctx.URLParam("time_zone"), time.Now())
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReportPeriod) || errors.Is(err, service.ErrInvalidTimeZone) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.JSON(iris.Map{"error": err.Error()})
+		} else {
+			logger.Error().Err(err).Uint("userID", userID).Msg("Failed to build time report")
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.JSON(iris.Map{"error": "Could not build time report"})
+		}
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toTimeReportResponse(*report))
+}
+
+// parseReportDate reads an optional YYYY-MM-DD day.
+func parseReportDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(reportDateLayout, value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
@@ -45,7 +45,7 @@ func (h *UserHandler) Register(ctx iris.Context) {
 		return
 	}
 
-	user, err := h.userService.Register(req.Username, req.Email, req.Password)
+	user, err := h.userService.Register(req.Username, req.Email, req.Password, req.TimeZone)
 	if err != nil {
 		if errors.Is(err, repository.ErrUserAlreadyExists) {
 			ctx.StatusCode(iris.StatusConflict)
@@ -59,11 +59,13 @@ func (h *UserHandler) Register(ctx iris.Context) {
 	}
 
 	response := models.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:                user.ID,
+		Username:          user.Username,
+		Email:             user.Email,
+		TimeZone:          user.TimeZone,
+		ActiveWorkspaceID: user.ActiveWorkspaceID,
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
 	}
 
 	ctx.StatusCode(iris.StatusCreated)
@@ -136,11 +138,13 @@ func (h *UserHandler) GetMyDetails(ctx iris.Context) {
 	}
 
 	response := models.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:                user.ID,
+		Username:          user.Username,
+		Email:             user.Email,
+		TimeZone:          user.TimeZone,
+		ActiveWorkspaceID: user.ActiveWorkspaceID,
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
 	}
 
 	ctx.StatusCode(iris.StatusOK)
@@ -149,7 +153,7 @@ func (h *UserHandler) GetMyDetails(ctx iris.Context) {
 
 // UpdateMyDetails
 // @Summary      Update current user details
-// @Description  Updates the username and/or email for the currently authenticated user.
+// @Description  Updates the username, email and/or time zone for the currently authenticated user.
 // @Tags         Users
 // @Accept       json
 // @Produce      json
@@ -172,7 +176,7 @@ func (h *UserHandler) UpdateMyDetails(ctx iris.Context) {
 		return
 	}
 
-	user, err := h.userService.UpdateUserDetails(claims.UserID, req.Username, req.Email)
+	user, err := h.userService.UpdateUserDetails(claims.UserID, req.Username, req.Email, req.TimeZone)
 	if err != nil {
 		if errors.Is(err, repository.ErrUserAlreadyExists) {
 			ctx.StatusCode(iris.StatusConflict)
@@ -187,11 +191,13 @@ func (h *UserHandler) UpdateMyDetails(ctx iris.Context) {
 	}
 
 	response := models.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:                user.ID,
+		Username:          user.Username,
+		Email:             user.Email,
+		TimeZone:          user.TimeZone,
+		ActiveWorkspaceID: user.ActiveWorkspaceID,
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
 	}
 
 	ctx.StatusCode(iris.StatusOK)
@@ -245,6 +251,47 @@ func (h *UserHandler) RefreshToken(ctx iris.Context) {
 	ctx.JSON(newTokenPair)
 }
 
+// SwitchWorkspace
+// @Summary      Switch the active workspace
+// @Description  Makes a workspace the authenticated user is a member of their active one, or their personal space when workspace_id is left out, and returns tokens for it. Requests use the active workspace unless they send an X-Workspace header.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload  body      models.SwitchWorkspaceRequest  true  "Workspace to switch to"
+// @Success      200      {object}  jwt.TokenPair         "A new pair of access and refresh tokens"
+// @Failure      400      {object}  object{error=string}  "Invalid request format"
+// @Failure      401      {object}  object{error=string}  "Unauthorized"
+// @Failure      403      {object}  object{error=string}  "Not a member of the workspace"
+// @Failure      500      {object}  object{error=string}  "Could not switch workspace"
+// @Router       /users/me/workspace [put]
+func (h *UserHandler) SwitchWorkspace(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+
+	var req models.SwitchWorkspaceRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "invalid request format"})
+		return
+	}
+
+	tokenPair, err := h.userService.SwitchWorkspace(claims.UserID, req.WorkspaceID)
+	if err != nil {
+		if errors.Is(err, service.ErrWorkspaceAccessDenied) {
+			ctx.StatusCode(iris.StatusForbidden)
+			ctx.JSON(iris.Map{"error": err.Error()})
+			return
+		}
+		logger.Error().Err(err).Uint("userID", claims.UserID).Msg("Failed to switch workspace")
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": "could not switch workspace"})
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(tokenPair)
+}
+
 // Logout
 // @Summary      Log out the current user
 // @Description  Invalidates the current user's JWT, effectively logging them out.
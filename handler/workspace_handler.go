@@ -0,0 +1,591 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/service"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/jwt"
+)
+
+const (
+	// WorkspaceHeader selects the workspace a request works in, overriding the
+	// active workspace of the token. "personal" selects the personal space.
+	WorkspaceHeader = "X-Workspace"
+
+	workspaceContextKey = "workspaceID"
+)
+
+type WorkspaceHandler struct {
+	workspaceService service.WorkspaceService
+}
+
+func NewWorkspaceHandler(ws service.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{workspaceService: ws}
+}
+
+func toWorkspaceResponse(member models.WorkspaceMember) models.WorkspaceResponse {
+	return models.WorkspaceResponse{
+		ID:        member.Workspace.ID,
+		Name:      member.Workspace.Name,
+		OwnerID:   member.Workspace.OwnerID,
+		Role:      member.Role,
+		CreatedAt: member.Workspace.CreatedAt,
+		UpdatedAt: member.Workspace.UpdatedAt,
+	}
+}
+
+func toWorkspaceMemberResponse(member models.WorkspaceMember) models.WorkspaceMemberResponse {
+	return models.WorkspaceMemberResponse{
+		UserID:    member.UserID,
+		Username:  member.User.Username,
+		Email:     member.User.Email,
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt,
+	}
+}
+
+func toInvitationResponse(invitation models.WorkspaceInvitation, withToken bool) models.InvitationResponse {
+	response := models.InvitationResponse{
+		ID:            invitation.ID,
+		WorkspaceID:   invitation.WorkspaceID,
+		WorkspaceName: invitation.Workspace.Name,
+		Email:         invitation.Email,
+		Role:          invitation.Role,
+		InvitedByID:   invitation.InvitedByID,
+		Status:        invitation.Status,
+		ExpiresAt:     invitation.ExpiresAt,
+		CreatedAt:     invitation.CreatedAt,
+	}
+	if withToken {
+		response.Token = invitation.Token
+	}
+	return response
+}
+
+func toInvitationResponses(invitations []models.WorkspaceInvitation, withToken bool) []models.InvitationResponse {
+	responses := make([]models.InvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		responses[i] = toInvitationResponse(invitation, withToken)
+	}
+	return responses
+}
+
+// ResolveWorkspace is a middleware that settles which workspace the request
+// works in, from the X-Workspace header or else the token's active workspace,
+// and checks that the user is a member of it.
+func (h *WorkspaceHandler) ResolveWorkspace(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+
+	workspaceID := claims.WorkspaceID
+	if header := ctx.GetHeader(WorkspaceHeader); header == "personal" {
+		workspaceID = nil
+	} else if header != "" {
+		id, err := strconv.ParseUint(header, 10, 64)
+		if err != nil || id == 0 {
+			ctx.StopWithJSON(iris.StatusBadRequest, iris.Map{"error": "Invalid " + WorkspaceHeader + " header"})
+			return
+		}
+		selected := uint(id)
+		workspaceID = &selected
+	}
+
+	if workspaceID != nil {
+		if _, err := h.workspaceService.GetWorkspace(*workspaceID, claims.UserID); err != nil {
+			if errors.Is(err, service.ErrWorkspaceAccessDenied) {
+				ctx.StopWithJSON(iris.StatusForbidden, iris.Map{"error": err.Error()})
+			} else if errors.Is(err, repository.ErrWorkspaceNotFound) {
+				ctx.StopWithJSON(iris.StatusNotFound, iris.Map{"error": err.Error()})
+			} else {
+				logger.Error().Err(err).Uint("workspaceID", *workspaceID).Msg("Failed to resolve workspace")
+				ctx.StopWithJSON(iris.StatusInternalServerError, iris.Map{"error": "Could not resolve workspace"})
+			}
+			return
+		}
+		ctx.Values().Set(workspaceContextKey, *workspaceID)
+	}
+	ctx.Next()
+}
+
+// activeWorkspaceID returns the workspace ResolveWorkspace settled on, or nil
+// for the personal space.
+func activeWorkspaceID(ctx iris.Context) *uint {
+	if id, ok := ctx.Values().Get(workspaceContextKey).(uint); ok {
+		return &id
+	}
+	return nil
+}
+
+// readWorkspaceID reads the workspace ID from the path, writing a 400 response
+// when it is invalid.
+func readWorkspaceID(ctx iris.Context) (uint, bool) {
+	workspaceID, err := ctx.Params().GetUint("id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid workspace ID"})
+		return 0, false
+	}
+	return workspaceID, true
+}
+
+// writeWorkspaceError maps the errors shared by the workspace endpoints to a
+// response, logging and hiding anything unexpected behind message.
+func writeWorkspaceError(ctx iris.Context, err error, message string) {
+	if errors.Is(err, service.ErrWorkspaceAccessDenied) {
+		ctx.StatusCode(iris.StatusForbidden)
+		ctx.JSON(iris.Map{"error": err.Error()})
+	} else if errors.Is(err, repository.ErrWorkspaceNotFound) || errors.Is(err, repository.ErrMemberNotFound) ||
+		errors.Is(err, repository.ErrInvitationNotFound) {
+		ctx.StatusCode(iris.StatusNotFound)
+		ctx.JSON(iris.Map{"error": err.Error()})
+	} else if errors.Is(err, service.ErrCannotChangeOwner) || errors.Is(err, repository.ErrAlreadyMember) {
+		ctx.StatusCode(iris.StatusConflict)
+		ctx.JSON(iris.Map{"error": err.Error()})
+	} else if errors.Is(err, service.ErrInvitationExpired) {
+		ctx.StatusCode(iris.StatusGone)
+		ctx.JSON(iris.Map{"error": err.Error()})
+	} else {
+		logger.Error().Err(err).Msg(message)
+		ctx.StatusCode(iris.StatusInternalServerError)
+		ctx.JSON(iris.Map{"error": message})
+	}
+}
+
+// CreateWorkspace
+// @Summary      Create a workspace
+// @Description  Creates a workspace owned by the authenticated user.
+// @Tags         Workspaces
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload body  models.CreateWorkspaceRequest  true  "Workspace Payload"
+// @Success      201 {object} models.WorkspaceResponse
+// @Failure      400 {object} object{error=string} "Invalid request format"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      500 {object} object{error=string} "Failed to create workspace"
+// @Router       /workspaces [post]
+func (h *WorkspaceHandler) CreateWorkspace(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	var req models.CreateWorkspaceRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate create workspace request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	member, err := h.workspaceService.CreateWorkspace(userID, req)
+	if err != nil {
+		writeWorkspaceError(ctx, err, "Failed to create workspace")
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toWorkspaceResponse(*member))
+}
+
+// GetMyWorkspaces
+// @Summary      Get my workspaces
+// @Description  Lists the workspaces the authenticated user is a member of, with their role in each.
+// @Tags         Workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} models.WorkspaceResponse
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      500 {object} object{error=string} "Could not retrieve workspaces"
+// @Router       /workspaces [get]
+func (h *WorkspaceHandler) GetMyWorkspaces(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	userID := claims.UserID
+
+	members, err := h.workspaceService.GetWorkspaces(userID)
+	if err != nil {
+		writeWorkspaceError(ctx, err, "Could not retrieve workspaces")
+		return
+	}
+
+	response := make([]models.WorkspaceResponse, len(members))
+	for i, member := range members {
+		response[i] = toWorkspaceResponse(member)
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(response)
+}
+
+// GetWorkspace
+// @Summary      Get a workspace
+// @Description  Retrieves a workspace the authenticated user is a member of.
+// @Tags         Workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Workspace ID"
+// @Success      200 {object} models.WorkspaceResponse
+// @Failure      400 {object} object{error=string} "Invalid workspace ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Workspace not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve workspace"
+// @Router       /workspaces/{id} [get]
+func (h *WorkspaceHandler) GetWorkspace(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	workspaceID, ok := readWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+
+	member, err := h.workspaceService.GetWorkspace(workspaceID, claims.UserID)
+	if err != nil {
+		writeWorkspaceError(ctx, err, "Could not retrieve workspace")
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toWorkspaceResponse(*member))
+}
+
+// UpdateWorkspace
+// @Summary      Rename a workspace
+// @Description  Renames a workspace. Only its owner and admins can do this.
+// @Tags         Workspaces
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int                            true  "Workspace ID"
+// @Param        payload body  models.UpdateWorkspaceRequest  true  "Workspace Update Payload"
+// @Success      200 {object} models.WorkspaceResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Workspace not found"
+// @Failure      500 {object} object{error=string} "Could not update workspace"
+// @Router       /workspaces/{id} [put]
+func (h *WorkspaceHandler) UpdateWorkspace(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	workspaceID, ok := readWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateWorkspaceRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate update workspace request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	member, err := h.workspaceService.UpdateWorkspace(workspaceID, claims.UserID, req)
+	if err != nil {
+		writeWorkspaceError(ctx, err, "Could not update workspace")
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toWorkspaceResponse(*member))
+}
+
+// GetMembers
+// @Summary      Get a workspace's members
+// @Description  Lists the members of a workspace the authenticated user belongs to, with their roles.
+// @Tags         Workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Workspace ID"
+// @Success      200 {array} models.WorkspaceMemberResponse
+// @Failure      400 {object} object{error=string} "Invalid workspace ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Workspace not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve members"
+// @Router       /workspaces/{id}/members [get]
+func (h *WorkspaceHandler) GetMembers(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	workspaceID, ok := readWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+
+	members, err := h.workspaceService.GetMembers(workspaceID, claims.UserID)
+	if err != nil {
+		writeWorkspaceError(ctx, err, "Could not retrieve members")
+		return
+	}
+
+	response := make([]models.WorkspaceMemberResponse, len(members))
+	for i, member := range members {
+		response[i] = toWorkspaceMemberResponse(member)
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(response)
+}
+
+// UpdateMember
+// @Summary      Change a member's role
+// @Description  Changes the role of a workspace member. Owners and admins can do this, but only the owner can make or change admins, and nobody can change the owner.
+// @Tags         Workspaces
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  int                         true  "Workspace ID"
+// @Param        userID   path  int                         true  "Member's user ID"
+// @Param        payload  body  models.UpdateMemberRequest  true  "Role"
+// @Success      200 {object} models.WorkspaceMemberResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Workspace or member not found"
+// @Failure      409 {object} object{error=string} "The owner's membership cannot be changed"
+// @Failure      500 {object} object{error=string} "Could not update member"
+// @Router       /workspaces/{id}/members/{userID} [put]
+func (h *WorkspaceHandler) UpdateMember(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	workspaceID, ok := readWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+	memberID, err := ctx.Params().GetUint("userID")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.UpdateMemberRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate update member request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	member, err := h.workspaceService.UpdateMember(workspaceID, claims.UserID, memberID, req)
+	if err != nil {
+		writeWorkspaceError(ctx, err, "Could not update member")
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toWorkspaceMemberResponse(*member))
+}
+
+// RemoveMember
+// @Summary      Remove a member
+// @Description  Removes a member from a workspace. Owners and admins can remove members, only the owner can remove admins, and any member but the owner can remove themselves to leave.
+// @Tags         Workspaces
+// @Security     BearerAuth
+// @Param        id      path  int  true  "Workspace ID"
+// @Param        userID  path  int  true  "Member's user ID"
+// @Success      204 "No Content"
+// @Failure      400 {object} object{error=string} "Invalid ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Workspace or member not found"
+// @Failure      409 {object} object{error=string} "The owner's membership cannot be changed"
+// @Failure      500 {object} object{error=string} "Could not remove member"
+// @Router       /workspaces/{id}/members/{userID} [delete]
+func (h *WorkspaceHandler) RemoveMember(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	workspaceID, ok := readWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+	memberID, err := ctx.Params().GetUint("userID")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.workspaceService.RemoveMember(workspaceID, claims.UserID, memberID); err != nil {
+		writeWorkspaceError(ctx, err, "Could not remove member")
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
+
+// InviteMember
+// @Summary      Invite someone to a workspace
+// @Description  Invites an email address to join a workspace with the given role, renewing any pending invitation for it. The invitation is emailed to the address when SMTP is configured, and shows up under GET /invitations for a user with that address either way. The response includes the token the invitee accepts or declines the invitation with. Only the owner can invite admins.
+// @Tags         Workspaces
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  int                         true  "Workspace ID"
+// @Param        payload  body  models.InviteMemberRequest  true  "Invitation Payload"
+// @Success      201 {object} models.InvitationResponse
+// @Failure      400 {object} object{error=string} "Invalid request format or ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Workspace not found"
+// @Failure      409 {object} object{error=string} "Already a member"
+// @Failure      500 {object} object{error=string} "Could not send invitation"
+// @Router       /workspaces/{id}/invitations [post]
+func (h *WorkspaceHandler) InviteMember(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	workspaceID, ok := readWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.InviteMemberRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		logger.Error().Err(err).Msg("Failed to read or validate invite member request")
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid request format or validation failed", "details": err.Error()})
+		return
+	}
+
+	invitation, err := h.workspaceService.InviteMember(workspaceID, claims.UserID, req)
+	if err != nil {
+		writeWorkspaceError(ctx, err, "Could not send invitation")
+		return
+	}
+
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(toInvitationResponse(*invitation, true))
+}
+
+// GetInvitations
+// @Summary      Get a workspace's pending invitations
+// @Description  Lists the pending invitations of a workspace. Only its owner and admins can do this.
+// @Tags         Workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  int  true  "Workspace ID"
+// @Success      200 {array} models.InvitationResponse
+// @Failure      400 {object} object{error=string} "Invalid workspace ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Workspace not found"
+// @Failure      500 {object} object{error=string} "Could not retrieve invitations"
+// @Router       /workspaces/{id}/invitations [get]
+func (h *WorkspaceHandler) GetInvitations(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	workspaceID, ok := readWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.workspaceService.GetInvitations(workspaceID, claims.UserID)
+	if err != nil {
+		writeWorkspaceError(ctx, err, "Could not retrieve invitations")
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toInvitationResponses(invitations, false))
+}
+
+// RevokeInvitation
+// @Summary      Revoke an invitation
+// @Description  Revokes a pending invitation to a workspace. Only its owner and admins can do this.
+// @Tags         Workspaces
+// @Security     BearerAuth
+// @Param        id            path  int  true  "Workspace ID"
+// @Param        invitationID  path  int  true  "Invitation ID"
+// @Success      204 "No Content"
+// @Failure      400 {object} object{error=string} "Invalid ID"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      403 {object} object{error=string} "Access denied"
+// @Failure      404 {object} object{error=string} "Workspace or invitation not found"
+// @Failure      500 {object} object{error=string} "Could not revoke invitation"
+// @Router       /workspaces/{id}/invitations/{invitationID} [delete]
+func (h *WorkspaceHandler) RevokeInvitation(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+	workspaceID, ok := readWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+	invitationID, err := ctx.Params().GetUint("invitationID")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.JSON(iris.Map{"error": "Invalid invitation ID"})
+		return
+	}
+
+	if err := h.workspaceService.RevokeInvitation(workspaceID, claims.UserID, invitationID); err != nil {
+		writeWorkspaceError(ctx, err, "Could not revoke invitation")
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
+
+// GetMyInvitations
+// @Summary      Get my invitations
+// @Description  Lists the pending invitations sent to the authenticated user's email, with the tokens to accept or decline them.
+// @Tags         Workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} models.InvitationResponse
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      500 {object} object{error=string} "Could not retrieve invitations"
+// @Router       /invitations [get]
+func (h *WorkspaceHandler) GetMyInvitations(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+
+	invitations, err := h.workspaceService.GetMyInvitations(claims.UserID)
+	if err != nil {
+		writeWorkspaceError(ctx, err, "Could not retrieve invitations")
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toInvitationResponses(invitations, true))
+}
+
+// AcceptInvitation
+// @Summary      Accept an invitation
+// @Description  Joins the workspace an invitation sent to the authenticated user's email is for, with the role it offers.
+// @Tags         Workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Param        token  path  string  true  "Invitation token"
+// @Success      200 {object} models.WorkspaceResponse
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      404 {object} object{error=string} "Invitation not found"
+// @Failure      409 {object} object{error=string} "Already a member"
+// @Failure      410 {object} object{error=string} "Invitation has expired"
+// @Failure      500 {object} object{error=string} "Could not accept invitation"
+// @Router       /invitations/{token}/accept [post]
+func (h *WorkspaceHandler) AcceptInvitation(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+
+	member, err := h.workspaceService.AcceptInvitation(ctx.Params().Get("token"), claims.UserID)
+	if err != nil {
+		writeWorkspaceError(ctx, err, "Could not accept invitation")
+		return
+	}
+
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(toWorkspaceResponse(*member))
+}
+
+// DeclineInvitation
+// @Summary      Decline an invitation
+// @Description  Declines an invitation sent to the authenticated user's email.
+// @Tags         Workspaces
+// @Security     BearerAuth
+// @Param        token  path  string  true  "Invitation token"
+// @Success      204 "No Content"
+// @Failure      401 {object} object{error=string} "Unauthorized"
+// @Failure      404 {object} object{error=string} "Invitation not found"
+// @Failure      500 {object} object{error=string} "Could not decline invitation"
+// @Router       /invitations/{token}/decline [post]
+func (h *WorkspaceHandler) DeclineInvitation(ctx iris.Context) {
+	claims := jwt.Get(ctx).(*models.UserClaims)
+
+	if err := h.workspaceService.DeclineInvitation(ctx.Params().Get("token"), claims.UserID); err != nil {
+		writeWorkspaceError(ctx, err, "Could not decline invitation")
+		return
+	}
+
+	ctx.StatusCode(iris.StatusNoContent)
+}
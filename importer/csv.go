@@ -0,0 +1,145 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+// csvImporter reads the CSV export. Columns are found by their header, so
+// only title is required and the rest may come in any order; IDs of lists,
+// statuses and assignees don't carry over and are ignored.
+type csvImporter struct{}
+
+func (csvImporter) Parse(r io.Reader, _ Options) (*Result, error) {
+	table, err := readCSV(r, "title")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for table.next() {
+		line := table.line
+		task := Task{
+			Row:            line,
+			Ref:            table.get("id"),
+			ParentRef:      table.get("parent_id"),
+			Title:          table.get("title"),
+			Content:        table.get("content"),
+			Priority:       models.TaskPriority(strings.ToLower(table.get("priority"))),
+			TimeZone:       table.get("time_zone"),
+			RecurrenceRule: table.get("recurrence_rule"),
+			Tags:           splitTags(table.get("tags"), ";"),
+		}
+
+		var ok bool
+		if task.Completed, ok = table.bool("completed"); !ok {
+			result.addError(line, "completed", "must be true or false")
+			continue
+		}
+		allDay, ok := table.bool("all_day")
+		if !ok {
+			result.addError(line, "all_day", "must be true or false")
+			continue
+		}
+		dueDate, dateOnly, err := parseDueDate(table.get("due_date"))
+		if err != nil {
+			result.addError(line, "due_date", err.Error())
+			continue
+		}
+		task.DueDate, task.AllDay = dueDate, allDay || dateOnly
+
+		result.Tasks = append(result.Tasks, task)
+	}
+	if table.err != nil {
+		return nil, table.err
+	}
+	return result, nil
+}
+
+// csvTable reads the rows of a CSV file with a header, giving access to
+// their fields by column name.
+type csvTable struct {
+	reader  *csv.Reader
+	columns map[string]int
+	row     []string
+	line    int
+	err     error
+}
+
+// readCSV reads the header of a CSV file, whose column names are matched
+// ignoring case, and checks that the required columns are there.
+func readCSV(r io.Reader, required ...string) (*csvTable, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	table := &csvTable{reader: reader, columns: make(map[string]int, len(header))}
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheet applications often start UTF-8 files with a BOM.
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		table.columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range required {
+		if _, ok := table.columns[name]; !ok {
+			return nil, fmt.Errorf("the file has no %s column", name)
+		}
+	}
+	return table, nil
+}
+
+// next moves to the next row, skipping blank ones, and reports whether there
+// was one. Errors other than the end of the file are kept in err.
+func (t *csvTable) next() bool {
+	for {
+		row, err := t.reader.Read()
+		if errors.Is(err, io.EOF) {
+			return false
+		}
+		if err != nil {
+			t.err = err
+			return false
+		}
+		t.line, _ = t.reader.FieldPos(0)
+		if len(row) == 1 && strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		t.row = row
+		return true
+	}
+}
+
+// get returns the trimmed field of the current row in the named column, or
+// "" if there is no such column or the row is too short.
+func (t *csvTable) get(column string) string {
+	i, ok := t.columns[column]
+	if !ok || i >= len(t.row) {
+		return ""
+	}
+	return strings.TrimSpace(t.row[i])
+}
+
+// bool reads a boolean field, with a blank one being false.
+func (t *csvTable) bool(column string) (value, ok bool) {
+	field := t.get(column)
+	if field == "" {
+		return false, true
+	}
+	value, err := strconv.ParseBool(field)
+	return value, err == nil
+}
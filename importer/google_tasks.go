@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// googleTasksImporter reads Google Tasks, either a Google Takeout export with
+// every task list or a single list as the Google Tasks API returns it.
+// Deleted tasks are left out. Google Tasks due dates have no time of day, so
+// the tasks are all-day.
+type googleTasksImporter struct{}
+
+type googleTask struct {
+	ID       string     `json:"id"`
+	Title    string     `json:"title"`
+	Notes    string     `json:"notes"`
+	Status   string     `json:"status"`
+	Due      *time.Time `json:"due"`
+	Parent   string     `json:"parent"`
+	Position string     `json:"position"`
+	Deleted  bool       `json:"deleted"`
+}
+
+func (googleTasksImporter) Parse(r io.Reader, _ Options) (*Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Kind  string            `json:"kind"`
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := decodeJSON(bytes.NewReader(data), &file); err != nil {
+		return nil, err
+	}
+
+	// Takeout holds task lists, each with its tasks, where the API holds the
+	// tasks of a single list.
+	lists := [][]json.RawMessage{file.Items}
+	if file.Kind == "tasks#taskLists" {
+		lists = nil
+		for _, raw := range file.Items {
+			var list struct {
+				Items []json.RawMessage `json:"items"`
+			}
+			if err := json.Unmarshal(raw, &list); err != nil {
+				return nil, errStructure
+			}
+			lists = append(lists, list.Items)
+		}
+	}
+
+	result := &Result{}
+	row := 0
+	for _, items := range lists {
+		var tasks []Task
+		var positions []string
+		for _, item := range items {
+			row++
+			var t googleTask
+			if err := json.Unmarshal(item, &t); err != nil {
+				result.Errors = append(result.Errors, jsonRowError(row, err))
+				continue
+			}
+			if t.Deleted {
+				continue
+			}
+			task := Task{
+				Row:       row,
+				Ref:       t.ID,
+				ParentRef: t.Parent,
+				Title:     t.Title,
+				Content:   t.Notes,
+				Completed: t.Status == "completed",
+			}
+			if t.Due != nil {
+				year, month, day := t.Due.UTC().Date()
+				due := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+				task.DueDate, task.AllDay = &due, true
+			}
+			tasks = append(tasks, task)
+			positions = append(positions, t.Position)
+		}
+
+		// Tasks come in no particular order; their positions order them
+		// among their siblings.
+		order := make([]int, len(tasks))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool { return positions[order[i]] < positions[order[j]] })
+		for _, i := range order {
+			result.Tasks = append(result.Tasks, tasks[i])
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,137 @@
+// Package importer reads tasks from files exported by Listario or by other
+// to-do applications. Each format is an Importer registered under its name,
+// like the exporters of the export package.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+// Importer reads one file format.
+type Importer interface {
+	// Parse reads a whole file. Problems with single tasks are reported in
+	// the result's Errors, while an error means the file itself could not be
+	// read.
+	Parse(r io.Reader, opts Options) (*Result, error)
+}
+
+// Options holds what importers need to know beyond the file.
+type Options struct {
+	// Now is the current time in the user's time zone. Relative dates such
+	// as "tomorrow" are read against it.
+	Now time.Time
+}
+
+// Task is a task read from a file, before it is validated. Ref identifies it
+// within the file, and ParentRef is the Ref of its parent if it is a subtask.
+type Task struct {
+	Row            int                 `json:"-"`
+	Ref            string              `json:"-"`
+	ParentRef      string              `json:"-"`
+	Title          string              `json:"title" validate:"required,min=1,max=100"`
+	Content        string              `json:"content"`
+	Priority       models.TaskPriority `json:"priority" validate:"omitempty,priority"`
+	DueDate        *time.Time          `json:"due_date"`
+	AllDay         bool                `json:"all_day"`
+	TimeZone       string              `json:"time_zone" validate:"omitempty,timezone"`
+	Completed      bool                `json:"completed"`
+	RecurrenceRule string              `json:"recurrence_rule" validate:"omitempty,max=255"`
+	Tags           []string            `json:"tags" validate:"dive,min=1,max=50"`
+}
+
+// RowError is a problem with a single task of a file. Row is the task's line
+// in CSV files, or its position among the tasks of other files, counting from
+// 1. Field is empty when the problem isn't with one field in particular.
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e RowError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("row %d: %s", e.Row, e.Message)
+	}
+	return fmt.Sprintf("row %d: %s %s", e.Row, e.Field, e.Message)
+}
+
+// Result is everything read from a file, with the tasks in the order they
+// should be created.
+type Result struct {
+	Tasks  []Task
+	Errors []RowError
+}
+
+func (r *Result) addError(row int, field, message string) {
+	r.Errors = append(r.Errors, RowError{Row: row, Field: field, Message: message})
+}
+
+var importers = map[string]Importer{}
+
+// Register makes the importer available under the format name, replacing any
+// importer registered under it before.
+func Register(format string, importer Importer) {
+	importers[format] = importer
+}
+
+// Lookup returns the importer registered under the format name.
+func Lookup(format string) (Importer, bool) {
+	importer, ok := importers[format]
+	return importer, ok
+}
+
+// Formats lists the registered format names in alphabetical order.
+func Formats() []string {
+	formats := make([]string, 0, len(importers))
+	for format := range importers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+func init() {
+	Register("csv", csvImporter{})
+	Register("json", jsonImporter{})
+	Register("todoist", todoistImporter{})
+	Register("trello", trelloImporter{})
+	Register("microsoft_todo", microsoftTodoImporter{})
+	Register("google_tasks", googleTasksImporter{})
+}
+
+var errDueDate = errors.New("must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+
+// parseDueDate reads a due date written as a date alone, which makes the task
+// all-day, or as an RFC 3339 timestamp.
+func parseDueDate(value string) (dueDate *time.Time, allDay bool, err error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, false, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return &t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, false, errDueDate
+	}
+	return &t, false, nil
+}
+
+// splitTags reads a list of tag names separated by sep, dropping blanks.
+func splitTags(value, sep string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, sep) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
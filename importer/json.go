@@ -0,0 +1,100 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+// jsonImporter reads the JSON export: an array with an object per task.
+type jsonImporter struct{}
+
+type jsonTask struct {
+	ID             *uint               `json:"id"`
+	Title          string              `json:"title"`
+	Content        string              `json:"content"`
+	Completed      bool                `json:"completed"`
+	Priority       models.TaskPriority `json:"priority"`
+	DueDate        *time.Time          `json:"due_date"`
+	AllDay         bool                `json:"all_day"`
+	TimeZone       string              `json:"time_zone"`
+	ParentID       *uint               `json:"parent_id"`
+	RecurrenceRule string              `json:"recurrence_rule"`
+	Tags           []string            `json:"tags"`
+}
+
+func (jsonImporter) Parse(r io.Reader, _ Options) (*Result, error) {
+	var items []json.RawMessage
+	if err := decodeJSON(r, &items); err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for i, item := range items {
+		row := i + 1
+		var t jsonTask
+		if err := json.Unmarshal(item, &t); err != nil {
+			result.Errors = append(result.Errors, jsonRowError(row, err))
+			continue
+		}
+		result.Tasks = append(result.Tasks, Task{
+			Row:            row,
+			Ref:            formatRef(t.ID),
+			ParentRef:      formatRef(t.ParentID),
+			Title:          t.Title,
+			Content:        t.Content,
+			Priority:       t.Priority,
+			DueDate:        t.DueDate,
+			AllDay:         t.AllDay,
+			TimeZone:       t.TimeZone,
+			Completed:      t.Completed,
+			RecurrenceRule: t.RecurrenceRule,
+			Tags:           t.Tags,
+		})
+	}
+	return result, nil
+}
+
+var errStructure = errors.New("the file does not have the structure of the chosen format")
+
+// decodeJSON decodes a whole file into v, turning syntax errors into a
+// message about the file.
+func decodeJSON(r io.Reader, v any) error {
+	err := json.NewDecoder(r).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return errors.New("the file is empty")
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return errors.New("the file is not valid JSON: " + err.Error())
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return errStructure
+	}
+	return err
+}
+
+// jsonRowError describes why a task of a JSON file could not be decoded.
+func jsonRowError(row int, err error) RowError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return RowError{Row: row, Field: typeErr.Field, Message: "must not be a " + typeErr.Value}
+	}
+	var timeErr *time.ParseError
+	if errors.As(err, &timeErr) {
+		return RowError{Row: row, Field: "due_date", Message: "must be an RFC 3339 timestamp"}
+	}
+	return RowError{Row: row, Message: err.Error()}
+}
+
+func formatRef(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
@@ -0,0 +1,162 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+var microsoftImportances = map[string]models.TaskPriority{
+	"low":    models.PriorityLow,
+	"normal": models.PriorityNone,
+	"high":   models.PriorityHigh,
+}
+
+var microsoftFrequencies = map[string]string{
+	"daily":           "DAILY",
+	"weekly":          "WEEKLY",
+	"absoluteMonthly": "MONTHLY",
+	"absoluteYearly":  "YEARLY",
+}
+
+// microsoftTodoImporter reads Microsoft To Do tasks as the Microsoft Graph API
+// returns them, either as an array or as a response with a value array.
+// Steps become subtasks and categories become tags. To Do due dates have no
+// time of day, so the tasks are all-day.
+type microsoftTodoImporter struct{}
+
+type microsoftTask struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Importance string `json:"importance"`
+	Status     string `json:"status"`
+	Body       *struct {
+		Content string `json:"content"`
+	} `json:"body"`
+	DueDateTime *struct {
+		DateTime string `json:"dateTime"`
+		TimeZone string `json:"timeZone"`
+	} `json:"dueDateTime"`
+	Recurrence *struct {
+		Pattern struct {
+			Type       string   `json:"type"`
+			Interval   int      `json:"interval"`
+			DaysOfWeek []string `json:"daysOfWeek"`
+		} `json:"pattern"`
+	} `json:"recurrence"`
+	Categories     []string `json:"categories"`
+	ChecklistItems []struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"displayName"`
+		IsChecked   bool   `json:"isChecked"`
+	} `json:"checklistItems"`
+}
+
+func (microsoftTodoImporter) Parse(r io.Reader, _ Options) (*Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var items []json.RawMessage
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var response struct {
+			Value []json.RawMessage `json:"value"`
+		}
+		err = decodeJSON(bytes.NewReader(data), &response)
+		items = response.Value
+	} else {
+		err = decodeJSON(bytes.NewReader(data), &items)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	row := 0
+	for _, item := range items {
+		row++
+		var t microsoftTask
+		if err := json.Unmarshal(item, &t); err != nil {
+			result.Errors = append(result.Errors, jsonRowError(row, err))
+			continue
+		}
+
+		ref := t.ID
+		if ref == "" {
+			ref = fmt.Sprintf("row %d", row)
+		}
+		task := Task{
+			Row:       row,
+			Ref:       ref,
+			Title:     t.Title,
+			Priority:  microsoftImportances[t.Importance],
+			Completed: t.Status == "completed",
+			Tags:      t.Categories,
+		}
+		if t.Body != nil {
+			task.Content = strings.TrimSpace(t.Body.Content)
+		}
+		if t.DueDateTime != nil && t.DueDateTime.DateTime != "" {
+			due, err := time.Parse(time.DateOnly, t.DueDateTime.DateTime[:min(len(t.DueDateTime.DateTime), len(time.DateOnly))])
+			if err != nil {
+				result.addError(row, "dueDateTime", "must start with a date (YYYY-MM-DD)")
+				continue
+			}
+			task.DueDate, task.AllDay = &due, true
+		}
+		if t.Recurrence != nil {
+			rule, ok := microsoftRecurrence(t.Recurrence.Pattern.Type, t.Recurrence.Pattern.Interval, t.Recurrence.Pattern.DaysOfWeek)
+			if !ok {
+				result.addError(row, "recurrence", fmt.Sprintf("has a %q pattern, which is not supported", t.Recurrence.Pattern.Type))
+				continue
+			}
+			task.RecurrenceRule = rule
+		}
+		result.Tasks = append(result.Tasks, task)
+
+		for i, step := range t.ChecklistItems {
+			row++
+			stepRef := step.ID
+			if stepRef == "" {
+				stepRef = fmt.Sprintf("%s step %d", ref, i+1)
+			}
+			result.Tasks = append(result.Tasks, Task{
+				Row:       row,
+				Ref:       stepRef,
+				ParentRef: ref,
+				Title:     step.DisplayName,
+				Completed: step.IsChecked,
+			})
+		}
+	}
+	return result, nil
+}
+
+// microsoftRecurrence turns a Graph recurrence pattern into an RRULE. Only
+// patterns that repeat on the same date or weekdays are supported.
+func microsoftRecurrence(patternType string, interval int, daysOfWeek []string) (string, bool) {
+	frequency, ok := microsoftFrequencies[patternType]
+	if !ok {
+		return "", false
+	}
+	rule := "FREQ=" + frequency
+	if interval > 1 {
+		rule += fmt.Sprintf(";INTERVAL=%d", interval)
+	}
+	if frequency == "WEEKLY" && len(daysOfWeek) > 0 {
+		days := make([]string, len(daysOfWeek))
+		for i, day := range daysOfWeek {
+			if len(day) < 2 {
+				return "", false
+			}
+			days[i] = strings.ToUpper(day[:2])
+		}
+		rule += ";BYDAY=" + strings.Join(days, ",")
+	}
+	return rule, true
+}
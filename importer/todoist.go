@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/quickadd"
+)
+
+// todoistPriorities maps Todoist's priorities, where 1 is the most urgent and
+// 4 the default, to ours.
+var todoistPriorities = map[string]models.TaskPriority{
+	"1": models.PriorityUrgent,
+	"2": models.PriorityHigh,
+	"3": models.PriorityMedium,
+	"4": models.PriorityNone,
+}
+
+// todoistImporter reads a project exported from Todoist as CSV. Each task row
+// has an INDENT nesting it under the closest task above with a smaller one,
+// notes are added to the content of the task above them and labels written
+// as @label in the task's text become tags. Dates are read like quick-add
+// lines, so "every monday" or "tomorrow 9am" keep their meaning.
+type todoistImporter struct{}
+
+func (todoistImporter) Parse(r io.Reader, opts Options) (*Result, error) {
+	table, err := readCSV(r, "type", "content")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	// parents holds the refs of the last task seen at each indent, up to the
+	// current one.
+	var parents []string
+	var last *Task
+	for table.next() {
+		line := table.line
+		switch strings.ToLower(table.get("type")) {
+		case "task":
+		case "note":
+			if last != nil && table.get("content") != "" {
+				last.Content = strings.TrimSpace(last.Content + "\n\n" + table.get("content"))
+			}
+			continue
+		case "section":
+			parents, last = nil, nil
+			continue
+		default:
+			continue
+		}
+		last = nil
+
+		indent := 1
+		if value := table.get("indent"); value != "" {
+			indent, err = strconv.Atoi(value)
+			if err != nil || indent < 1 || indent > len(parents)+1 {
+				result.addError(line, "INDENT", "must be a number from 1 to one more than the task above")
+				continue
+			}
+		}
+		parents = parents[:indent-1]
+
+		title, tags := todoistLabels(table.get("content"))
+		task := Task{
+			Row:      line,
+			Ref:      strconv.Itoa(line),
+			Title:    title,
+			Content:  table.get("description"),
+			TimeZone: table.get("timezone"),
+			Tags:     tags,
+		}
+		if indent > 1 {
+			task.ParentRef = parents[indent-2]
+		}
+		if value := table.get("priority"); value != "" {
+			priority, ok := todoistPriorities[value]
+			if !ok {
+				result.addError(line, "PRIORITY", "must be a number from 1 to 4")
+				continue
+			}
+			task.Priority = priority
+		}
+		if date := table.get("date"); date != "" {
+			if !readTodoistDate(&task, date, opts) {
+				result.addError(line, "DATE", "is not a date that can be understood")
+				continue
+			}
+		}
+
+		parents = append(parents, task.Ref)
+		result.Tasks = append(result.Tasks, task)
+		last = &result.Tasks[len(result.Tasks)-1]
+	}
+	if table.err != nil {
+		return nil, table.err
+	}
+	return result, nil
+}
+
+// todoistLabels takes the @labels out of a task's text, returning what's left
+// as the title along with the label names.
+func todoistLabels(content string) (title string, labels []string) {
+	var words []string
+	for _, word := range strings.Fields(content) {
+		if len(word) > 1 && strings.HasPrefix(word, "@") {
+			labels = append(labels, word[1:])
+		} else {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " "), labels
+}
+
+// readTodoistDate sets the task's due date and recurrence from a Todoist
+// date, which is either a plain date or timestamp or the text the user typed.
+// It reports whether the whole date was understood.
+func readTodoistDate(task *Task, date string, opts Options) bool {
+	if dueDate, allDay, err := parseDueDate(date); err == nil {
+		task.DueDate, task.AllDay = dueDate, allDay
+		return true
+	}
+	parsed := quickadd.Parse(date, opts.Now)
+	if parsed.Title != "" || (parsed.DueDate == nil && parsed.RecurrenceRule == "") {
+		return false
+	}
+	task.DueDate, task.AllDay, task.RecurrenceRule = parsed.DueDate, parsed.AllDay, parsed.RecurrenceRule
+	return true
+}
@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"io"
+	"sort"
+	"time"
+)
+
+// trelloImporter reads a board exported from Trello as JSON. Open cards
+// become tasks tagged with their labels, and the items of their checklists
+// become subtasks. Archived cards, and cards in archived lists, are left out.
+type trelloImporter struct{}
+
+type trelloBoard struct {
+	Lists []struct {
+		ID     string `json:"id"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		ID          string     `json:"id"`
+		Name        string     `json:"name"`
+		Desc        string     `json:"desc"`
+		Closed      bool       `json:"closed"`
+		Due         *time.Time `json:"due"`
+		DueComplete bool       `json:"dueComplete"`
+		IDList      string     `json:"idList"`
+		Labels      []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"labels"`
+	} `json:"cards"`
+	Checklists []struct {
+		IDCard     string  `json:"idCard"`
+		Pos        float64 `json:"pos"`
+		CheckItems []struct {
+			ID    string     `json:"id"`
+			Name  string     `json:"name"`
+			State string     `json:"state"`
+			Due   *time.Time `json:"due"`
+			Pos   float64    `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+func (trelloImporter) Parse(r io.Reader, _ Options) (*Result, error) {
+	var board trelloBoard
+	if err := decodeJSON(r, &board); err != nil {
+		return nil, err
+	}
+
+	closedLists := make(map[string]bool)
+	for _, list := range board.Lists {
+		closedLists[list.ID] = list.Closed
+	}
+	sort.SliceStable(board.Checklists, func(i, j int) bool {
+		return board.Checklists[i].Pos < board.Checklists[j].Pos
+	})
+
+	result := &Result{}
+	row := 0
+	for _, card := range board.Cards {
+		if card.Closed || closedLists[card.IDList] {
+			continue
+		}
+		row++
+		task := Task{
+			Row:       row,
+			Ref:       card.ID,
+			Title:     card.Name,
+			Content:   card.Desc,
+			DueDate:   card.Due,
+			Completed: card.DueComplete,
+		}
+		for _, label := range card.Labels {
+			name := label.Name
+			if name == "" {
+				name = label.Color
+			}
+			if name != "" {
+				task.Tags = append(task.Tags, name)
+			}
+		}
+		result.Tasks = append(result.Tasks, task)
+
+		for _, checklist := range board.Checklists {
+			if checklist.IDCard != card.ID {
+				continue
+			}
+			items := checklist.CheckItems
+			sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+			for _, item := range items {
+				row++
+				result.Tasks = append(result.Tasks, Task{
+					Row:       row,
+					Ref:       item.ID,
+					ParentRef: card.ID,
+					Title:     item.Name,
+					DueDate:   item.Due,
+					Completed: item.State == "complete",
+				})
+			}
+		}
+	}
+	return result, nil
+}
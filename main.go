@@ -1,17 +1,27 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/RLRama/listario-backend/db"
 	_ "github.com/RLRama/listario-backend/docs"
 	"github.com/RLRama/listario-backend/handler"
 	"github.com/RLRama/listario-backend/logger"
 	"github.com/RLRama/listario-backend/middleware"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/notify"
 	"github.com/RLRama/listario-backend/repository"
 	"github.com/RLRama/listario-backend/router"
 	"github.com/RLRama/listario-backend/service"
+	"github.com/RLRama/listario-backend/storage"
 	"github.com/RLRama/listario-backend/utils"
+	"github.com/RLRama/listario-backend/worker"
 	"github.com/iris-contrib/swagger/v12"
 	"github.com/iris-contrib/swagger/v12/swaggerFiles"
 	_ "github.com/joho/godotenv/autoload"
@@ -36,6 +46,11 @@ import (
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and a JWT.
+
+// shutdownTimeout bounds how long in-flight requests and background workers
+// get to finish once the server is asked to stop.
+const shutdownTimeout = 15 * time.Second
+
 func main() {
 	logger.SetupLogger()
 
@@ -51,6 +66,21 @@ func main() {
 		logger.Fatal().Err(err).Msg("Failed to set up JWT signer and verifier")
 	}
 
+	trashRetention, err := worker.TrashRetentionFromEnv()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Invalid trash retention")
+	}
+
+	attachmentMaxSize, err := service.AttachmentMaxSizeFromEnv()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Invalid attachment size limit")
+	}
+
+	blobStore, err := storage.NewFromEnv()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to set up blob storage")
+	}
+
 	database, err := db.InitDB(db.GetDSN("PROD"))
 	if err != nil {
 		logger.Fatal().Err(err).Msg("Failed to initialize database")
@@ -69,19 +99,95 @@ func main() {
 
 	userRepository := repository.NewGormUserRepository(database)
 	taskRepository := repository.NewGormTaskRepository(database)
+	tagRepository := repository.NewGormTagRepository(database)
+	listRepository := repository.NewGormListRepository(database)
+	commentRepository := repository.NewGormCommentRepository(database)
+	attachmentRepository := repository.NewGormAttachmentRepository(database)
+	shareRepository := repository.NewGormShareRepository(database)
+	workspaceRepository := repository.NewGormWorkspaceRepository(database)
+	invitationRepository := repository.NewGormInvitationRepository(database)
+	statusRepository := repository.NewGormStatusRepository(database)
+	reminderRepository := repository.NewGormReminderRepository(database)
+	notificationRepository := repository.NewGormNotificationRepository(database)
+	templateRepository := repository.NewGormTemplateRepository(database)
+	timeEntryRepository := repository.NewGormTimeEntryRepository(database)
+
+	notifiers, err := notify.NewFromEnv(notificationRepository)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to set up notifications")
+	}
 
-	userService := service.NewUserService(userRepository, signer, refreshTokenMaxAge)
-	taskService := service.NewTaskService(taskRepository)
+	validator := utils.NewCustomValidator()
+
+	userService := service.NewUserService(userRepository, listRepository, workspaceRepository, signer, refreshTokenMaxAge)
+	taskService := service.NewTaskService(taskRepository, userRepository, tagRepository, listRepository, statusRepository, shareRepository, workspaceRepository, blobStore)
+	tagService := service.NewTagService(tagRepository)
+	listService := service.NewListService(listRepository, taskRepository, shareRepository, workspaceRepository)
+	commentService := service.NewCommentService(commentRepository, taskService)
+	attachmentService := service.NewAttachmentService(attachmentRepository, taskService, blobStore, attachmentMaxSize)
+	workspaceService := service.NewWorkspaceService(workspaceRepository, invitationRepository, userRepository, notifiers[models.ReminderEmail])
+	shareService := service.NewShareService(shareRepository, userRepository, taskRepository, listRepository, taskService, listService)
+	statusService := service.NewStatusService(statusRepository, taskRepository, listService)
+	reminderService := service.NewReminderService(reminderRepository, taskService, notifiers)
+	notificationService := service.NewNotificationService(notificationRepository)
+	templateService := service.NewTemplateService(templateRepository, userRepository, taskRepository, listRepository, shareRepository, workspaceRepository, taskService)
+	timeEntryService := service.NewTimeEntryService(timeEntryRepository, userRepository, taskService)
+	importService := service.NewImportService(taskService, userRepository, validator)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	workers := worker.NewGroup(ctx)
+	workers.Go("trash-purger", worker.NewTrashPurger(taskService, trashRetention, worker.TrashPurgeInterval).Run)
+	workers.Go("reminder-scheduler", worker.NewReminderScheduler(reminderService, worker.ReminderPollInterval).Run)
 
 	userHandler := handler.NewUserHandler(userService, verifier)
 	taskHandler := handler.NewTaskHandler(taskService)
-
-	app.Validator = utils.NewCustomValidator()
+	tagHandler := handler.NewTagHandler(tagService)
+	listHandler := handler.NewListHandler(listService)
+	commentHandler := handler.NewCommentHandler(commentService)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService)
+	shareHandler := handler.NewShareHandler(shareService)
+	workspaceHandler := handler.NewWorkspaceHandler(workspaceService)
+	statusHandler := handler.NewStatusHandler(statusService)
+	reminderHandler := handler.NewReminderHandler(reminderService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	templateHandler := handler.NewTemplateHandler(templateService)
+	timeEntryHandler := handler.NewTimeEntryHandler(timeEntryService)
+	importHandler := handler.NewImportHandler(importService)
+
+	app.Validator = validator
 	app.Use(middleware.RequestLogger())
 
-	router.SetupRoutes(app, userHandler, taskHandler, verifier, rateLimiter)
+	router.SetupRoutes(app, userHandler, taskHandler, tagHandler, listHandler, commentHandler, attachmentHandler, shareHandler, workspaceHandler, statusHandler, reminderHandler, notificationHandler, templateHandler, timeEntryHandler, importHandler, verifier, rateLimiter)
+
+	go func() {
+		<-ctx.Done()
+		logger.Info().Msg("Shutting down...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := app.Shutdown(shutdownCtx); err != nil {
+			logger.Error().Err(err).Msg("Failed to shut down the server gracefully")
+		}
+	}()
+
+	listenErr := app.Listen(":"+port, iris.WithoutInterruptHandler)
+	if errors.Is(listenErr, iris.ErrServerClosed) {
+		listenErr = nil
+	}
+	if listenErr != nil {
+		logger.Error().Err(listenErr).Msg("Failed to start the server")
+	}
+	// Stop the workers whether the server was asked to stop or failed.
+	stop()
 
-	if err := app.Listen(":" + port); err != nil {
-		logger.Fatal().Err(err).Msg("Failed to start the server")
+	if !workers.Wait(shutdownTimeout) {
+		logger.Warn().Msg("Background workers did not stop in time")
+	}
+	if sqlDB, err := database.DB(); err == nil {
+		sqlDB.Close()
+	}
+	if listenErr != nil {
+		os.Exit(1)
 	}
 }
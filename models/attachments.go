@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// Attachment describes a file uploaded to a task. The contents live in blob
+// storage under StorageKey; removing an attachment removes them too, so
+// attachments are deleted for good rather than soft-deleted.
+type Attachment struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	TaskID      uint      `gorm:"not null;index" json:"task_id"`
+	UploaderID  uint      `gorm:"not null" json:"uploader_id"`
+	FileName    string    `gorm:"not null" json:"file_name"`
+	ContentType string    `gorm:"not null" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	StorageKey  string    `gorm:"not null;uniqueIndex" json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type AttachmentResponse struct {
+	ID          uint      `json:"id"`
+	TaskID      uint      `json:"task_id"`
+	UploaderID  uint      `json:"uploader_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
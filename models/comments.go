@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Comment is a markdown note left on a task. EditedAt is only set once the
+// body has been changed after posting.
+type Comment struct {
+	gorm.Model
+	TaskID   uint       `gorm:"not null;index" json:"task_id"`
+	AuthorID uint       `gorm:"not null" json:"author_id"`
+	Body     string     `gorm:"type:text;not null" json:"body"`
+	EditedAt *time.Time `json:"edited_at"`
+}
+
+type CreateCommentRequest struct {
+	Body string `json:"body" validate:"required,min=1,max=10000"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" validate:"required,min=1,max=10000"`
+}
+
+type CommentResponse struct {
+	ID        uint       `json:"id"`
+	TaskID    uint       `json:"task_id"`
+	AuthorID  uint       `json:"author_id"`
+	Body      string     `json:"body"`
+	EditedAt  *time.Time `json:"edited_at"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ImportRowError is a problem with one task of an imported file. Row is the
+// task's line in CSV files, or its position among the tasks of other files.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportedTaskResponse is a task read from an imported file. ParentRow is the
+// row of its parent when it is a subtask.
+type ImportedTaskResponse struct {
+	Row            int          `json:"row"`
+	ParentRow      *int         `json:"parent_row,omitempty"`
+	Title          string       `json:"title"`
+	Priority       TaskPriority `json:"priority"`
+	DueDate        *time.Time   `json:"due_date"`
+	AllDay         bool         `json:"all_day"`
+	Completed      bool         `json:"completed"`
+	RecurrenceRule string       `json:"recurrence_rule,omitempty"`
+	Tags           []string     `json:"tags"`
+}
+
+// ImportResponse lists the tasks of an imported file in the order they are
+// created. Created is how many were, which is none for dry runs and for files
+// with any errors.
+type ImportResponse struct {
+	Format  string                 `json:"format"`
+	DryRun  bool                   `json:"dry_run"`
+	Created int                    `json:"created"`
+	Tasks   []ImportedTaskResponse `json:"tasks"`
+	Errors  []ImportRowError       `json:"errors"`
+}
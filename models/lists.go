@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const InboxListName = "Inbox"
+
+type List struct {
+	gorm.Model
+	Name     string `gorm:"not null" json:"name"`
+	Color    string `gorm:"type:varchar(7)" json:"color"`
+	Icon     string `gorm:"type:varchar(50)" json:"icon"`
+	Archived bool   `gorm:"default:false" json:"archived"`
+	IsInbox  bool   `gorm:"default:false" json:"is_inbox"`
+	UserID   uint   `gorm:"not null;index" json:"user_id"`
+	// WorkspaceID is the workspace the list belongs to, or nil if it is in
+	// its owner's personal space. Inboxes are always personal.
+	WorkspaceID *uint `gorm:"index" json:"workspace_id"`
+}
+
+type CreateListRequest struct {
+	Name  string `json:"name" validate:"required,min=1,max=100"`
+	Color string `json:"color" validate:"omitempty,hexcolor"`
+	Icon  string `json:"icon" validate:"omitempty,max=50"`
+}
+
+type UpdateListRequest struct {
+	Name     string `json:"name" validate:"omitempty,min=1,max=100"`
+	Color    string `json:"color" validate:"omitempty,hexcolor"`
+	Icon     string `json:"icon" validate:"omitempty,max=50"`
+	Archived *bool  `json:"archived"`
+}
+
+type ListResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Color       string    `json:"color"`
+	Icon        string    `json:"icon"`
+	Archived    bool      `json:"archived"`
+	IsInbox     bool      `json:"is_inbox"`
+	UserID      uint      `json:"user_id"`
+	WorkspaceID *uint     `json:"workspace_id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
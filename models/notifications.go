@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Notification is a message shown to a user inside the app, such as a
+// reminder delivered through the in-app channel.
+type Notification struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	TaskID     *uint      `gorm:"index" json:"task_id"`
+	ReminderID *uint      `json:"reminder_id"`
+	Title      string     `gorm:"not null" json:"title"`
+	Body       string     `gorm:"type:text" json:"body"`
+	ReadAt     *time.Time `json:"read_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type NotificationResponse struct {
+	ID         uint       `json:"id"`
+	TaskID     *uint      `json:"task_id"`
+	ReminderID *uint      `json:"reminder_id"`
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	ReadAt     *time.Time `json:"read_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type QuickAddTaskRequest struct {
+	Text   string `json:"text" validate:"required,min=1,max=500"`
+	ListID *uint  `json:"list_id"`
+	// TimeZone the text is read in; defaults to the user's one.
+	TimeZone string `json:"time_zone" validate:"omitempty,timezone"`
+}
+
+type QuickAddMatch struct {
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+// QuickAddParse shows what was understood from a quick-add text.
+type QuickAddParse struct {
+	Title          string          `json:"title"`
+	DueDate        *time.Time      `json:"due_date"`
+	AllDay         bool            `json:"all_day"`
+	RecurrenceRule string          `json:"recurrence_rule,omitempty"`
+	Tags           []string        `json:"tags"`
+	Priority       TaskPriority    `json:"priority,omitempty"`
+	Matches        []QuickAddMatch `json:"matches"`
+}
+
+type QuickAddTaskResponse struct {
+	Task   TaskResponse  `json:"task"`
+	Parsed QuickAddParse `json:"parsed"`
+}
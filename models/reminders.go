@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+type ReminderChannel string
+
+const (
+	ReminderEmail   ReminderChannel = "email"
+	ReminderWebhook ReminderChannel = "webhook"
+	ReminderInApp   ReminderChannel = "in_app"
+)
+
+type ReminderStatus string
+
+const (
+	ReminderPending ReminderStatus = "pending"
+	ReminderSent    ReminderStatus = "sent"
+	// ReminderFailed reminders gave up after too many failed deliveries.
+	ReminderFailed ReminderStatus = "failed"
+	// ReminderSkipped reminders came due after their task was completed or
+	// deleted.
+	ReminderSkipped ReminderStatus = "skipped"
+)
+
+// Reminder notifies its user about a task once, either at RemindAt or
+// MinutesBefore the task's due date. All-day tasks count as due at 9:00 in
+// their time zone. Pending reminders are kept here until delivered, so none
+// are lost across restarts.
+type Reminder struct {
+	ID            uint            `gorm:"primarykey" json:"id"`
+	TaskID        uint            `gorm:"not null;index" json:"task_id"`
+	Task          Task            `json:"-"`
+	UserID        uint            `gorm:"not null;index" json:"user_id"`
+	User          User            `json:"-"`
+	RemindAt      *time.Time      `json:"remind_at"`
+	MinutesBefore *int            `json:"minutes_before"`
+	Channel       ReminderChannel `gorm:"type:varchar(10);not null" json:"channel"`
+	WebhookURL    string          `gorm:"type:varchar(2048)" json:"webhook_url"`
+	Status        ReminderStatus  `gorm:"type:varchar(10);not null;default:'pending';index" json:"status"`
+	Attempts      int             `gorm:"not null;default:0" json:"attempts"`
+	// NextAttemptAt holds back a reminder that is being delivered, or is
+	// waiting to be retried, until then.
+	NextAttemptAt *time.Time `json:"-"`
+	LastError     string     `gorm:"type:text" json:"-"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// CreateReminderRequest sets either RemindAt or MinutesBefore.
+type CreateReminderRequest struct {
+	RemindAt      *time.Time      `json:"remind_at" validate:"required_without=MinutesBefore,excluded_with=MinutesBefore"`
+	MinutesBefore *int            `json:"minutes_before" validate:"required_without=RemindAt,omitempty,min=0,max=525600"`
+	Channel       ReminderChannel `json:"channel" validate:"required,oneof=email webhook in_app"`
+	WebhookURL    string          `json:"webhook_url" validate:"required_if=Channel webhook,omitempty,https_url,max=2048"`
+}
+
+type ReminderResponse struct {
+	ID            uint            `json:"id"`
+	TaskID        uint            `json:"task_id"`
+	RemindAt      *time.Time      `json:"remind_at"`
+	MinutesBefore *int            `json:"minutes_before"`
+	Channel       ReminderChannel `json:"channel"`
+	WebhookURL    string          `json:"webhook_url,omitempty"`
+	Status        ReminderStatus  `json:"status"`
+	Attempts      int             `json:"attempts"`
+	SentAt        *time.Time      `json:"sent_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+)
+
+type ShareRole string
+
+const (
+	// ShareViewer can see the shared item and everything in it.
+	ShareViewer ShareRole = "viewer"
+	// ShareEditor can also change it, but not delete or reshare it.
+	ShareEditor ShareRole = "editor"
+)
+
+// Share gives another user access to a task, including its subtasks, or to
+// a list, including its tasks. Exactly one of TaskID and ListID is set.
+type Share struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	TaskID     *uint     `gorm:"uniqueIndex:idx_shares_task_user,priority:1" json:"task_id"`
+	ListID     *uint     `gorm:"uniqueIndex:idx_shares_list_user,priority:1" json:"list_id"`
+	UserID     uint      `gorm:"not null;index;uniqueIndex:idx_shares_task_user,priority:2;uniqueIndex:idx_shares_list_user,priority:2" json:"user_id"`
+	User       User      `json:"-"`
+	SharedByID uint      `gorm:"not null" json:"shared_by_id"`
+	Role       ShareRole `gorm:"type:varchar(10);not null" json:"role"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type CreateShareRequest struct {
+	Email string    `json:"email" validate:"required,email"`
+	Role  ShareRole `json:"role" validate:"required,oneof=viewer editor"`
+}
+
+type UpdateShareRequest struct {
+	Role ShareRole `json:"role" validate:"required,oneof=viewer editor"`
+}
+
+type ShareResponse struct {
+	ID         uint      `json:"id"`
+	TaskID     *uint     `json:"task_id,omitempty"`
+	ListID     *uint     `json:"list_id,omitempty"`
+	UserID     uint      `json:"user_id"`
+	Username   string    `json:"username"`
+	Email      string    `json:"email"`
+	SharedByID uint      `json:"shared_by_id"`
+	Role       ShareRole `json:"role"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type SharedTaskResponse struct {
+	Role       ShareRole    `json:"role"`
+	SharedByID uint         `json:"shared_by_id"`
+	Task       TaskResponse `json:"task"`
+}
+
+type SharedListResponse struct {
+	Role       ShareRole    `json:"role"`
+	SharedByID uint         `json:"shared_by_id"`
+	List       ListResponse `json:"list"`
+}
+
+type SharedWithMeResponse struct {
+	Tasks []SharedTaskResponse `json:"tasks"`
+	Lists []SharedListResponse `json:"lists"`
+}
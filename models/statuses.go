@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+type StatusCategory string
+
+const (
+	StatusCategoryTodo       StatusCategory = "todo"
+	StatusCategoryInProgress StatusCategory = "in_progress"
+	// StatusCategoryDone marks the statuses of completed tasks.
+	StatusCategoryDone StatusCategory = "done"
+)
+
+// TaskStatus is a column of a list's workflow. Every task in a list has one of
+// the list's statuses, and is completed exactly when its status is in the done
+// category.
+type TaskStatus struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	ListID    uint           `gorm:"not null;uniqueIndex:idx_task_statuses_name,priority:1" json:"list_id"`
+	Name      string         `gorm:"not null;uniqueIndex:idx_task_statuses_name,priority:2" json:"name"`
+	Category  StatusCategory `gorm:"type:varchar(20);not null" json:"category"`
+	Position  int            `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func (s TaskStatus) IsDone() bool {
+	return s.Category == StatusCategoryDone
+}
+
+// DefaultStatuses are the statuses every new list starts with.
+var DefaultStatuses = []TaskStatus{
+	{Name: "Backlog", Category: StatusCategoryTodo, Position: 1},
+	{Name: "In Progress", Category: StatusCategoryInProgress, Position: 2},
+	{Name: "Review", Category: StatusCategoryInProgress, Position: 3},
+	{Name: "Done", Category: StatusCategoryDone, Position: 4},
+}
+
+type CreateStatusRequest struct {
+	Name     string         `json:"name" validate:"required,min=1,max=50"`
+	Category StatusCategory `json:"category" validate:"required,oneof=todo in_progress done"`
+	// Position defaults to after the list's last status.
+	Position *int `json:"position"`
+}
+
+type UpdateStatusRequest struct {
+	Name     string         `json:"name" validate:"omitempty,min=1,max=50"`
+	Category StatusCategory `json:"category" validate:"omitempty,oneof=todo in_progress done"`
+	Position *int           `json:"position"`
+}
+
+type StatusResponse struct {
+	ID       uint           `json:"id"`
+	ListID   uint           `json:"list_id"`
+	Name     string         `json:"name"`
+	Category StatusCategory `json:"category"`
+	Position int            `json:"position"`
+}
+
+// BoardColumn holds the top-level tasks of a list that have the status.
+type BoardColumn struct {
+	Status StatusResponse `json:"status"`
+	Tasks  []TaskResponse `json:"tasks"`
+}
+
+type BoardResponse struct {
+	List    ListResponse  `json:"list"`
+	Columns []BoardColumn `json:"columns"`
+}
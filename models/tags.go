@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Tag struct {
+	gorm.Model
+	Name   string `gorm:"not null;uniqueIndex:idx_tags_user_name,priority:2" json:"name"`
+	Color  string `gorm:"type:varchar(7);not null" json:"color"`
+	UserID uint   `gorm:"not null;uniqueIndex:idx_tags_user_name,priority:1" json:"user_id"`
+}
+
+type CreateTagRequest struct {
+	Name  string `json:"name" validate:"required,min=1,max=50"`
+	Color string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+type UpdateTagRequest struct {
+	Name  string `json:"name" validate:"omitempty,min=1,max=50"`
+	Color string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+type TagResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
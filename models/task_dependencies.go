@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// TaskDependency records that TaskID can't be completed until BlockedByID is.
+type TaskDependency struct {
+	TaskID      uint      `gorm:"primaryKey;autoIncrement:false" json:"task_id"`
+	BlockedByID uint      `gorm:"primaryKey;autoIncrement:false;index" json:"blocked_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type AddDependencyRequest struct {
+	BlockedByID uint `json:"blocked_by_id" validate:"required"`
+}
+
+// TaskDependenciesResponse lists the tasks a task is blocked by and the tasks
+// it blocks in turn.
+type TaskDependenciesResponse struct {
+	BlockedBy []TaskResponse `json:"blocked_by"`
+	Blocking  []TaskResponse `json:"blocking"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type TaskEventType string
+
+const (
+	TaskEventCreated   TaskEventType = "created"
+	TaskEventUpdated   TaskEventType = "updated"
+	TaskEventCompleted TaskEventType = "completed"
+	TaskEventReopened  TaskEventType = "reopened"
+	TaskEventDeleted   TaskEventType = "deleted"
+	TaskEventRestored  TaskEventType = "restored"
+)
+
+// TaskEvent records one change made to a task, by whom and when. Events are
+// append-only, so they have no UpdatedAt or soft delete.
+type TaskEvent struct {
+	ID        uint          `gorm:"primarykey;index:idx_task_events_task_id_id,priority:2" json:"id"`
+	TaskID    uint          `gorm:"not null;index:idx_task_events_task_id_id,priority:1" json:"task_id"`
+	ActorID   uint          `gorm:"not null" json:"actor_id"`
+	Type      TaskEventType `gorm:"type:varchar(20);not null" json:"type"`
+	Changes   TaskChanges   `gorm:"type:jsonb" json:"changes"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// FieldChange holds the value of a task field before and after a change.
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// TaskChanges maps the JSON name of each changed field to its change.
+type TaskChanges map[string]FieldChange
+
+func (c TaskChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+func (c *TaskChanges) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	}
+	return errors.New("unsupported type for TaskChanges")
+}
+
+type TaskEventResponse struct {
+	ID        uint          `json:"id"`
+	TaskID    uint          `json:"task_id"`
+	ActorID   uint          `json:"actor_id"`
+	Type      TaskEventType `json:"type"`
+	Changes   TaskChanges   `json:"changes,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type TaskHistoryResponse struct {
+	Events     []TaskEventResponse `json:"events"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
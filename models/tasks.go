@@ -6,31 +6,212 @@ import (
 	"gorm.io/gorm"
 )
 
+type TaskPriority string
+
+const (
+	PriorityNone   TaskPriority = "none"
+	PriorityLow    TaskPriority = "low"
+	PriorityMedium TaskPriority = "medium"
+	PriorityHigh   TaskPriority = "high"
+	PriorityUrgent TaskPriority = "urgent"
+)
+
+// TaskPriorities lists every priority level from least to most important.
+var TaskPriorities = []TaskPriority{PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+
+// Rank returns the position of the priority in TaskPriorities, or -1 if the
+// priority is unknown.
+func (p TaskPriority) Rank() int {
+	for i, priority := range TaskPriorities {
+		if priority == p {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p TaskPriority) IsValid() bool {
+	return p.Rank() >= 0
+}
+
 type Task struct {
 	gorm.Model
-	Title     string `gorm:"not null" json:"title"`
-	Content   string `json:"content"`
-	Completed bool   `gorm:"default:false" json:"completed"`
-	UserID    uint   `gorm:"not null" json:"user_id"`
+	Title     string       `gorm:"not null" json:"title"`
+	Content   string       `json:"content"`
+	Completed bool         `gorm:"default:false" json:"completed"`
+	Priority  TaskPriority `gorm:"type:varchar(10);not null;default:'none';index" json:"priority"`
+	DueDate   *time.Time   `gorm:"index" json:"due_date"`
+	AllDay    bool         `gorm:"default:false" json:"all_day"`
+	TimeZone  string       `gorm:"not null;default:'UTC'" json:"time_zone"`
+	UserID    uint         `gorm:"not null" json:"user_id"`
+	ListID    *uint        `gorm:"index" json:"list_id"`
+	ParentID  *uint        `gorm:"index" json:"parent_id"`
+	Position  float64      `gorm:"not null;default:0" json:"position"`
+	// WorkspaceID is the workspace the task belongs to, or nil if it is in
+	// its owner's personal space.
+	WorkspaceID *uint `gorm:"index" json:"workspace_id"`
+	// AssigneeID is the user the task was delegated to, who need not be its
+	// owner.
+	AssigneeID *uint `gorm:"index" json:"assignee_id"`
+	// StatusID is one of the statuses of the task's list, kept in step with
+	// Completed. Tasks outside any list have no status.
+	StatusID *uint `gorm:"index" json:"status_id"`
+	// RecurrenceRule is an RFC 5545 RRULE applied to the due date. Every
+	// occurrence after the first points at the first one through SeriesID.
+	RecurrenceRule string `gorm:"type:varchar(255)" json:"recurrence_rule"`
+	SeriesID       *uint  `gorm:"index" json:"series_id"`
+	Tags           []Tag  `gorm:"many2many:task_tags;" json:"tags"`
+
+	// Computed by the repository when loading tasks; never stored.
+	SubtaskCount          int `gorm:"->;-:migration" json:"-"`
+	CompletedSubtaskCount int `gorm:"->;-:migration" json:"-"`
+	CommentCount          int `gorm:"->;-:migration" json:"-"`
+	// Blocked is set while any task this one depends on is still open.
+	Blocked bool `gorm:"->;-:migration" json:"-"`
+	// TrackedSeconds is the time tracked on the task by everyone, counting
+	// running timers up to now.
+	TrackedSeconds int64 `gorm:"->;-:migration" json:"-"`
 }
 
 type CreateTaskRequest struct {
-	Title   string `json:"title" validate:"required,min=1,max=100"`
-	Content string `json:"content"`
+	Title          string       `json:"title" validate:"required,min=1,max=100"`
+	Content        string       `json:"content"`
+	Priority       TaskPriority `json:"priority" validate:"omitempty,priority"`
+	DueDate        *time.Time   `json:"due_date"`
+	AllDay         bool         `json:"all_day"`
+	TimeZone       string       `json:"time_zone" validate:"omitempty,timezone"`
+	TagIDs         []uint       `json:"tag_ids"`
+	ListID         *uint        `json:"list_id"`
+	RecurrenceRule string       `json:"recurrence_rule" validate:"omitempty,max=255"`
 }
 
 type UpdateTaskRequest struct {
-	Title     string `json:"title" validate:"omitempty,min=1,max=100"`
-	Content   string `json:"content"`
-	Completed *bool  `json:"completed"`
+	Title        string       `json:"title" validate:"omitempty,min=1,max=100"`
+	Content      string       `json:"content"`
+	Completed    *bool        `json:"completed"`
+	Priority     TaskPriority `json:"priority" validate:"omitempty,priority"`
+	DueDate      *time.Time   `json:"due_date"`
+	ClearDueDate bool         `json:"clear_due_date"`
+	AllDay       *bool        `json:"all_day"`
+	TimeZone     string       `json:"time_zone" validate:"omitempty,timezone"`
+	TagIDs       *[]uint      `json:"tag_ids"`
+	ListID       *uint        `json:"list_id"`
+	// StatusID moves the task to another status of its list, completing or
+	// reopening it to match.
+	StatusID *uint `json:"status_id"`
+	// Force completes the task even if it is blocked.
+	Force bool `json:"force"`
 }
 
 type TaskResponse struct {
-	ID        uint      `json:"id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
-	Completed bool      `json:"completed"`
-	UserID    uint      `json:"user_id"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID             uint          `json:"id"`
+	Title          string        `json:"title"`
+	Content        string        `json:"content"`
+	Completed      bool          `json:"completed"`
+	Priority       TaskPriority  `json:"priority"`
+	DueDate        *time.Time    `json:"due_date"`
+	AllDay         bool          `json:"all_day"`
+	TimeZone       string        `json:"time_zone"`
+	UserID         uint          `json:"user_id"`
+	WorkspaceID    *uint         `json:"workspace_id"`
+	ListID         *uint         `json:"list_id"`
+	ParentID       *uint         `json:"parent_id"`
+	Position       float64       `json:"position"`
+	AssigneeID     *uint         `json:"assignee_id"`
+	StatusID       *uint         `json:"status_id"`
+	Progress       *TaskProgress `json:"progress,omitempty"`
+	CommentCount   int           `json:"comment_count"`
+	Blocked        bool          `json:"blocked"`
+	TrackedSeconds int64         `json:"tracked_seconds"`
+	RecurrenceRule string        `json:"recurrence_rule,omitempty"`
+	SeriesID       *uint         `json:"series_id,omitempty"`
+	Tags           []TagResponse `json:"tags"`
+	CreatedAt      time.Time     `json:"createdAt"`
+	UpdatedAt      time.Time     `json:"updatedAt"`
+	DeletedAt      *time.Time    `json:"deleted_at,omitempty"`
+}
+
+// TaskProgress counts a task's direct subtasks. It is only reported for tasks
+// that have subtasks.
+type TaskProgress struct {
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+}
+
+type ReorderSubtasksRequest struct {
+	TaskIDs []uint `json:"task_ids" validate:"required,min=1"`
+}
+
+// MoveTaskRequest places a task between two of its siblings. BeforeID is the
+// task that should come right before it and AfterID the one right after it;
+// either can be left out to move the task next to a single neighbour.
+type MoveTaskRequest struct {
+	BeforeID *uint `json:"before_id"`
+	AfterID  *uint `json:"after_id"`
+}
+
+type AssignTaskRequest struct {
+	UserID uint `json:"user_id" validate:"required"`
+}
+
+type SetRecurrenceRequest struct {
+	Rule string `json:"rule" validate:"required,max=255"`
+}
+
+type BatchAction string
+
+const (
+	BatchComplete    BatchAction = "complete"
+	BatchUncomplete  BatchAction = "uncomplete"
+	BatchDelete      BatchAction = "delete"
+	BatchMove        BatchAction = "move"
+	BatchTag         BatchAction = "tag"
+	BatchSetPriority BatchAction = "set_priority"
+)
+
+// BatchOperation applies one action to many tasks. ListID is required to move
+// tasks, TagIDs to tag them and Priority to set their priority.
+type BatchOperation struct {
+	Action   BatchAction  `json:"action" validate:"required,oneof=complete uncomplete delete move tag set_priority"`
+	TaskIDs  []uint       `json:"task_ids" validate:"required,min=1,max=500"`
+	ListID   *uint        `json:"list_id" validate:"required_if=Action move"`
+	TagIDs   []uint       `json:"tag_ids" validate:"required_if=Action tag"`
+	Priority TaskPriority `json:"priority" validate:"required_if=Action set_priority,omitempty,priority"`
+	// Force completes blocked tasks too.
+	Force bool `json:"force"`
+}
+
+type BatchTaskRequest struct {
+	Operations []BatchOperation `json:"operations" validate:"required,min=1,max=20,dive"`
+}
+
+// BatchItemResult reports how one operation went for one task. Operation is
+// the index of the operation in the request.
+type BatchItemResult struct {
+	Operation int    `json:"operation"`
+	TaskID    uint   `json:"task_id"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+}
+
+type BatchTaskResponse struct {
+	Results []BatchItemResult `json:"results"`
+}
+
+type TaskBucketsResponse struct {
+	Overdue   []TaskResponse `json:"overdue"`
+	Today     []TaskResponse `json:"today"`
+	Upcoming  []TaskResponse `json:"upcoming"`
+	NoDueDate []TaskResponse `json:"no_due_date"`
+}
+
+type TaskPageResponse struct {
+	Tasks      []TaskResponse `json:"tasks"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+type TaskSearchResultResponse struct {
+	Task    TaskResponse `json:"task"`
+	Rank    float64      `json:"rank"`
+	Snippet string       `json:"snippet"`
 }
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// TaskTemplate is a reusable task tree, such as a recurring checklist, that
+// can be instantiated into new tasks. Templates saved in a workspace are
+// shared with its members; the rest belong to their creator alone.
+type TaskTemplate struct {
+	ID          uint   `gorm:"primarykey" json:"id"`
+	Name        string `gorm:"not null" json:"name"`
+	UserID      uint   `gorm:"not null;index" json:"user_id"`
+	WorkspaceID *uint  `gorm:"index" json:"workspace_id"`
+	// Root is the template's top-level task, with its subtasks nested inside.
+	// It is stored as rows of TemplateTask and assembled by the repository.
+	Root      TemplateTask `gorm:"-" json:"root"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// TemplateTask is one task of a template. Due dates are kept as offsets from
+// the moment the template is instantiated, which for all-day tasks is counted
+// from the start of that day. Tags are kept by name, so the template works for
+// anyone instantiating it with their own tags.
+type TemplateTask struct {
+	ID               uint           `gorm:"primarykey" json:"-"`
+	TemplateID       uint           `gorm:"not null;index" json:"-"`
+	ParentID         *uint          `gorm:"index" json:"-"`
+	Position         int            `gorm:"not null;default:0" json:"-"`
+	Title            string         `gorm:"not null" json:"title"`
+	Content          string         `json:"content"`
+	Priority         TaskPriority   `gorm:"type:varchar(10);not null;default:'none'" json:"priority"`
+	AllDay           bool           `gorm:"default:false" json:"all_day"`
+	DueOffsetMinutes *int           `json:"due_offset_minutes"`
+	Tags             []string       `gorm:"type:jsonb;serializer:json" json:"tags"`
+	Subtasks         []TemplateTask `gorm:"-" json:"subtasks"`
+}
+
+type SaveTemplateRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+// InstantiateTemplateRequest creates the template's tasks, optionally with a
+// different title for the top-level task. Due dates count from StartAt, which
+// defaults to now, with all-day tasks counting from the start of that day in
+// TimeZone or the user's own.
+type InstantiateTemplateRequest struct {
+	Title    string     `json:"title" validate:"omitempty,min=1,max=100"`
+	ListID   *uint      `json:"list_id"`
+	StartAt  *time.Time `json:"start_at"`
+	TimeZone string     `json:"time_zone" validate:"omitempty,timezone"`
+}
+
+type TemplateTaskResponse struct {
+	Title            string                 `json:"title"`
+	Content          string                 `json:"content"`
+	Priority         TaskPriority           `json:"priority"`
+	AllDay           bool                   `json:"all_day"`
+	DueOffsetMinutes *int                   `json:"due_offset_minutes"`
+	Tags             []string               `json:"tags"`
+	Subtasks         []TemplateTaskResponse `json:"subtasks"`
+}
+
+type TemplateResponse struct {
+	ID          uint                 `json:"id"`
+	Name        string               `json:"name"`
+	UserID      uint                 `json:"user_id"`
+	WorkspaceID *uint                `json:"workspace_id"`
+	Root        TemplateTaskResponse `json:"root"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// TimeEntry is a span of time a user spent on a task. Entries with no EndedAt
+// are running timers, of which each user has at most one.
+type TimeEntry struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	TaskID    uint       `gorm:"not null;index" json:"task_id"`
+	Task      Task       `json:"-"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	StartedAt time.Time  `gorm:"not null;index" json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Note      string     `gorm:"type:varchar(500)" json:"note"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Duration is how long the entry lasted, or has lasted by now if it is still
+// running.
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	if e.EndedAt != nil {
+		return e.EndedAt.Sub(e.StartedAt)
+	}
+	return max(now.Sub(e.StartedAt), 0)
+}
+
+type StartTimerRequest struct {
+	Note string `json:"note" validate:"max=500"`
+}
+
+type CreateTimeEntryRequest struct {
+	StartedAt time.Time `json:"started_at" validate:"required"`
+	EndedAt   time.Time `json:"ended_at" validate:"required,gtfield=StartedAt"`
+	Note      string    `json:"note" validate:"max=500"`
+}
+
+type UpdateTimeEntryRequest struct {
+	StartedAt *time.Time `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Note      *string    `json:"note" validate:"omitempty,max=500"`
+}
+
+type TimeEntryResponse struct {
+	ID              uint       `json:"id"`
+	TaskID          uint       `json:"task_id"`
+	UserID          uint       `json:"user_id"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	Running         bool       `json:"running"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	Note            string     `json:"note"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// TimeReportTask is the time tracked on one task within a report period.
+type TimeReportTask struct {
+	TaskID  uint   `json:"task_id"`
+	Title   string `json:"title"`
+	Seconds int64  `json:"seconds"`
+}
+
+// TimeReportDay is the time tracked on one calendar day, in the report's time
+// zone.
+type TimeReportDay struct {
+	Date    string           `json:"date"`
+	Seconds int64            `json:"seconds"`
+	Tasks   []TimeReportTask `json:"tasks"`
+}
+
+type TimeReportResponse struct {
+	From         string          `json:"from"`
+	To           string          `json:"to"`
+	TimeZone     string          `json:"time_zone"`
+	TotalSeconds int64           `json:"total_seconds"`
+	Days         []TimeReportDay `json:"days"`
+}
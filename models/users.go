@@ -11,17 +11,23 @@ type User struct {
 	Username string `gorm:"not null" json:"username" validate:"required,min=3,max=30"`
 	Email    string `gorm:"uniqueIndex;not null" json:"email" validate:"required,email"`
 	Password string `gorm:"not null" json:"-" validate:"required,password"`
-	Tasks    []Task
+	TimeZone string `gorm:"not null;default:'UTC'" json:"time_zone" validate:"omitempty,timezone"`
+	// ActiveWorkspaceID is the workspace the user's tokens are issued for, or
+	// nil for their personal space.
+	ActiveWorkspaceID *uint `json:"active_workspace_id"`
+	Tasks             []Task
 }
 
 type UserClaims struct {
-	UserID uint `json:"user_id"`
+	UserID      uint  `json:"user_id"`
+	WorkspaceID *uint `json:"workspace_id,omitempty"`
 }
 
 type RegisterRequest struct {
 	Username string `json:"username" validate:"required"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,password"`
+	TimeZone string `json:"time_zone" validate:"omitempty,timezone"`
 }
 
 type LoginRequest struct {
@@ -32,6 +38,7 @@ type LoginRequest struct {
 type UpdateUserRequest struct {
 	Username string `json:"username"`
 	Email    string `json:"email" validate:"omitempty,email"`
+	TimeZone string `json:"time_zone" validate:"omitempty,timezone"`
 }
 
 type RefreshRequest struct {
@@ -39,9 +46,11 @@ type RefreshRequest struct {
 }
 
 type UserResponse struct {
-	ID        uint      `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID                uint      `json:"id"`
+	Username          string    `json:"username"`
+	Email             string    `json:"email"`
+	TimeZone          string    `json:"time_zone"`
+	ActiveWorkspaceID *uint     `json:"active_workspace_id"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
 }
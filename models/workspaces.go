@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WorkspaceRole string
+
+const (
+	// WorkspaceRoleOwner created the workspace and can do anything in it.
+	WorkspaceRoleOwner WorkspaceRole = "owner"
+	// WorkspaceRoleAdmin manages members and invitations and any list or task.
+	WorkspaceRoleAdmin WorkspaceRole = "admin"
+	// WorkspaceRoleMember creates and edits lists and tasks.
+	WorkspaceRoleMember WorkspaceRole = "member"
+	// WorkspaceRoleGuest can only see the workspace's lists and tasks.
+	WorkspaceRoleGuest WorkspaceRole = "guest"
+)
+
+// Workspace is a space shared by a team. Lists and tasks with a WorkspaceID
+// belong to it; the rest belong to their owner's personal space.
+type Workspace struct {
+	gorm.Model
+	Name    string `gorm:"not null" json:"name"`
+	OwnerID uint   `gorm:"not null;index" json:"owner_id"`
+}
+
+type WorkspaceMember struct {
+	ID          uint          `gorm:"primarykey" json:"id"`
+	WorkspaceID uint          `gorm:"not null;uniqueIndex:idx_workspace_members_user,priority:1" json:"workspace_id"`
+	Workspace   Workspace     `json:"-"`
+	UserID      uint          `gorm:"not null;index;uniqueIndex:idx_workspace_members_user,priority:2" json:"user_id"`
+	User        User          `json:"-"`
+	Role        WorkspaceRole `gorm:"type:varchar(10);not null" json:"role"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+)
+
+// WorkspaceInvitation invites whoever registered with Email to join the
+// workspace. The invitee accepts or declines it with its Token.
+type WorkspaceInvitation struct {
+	ID          uint             `gorm:"primarykey" json:"id"`
+	WorkspaceID uint             `gorm:"not null;index" json:"workspace_id"`
+	Workspace   Workspace        `json:"-"`
+	Email       string           `gorm:"not null;index" json:"email"`
+	Role        WorkspaceRole    `gorm:"type:varchar(10);not null" json:"role"`
+	Token       string           `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	InvitedByID uint             `gorm:"not null" json:"invited_by_id"`
+	Status      InvitationStatus `gorm:"type:varchar(10);not null;default:'pending'" json:"status"`
+	ExpiresAt   time.Time        `gorm:"not null" json:"expires_at"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+type UpdateWorkspaceRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+type UpdateMemberRequest struct {
+	Role WorkspaceRole `json:"role" validate:"required,oneof=admin member guest"`
+}
+
+type InviteMemberRequest struct {
+	Email string        `json:"email" validate:"required,email"`
+	Role  WorkspaceRole `json:"role" validate:"required,oneof=admin member guest"`
+}
+
+// SwitchWorkspaceRequest selects the workspace new tokens are issued for;
+// leaving WorkspaceID out selects the personal space.
+type SwitchWorkspaceRequest struct {
+	WorkspaceID *uint `json:"workspace_id"`
+}
+
+type WorkspaceResponse struct {
+	ID        uint          `json:"id"`
+	Name      string        `json:"name"`
+	OwnerID   uint          `json:"owner_id"`
+	Role      WorkspaceRole `json:"role"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+type WorkspaceMemberResponse struct {
+	UserID    uint          `json:"user_id"`
+	Username  string        `json:"username"`
+	Email     string        `json:"email"`
+	Role      WorkspaceRole `json:"role"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// InvitationResponse includes the token only for the invitee and for the
+// admin who just sent it, so it can be passed along.
+type InvitationResponse struct {
+	ID            uint             `json:"id"`
+	WorkspaceID   uint             `json:"workspace_id"`
+	WorkspaceName string           `json:"workspace_name,omitempty"`
+	Email         string           `json:"email"`
+	Role          WorkspaceRole    `json:"role"`
+	Token         string           `json:"token,omitempty"`
+	InvitedByID   uint             `json:"invited_by_id"`
+	Status        InvitationStatus `json:"status"`
+	ExpiresAt     time.Time        `json:"expires_at"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+// InAppNotifier stores messages as notifications the user sees in the app.
+type InAppNotifier struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewInAppNotifier(notificationRepo repository.NotificationRepository) *InAppNotifier {
+	return &InAppNotifier{notificationRepo: notificationRepo}
+}
+
+func (n *InAppNotifier) Notify(ctx context.Context, msg Message) error {
+	notification := &models.Notification{
+		UserID: msg.UserID,
+		Title:  msg.Subject,
+		Body:   msg.Body,
+	}
+	if msg.TaskID != 0 {
+		notification.TaskID = &msg.TaskID
+	}
+	if msg.ReminderID != 0 {
+		notification.ReminderID = &msg.ReminderID
+	}
+	return n.notificationRepo.Create(notification)
+}
@@ -0,0 +1,75 @@
+// Package notify delivers reminders and other messages to users over the
+// channels they choose.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+var (
+	ErrNoRecipient = errors.New("message has no recipient for this channel")
+)
+
+// Message is a single notification for one user. Each Notifier reads the
+// recipient it needs, such as Email or WebhookURL, and ignores the rest.
+type Message struct {
+	UserID     uint
+	Email      string
+	WebhookURL string
+	TaskID     uint
+	ReminderID uint
+	Subject    string
+	Body       string
+	DueDate    *time.Time
+}
+
+// Notifier delivers messages over one channel. Notify must respect the
+// context's deadline, and an error means the message may be retried.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// NewFromEnv builds a notifier for every available reminder channel. In-app
+// and webhook notifications are always available, with webhooks signed when
+// WEBHOOK_SECRET is set; email is available only when SMTP_HOST is set, along
+// with SMTP_PORT (587 by default), SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM.
+func NewFromEnv(notificationRepo repository.NotificationRepository) (map[models.ReminderChannel]Notifier, error) {
+	notifiers := map[models.ReminderChannel]Notifier{
+		models.ReminderInApp:   NewInAppNotifier(notificationRepo),
+		models.ReminderWebhook: NewWebhookNotifier(os.Getenv("WEBHOOK_SECRET")),
+	}
+
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return notifiers, nil
+	}
+	port := defaultSMTPPort
+	if value := os.Getenv("SMTP_PORT"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 65535 {
+			return nil, fmt.Errorf("SMTP_PORT must be a port number, got %q", value)
+		}
+		port = parsed
+	}
+	from := os.Getenv("SMTP_FROM")
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("SMTP_FROM must be an email address when SMTP_HOST is set, got %q", from)
+	}
+	notifiers[models.ReminderEmail] = NewSMTPNotifier(SMTPConfig{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	})
+	return notifiers, nil
+}
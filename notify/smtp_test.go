@@ -0,0 +1,181 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpSession is what a fakeSMTPServer received during one session.
+type smtpSession struct {
+	auth       string
+	from       string
+	recipients []string
+	data       string
+}
+
+// fakeSMTPServer accepts a single session, speaking just enough SMTP for the
+// notifier: EHLO with AUTH PLAIN and no STARTTLS, so the exchange stays in
+// plain text, then MAIL, RCPT, DATA and QUIT. Any other command is rejected.
+// rcptReply is how it answers RCPT.
+func fakeSMTPServer(t *testing.T, rcptReply string) (host string, port int, sessions <-chan smtpSession) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	done := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+		text := textproto.NewConn(conn)
+		var session smtpSession
+		text.PrintfLine("220 fake.smtp ESMTP ready")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO":
+				text.PrintfLine("250-fake.smtp")
+				text.PrintfLine("250 AUTH PLAIN")
+			case "AUTH":
+				mechanism, response, _ := strings.Cut(arg, " ")
+				credentials, err := base64.StdEncoding.DecodeString(response)
+				if mechanism != "PLAIN" || err != nil {
+					text.PrintfLine("535 authentication failed")
+					continue
+				}
+				session.auth = string(credentials)
+				text.PrintfLine("235 authenticated")
+			case "MAIL":
+				session.from = arg
+				text.PrintfLine("250 ok")
+			case "RCPT":
+				session.recipients = append(session.recipients, arg)
+				text.PrintfLine("%s", rcptReply)
+			case "DATA":
+				text.PrintfLine("354 go ahead")
+				data, err := text.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				session.data = string(data)
+				text.PrintfLine("250 queued")
+			case "QUIT":
+				text.PrintfLine("221 bye")
+				done <- session
+				return
+			default:
+				text.PrintfLine("502 command not implemented")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, done
+}
+
+func TestSMTPNotifierSendsEmail(t *testing.T) {
+	host, port, sessions := fakeSMTPServer(t, "250 ok")
+	notifier := NewSMTPNotifier(SMTPConfig{
+		Host:     host,
+		Port:     port,
+		Username: "listario",
+		Password: "s3cret",
+		From:     "Listario <reminders@example.com>",
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := notifier.Notify(ctx, Message{
+		Email:   "ana@example.com",
+		Subject: "Reminder: Pay rent ✓",
+		Body:    "Due today.\n.hidden line\r\nBye",
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	var session smtpSession
+	select {
+	case session = <-sessions:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server never saw the session end")
+	}
+
+	if want := "\x00listario\x00s3cret"; session.auth != want {
+		t.Errorf("AUTH PLAIN credentials = %q, want %q", session.auth, want)
+	}
+	if want := "FROM:<reminders@example.com>"; !strings.HasPrefix(session.from, want) {
+		t.Errorf("MAIL %s, want %s", session.from, want)
+	}
+	if len(session.recipients) != 1 || session.recipients[0] != "TO:<ana@example.com>" {
+		t.Errorf("RCPT %v, want a single TO:<ana@example.com>", session.recipients)
+	}
+
+	header, body, ok := strings.Cut(session.data, "\n\n")
+	if !ok {
+		t.Fatalf("message has no blank line between its header and body:\n%s", session.data)
+	}
+	headers := make(map[string]string)
+	for _, line := range strings.Split(header, "\n") {
+		name, value, _ := strings.Cut(line, ": ")
+		headers[name] = value
+	}
+	wantHeaders := map[string]string{
+		"From":         `"Listario" <reminders@example.com>`,
+		"To":           "<ana@example.com>",
+		"Subject":      "=?utf-8?q?Reminder:_Pay_rent_=E2=9C=93?=",
+		"Content-Type": "text/plain; charset=utf-8",
+	}
+	for name, want := range wantHeaders {
+		if got := headers[name]; got != want {
+			t.Errorf("%s header = %q, want %q", name, got, want)
+		}
+	}
+	if _, err := time.Parse(time.RFC1123Z, headers["Date"]); err != nil {
+		t.Errorf("Date header %q is not an RFC 1123 date: %v", headers["Date"], err)
+	}
+	// Reading the data undoes the dot-stuffing and turns CRLFs into LFs, so
+	// this is the body as the recipient reads it.
+	if want := "Due today.\n.hidden line\nBye\n"; body != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+}
+
+func TestSMTPNotifierRejectsMissingRecipient(t *testing.T) {
+	notifier := NewSMTPNotifier(SMTPConfig{Host: "127.0.0.1", Port: defaultSMTPPort, From: "reminders@example.com"})
+
+	err := notifier.Notify(context.Background(), Message{Subject: "Reminder", Body: "Due today."})
+	if !errors.Is(err, ErrNoRecipient) {
+		t.Errorf("Notify without an email returned %v, want ErrNoRecipient", err)
+	}
+}
+
+func TestSMTPNotifierReportsRejectedRecipient(t *testing.T) {
+	host, port, _ := fakeSMTPServer(t, "550 no such user")
+	notifier := NewSMTPNotifier(SMTPConfig{Host: host, Port: port, From: "reminders@example.com"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := notifier.Notify(ctx, Message{Email: "nobody@example.com", Subject: "Reminder", Body: "Due today."})
+	var protoErr *textproto.Error
+	if !errors.As(err, &protoErr) || protoErr.Code != 550 {
+		t.Errorf("Notify to a rejected recipient returned %v, want the server's 550 reply", err)
+	}
+}
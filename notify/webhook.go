@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body, keyed by
+// the webhook secret, so receivers can check a webhook came from us.
+const SignatureHeader = "X-Listario-Signature"
+
+// webhookTimeout bounds a whole delivery, on top of the caller's context.
+const webhookTimeout = 30 * time.Second
+
+var (
+	ErrInsecureWebhook  = errors.New("webhook URL must use https")
+	ErrForbiddenAddress = errors.New("webhook host resolves to a loopback, private or otherwise internal address")
+)
+
+// reservedPrefixes are the internal ranges netip.Addr has no predicate for.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// webhookPayload is the JSON body posted to webhooks.
+type webhookPayload struct {
+	Event      string     `json:"event"`
+	TaskID     uint       `json:"task_id"`
+	ReminderID uint       `json:"reminder_id"`
+	UserID     uint       `json:"user_id"`
+	Subject    string     `json:"subject"`
+	Body       string     `json:"body"`
+	DueDate    *time.Time `json:"due_date"`
+	SentAt     time.Time  `json:"sent_at"`
+}
+
+// WebhookNotifier posts messages as JSON to the message's webhook URL. Any
+// response other than a 2xx counts as a failed delivery. Since users choose
+// the URLs, only https is used, redirects aren't followed and connections to
+// internal addresses are refused, so webhooks can't reach the server's own
+// network.
+type WebhookNotifier struct {
+	client *http.Client
+	secret string
+}
+
+func NewWebhookNotifier(secret string) *WebhookNotifier {
+	return &WebhookNotifier{client: newWebhookClient(refuseInternalAddress), secret: secret}
+}
+
+// newWebhookClient returns a client whose connections are vetted by control
+// once the host has been resolved, so a name can't point it elsewhere.
+func newWebhookClient(control func(network, address string, conn syscall.RawConn) error) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would connect on our behalf, out of the dialer's sight.
+	transport.Proxy = nil
+	return &http.Client{
+		Transport: transport,
+		Timeout:   webhookTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// refuseInternalAddress is a net.Dialer Control hook that refuses to connect
+// to loopback, private, link-local, unspecified and other reserved addresses.
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return ErrForbiddenAddress
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(ip) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, msg Message) error {
+	if msg.WebhookURL == "" {
+		return ErrNoRecipient
+	}
+	// Reminders saved before https was required may still hold other URLs.
+	if target, err := url.Parse(msg.WebhookURL); err != nil || !strings.EqualFold(target.Scheme, "https") {
+		return ErrInsecureWebhook
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		Event:      "reminder",
+		TaskID:     msg.TaskID,
+		ReminderID: msg.ReminderID,
+		UserID:     msg.UserID,
+		Subject:    msg.Subject,
+		Body:       msg.Body,
+		DueDate:    msg.DueDate,
+		SentAt:     time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.secret))
+		mac.Write(payload)
+		req.Header.Set(SignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRefuseInternalAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		refused bool
+	}{
+		{address: "127.0.0.1:443", refused: true},
+		{address: "127.8.9.10:443", refused: true},
+		{address: "[::1]:443", refused: true},
+		{address: "[::ffff:127.0.0.1]:443", refused: true},
+		{address: "10.1.2.3:443", refused: true},
+		{address: "172.16.0.1:443", refused: true},
+		{address: "192.168.1.1:443", refused: true},
+		{address: "[fd00::1]:443", refused: true},
+		{address: "169.254.169.254:80", refused: true},
+		{address: "[fe80::1]:443", refused: true},
+		{address: "0.0.0.0:443", refused: true},
+		{address: "0.1.2.3:443", refused: true},
+		{address: "[::]:443", refused: true},
+		{address: "100.64.0.1:443", refused: true},
+		{address: "224.0.0.1:443", refused: true},
+		{address: "255.255.255.255:443", refused: true},
+		{address: "93.184.215.14:443", refused: false},
+		{address: "[2606:4700:4700::1111]:443", refused: false},
+	}
+
+	for _, tt := range tests {
+		err := refuseInternalAddress("tcp", tt.address, nil)
+		if refused := errors.Is(err, ErrForbiddenAddress); refused != tt.refused {
+			t.Errorf("refuseInternalAddress(%s) = %v, want refused %v", tt.address, err, tt.refused)
+		}
+	}
+}
+
+func TestWebhookNotifierRefusesInternalHosts(t *testing.T) {
+	called := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier("")
+	trustTestServer(notifier, server)
+
+	err := notifier.Notify(context.Background(), Message{WebhookURL: server.URL + "/hook", Subject: "Reminder"})
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Notify to a loopback host returned %v, want ErrForbiddenAddress", err)
+	}
+	if called {
+		t.Error("the loopback server was reached")
+	}
+}
+
+func TestWebhookNotifierRequiresHTTPS(t *testing.T) {
+	notifier := NewWebhookNotifier("")
+
+	for _, target := range []string{"http://example.com/hook", "ftp://example.com/hook", "example.com/hook"} {
+		err := notifier.Notify(context.Background(), Message{WebhookURL: target, Subject: "Reminder"})
+		if !errors.Is(err, ErrInsecureWebhook) {
+			t.Errorf("Notify to %s returned %v, want ErrInsecureWebhook", target, err)
+		}
+	}
+}
+
+func TestWebhookNotifierPostsSignedPayload(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+	}))
+	defer server.Close()
+
+	notifier := newTestWebhookNotifier("webhook-secret", server)
+	due := time.Date(2026, time.October, 20, 12, 0, 0, 0, time.UTC)
+	err := notifier.Notify(context.Background(), Message{
+		UserID:     1,
+		WebhookURL: server.URL + "/hook",
+		TaskID:     2,
+		ReminderID: 3,
+		Subject:    "Reminder: Pay rent",
+		Body:       "Due today.",
+		DueDate:    &due,
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if got := header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	mac := hmac.New(sha256.New, []byte("webhook-secret"))
+	mac.Write(body)
+	if got, want := header.Get(SignatureHeader), hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Errorf("%s = %q, want %q", SignatureHeader, got, want)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("payload isn't JSON: %v", err)
+	}
+	if payload.Event != "reminder" || payload.UserID != 1 || payload.TaskID != 2 || payload.ReminderID != 3 ||
+		payload.Subject != "Reminder: Pay rent" || payload.Body != "Due today." || payload.DueDate == nil || !payload.DueDate.Equal(due) {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestWebhookNotifierDoesNotFollowRedirects(t *testing.T) {
+	redirected := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			redirected = true
+			return
+		}
+		http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	notifier := newTestWebhookNotifier("", server)
+	err := notifier.Notify(context.Background(), Message{WebhookURL: server.URL + "/hook", Subject: "Reminder"})
+	if err == nil || !strings.Contains(err.Error(), "307") {
+		t.Errorf("Notify to a redirecting webhook returned %v, want a failed delivery with status 307", err)
+	}
+	if redirected {
+		t.Error("the redirect was followed")
+	}
+}
+
+// newTestWebhookNotifier returns a notifier that may reach the test server,
+// which listens on loopback, but is otherwise configured like the real one.
+func newTestWebhookNotifier(secret string, server *httptest.Server) *WebhookNotifier {
+	notifier := &WebhookNotifier{client: newWebhookClient(nil), secret: secret}
+	trustTestServer(notifier, server)
+	return notifier
+}
+
+// trustTestServer makes the notifier accept the test server's certificate.
+func trustTestServer(notifier *WebhookNotifier, server *httptest.Server) {
+	notifier.client.Transport.(*http.Transport).TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig
+}
@@ -0,0 +1,522 @@
+// Package quickadd parses single-line task descriptions such as
+// "Pay rent tomorrow 9am #home !high every month" into the fields of a task.
+package quickadd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+type MatchKind string
+
+const (
+	MatchDate       MatchKind = "date"
+	MatchTime       MatchKind = "time"
+	MatchRecurrence MatchKind = "recurrence"
+	MatchTag        MatchKind = "tag"
+	MatchPriority   MatchKind = "priority"
+)
+
+// Match is a part of the line that was understood, as the user wrote it.
+type Match struct {
+	Kind MatchKind
+	Text string
+}
+
+// Result holds what Parse understood from a line. Whatever wasn't recognized
+// makes up the title.
+type Result struct {
+	Title string
+	// DueDate is the due instant for timed tasks, or midnight UTC of the due
+	// date for all-day ones.
+	DueDate        *time.Time
+	AllDay         bool
+	RecurrenceRule string
+	Tags           []string
+	Priority       models.TaskPriority
+	Matches        []Match
+}
+
+// eveningHour is when "tonight" is due unless a time is given.
+const eveningHour = 21
+
+var (
+	weekdays = map[string]time.Weekday{
+		// Abbreviations that are also English words, like "sun", are left out.
+		"sunday": time.Sunday,
+		"monday": time.Monday, "mon": time.Monday,
+		"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+		"wednesday": time.Wednesday,
+		"thursday":  time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+		"friday": time.Friday, "fri": time.Friday,
+		"saturday": time.Saturday,
+	}
+	months = map[string]time.Month{
+		"january": time.January, "jan": time.January,
+		"february": time.February, "feb": time.February,
+		"march": time.March, "mar": time.March,
+		"april": time.April, "apr": time.April,
+		"may":  time.May,
+		"june": time.June, "jun": time.June,
+		"july": time.July, "jul": time.July,
+		"august": time.August, "aug": time.August,
+		"september": time.September, "sep": time.September, "sept": time.September,
+		"october": time.October, "oct": time.October,
+		"november": time.November, "nov": time.November,
+		"december": time.December, "dec": time.December,
+	}
+	priorities = map[string]models.TaskPriority{
+		"!none":   models.PriorityNone,
+		"!low":    models.PriorityLow,
+		"!medium": models.PriorityMedium,
+		"!med":    models.PriorityMedium,
+		"!high":   models.PriorityHigh,
+		"!urgent": models.PriorityUrgent,
+	}
+	frequencies = map[string]string{
+		"day": "DAILY", "days": "DAILY",
+		"week": "WEEKLY", "weeks": "WEEKLY",
+		"month": "MONTHLY", "months": "MONTHLY",
+		"year": "YEARLY", "years": "YEARLY",
+	}
+	adverbFrequencies = map[string]string{
+		"daily":    "DAILY",
+		"weekly":   "WEEKLY",
+		"monthly":  "MONTHLY",
+		"yearly":   "YEARLY",
+		"annually": "YEARLY",
+	}
+	rruleDays = map[time.Weekday]string{
+		time.Sunday: "SU", time.Monday: "MO", time.Tuesday: "TU", time.Wednesday: "WE",
+		time.Thursday: "TH", time.Friday: "FR", time.Saturday: "SA",
+	}
+
+	isoDatePattern = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	clockPattern   = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	ordinalPattern = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+)
+
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+type clock struct {
+	hour, minute int
+}
+
+type parser struct {
+	now   time.Time
+	words []string
+	// lower holds the words lowercased and stripped of trailing punctuation,
+	// for matching.
+	lower []string
+	used  []bool
+
+	result  Result
+	date    *date
+	clock   *clock
+	tonight bool
+	// recurDays are the weekdays a weekly recurrence falls on, if any.
+	recurDays []time.Weekday
+}
+
+// Parse reads the line relative to now, whose location is taken as the
+// user's time zone. Dates without a time make all-day tasks, a time without a
+// date means its next occurrence, and a recurrence without either starts on
+// its first day from today.
+func Parse(line string, now time.Time) Result {
+	p := &parser{now: now, words: strings.Fields(line)}
+	p.lower = make([]string, len(p.words))
+	p.used = make([]bool, len(p.words))
+	for i, word := range p.words {
+		p.lower[i] = strings.TrimRight(strings.ToLower(word), ",.;")
+	}
+
+	matchers := []struct {
+		kind  MatchKind
+		match func(i int) int
+	}{
+		{MatchRecurrence, p.matchRecurrence},
+		{MatchDate, p.matchDate},
+		{MatchTime, p.matchTime},
+		{MatchTag, p.matchTag},
+		{MatchPriority, p.matchPriority},
+	}
+	for i := 0; i < len(p.words); {
+		consumed := 0
+		for _, matcher := range matchers {
+			if consumed = matcher.match(i); consumed > 0 {
+				p.consume(matcher.kind, i, consumed)
+				break
+			}
+		}
+		if consumed == 0 {
+			consumed = 1
+		}
+		i += consumed
+	}
+
+	p.resolveDueDate()
+
+	var title []string
+	for i, word := range p.words {
+		if !p.used[i] {
+			title = append(title, word)
+		}
+	}
+	p.result.Title = strings.Join(title, " ")
+	return p.result
+}
+
+func (p *parser) consume(kind MatchKind, start, count int) {
+	for i := start; i < start+count; i++ {
+		p.used[i] = true
+	}
+	text := strings.TrimRight(strings.Join(p.words[start:start+count], " "), ",.;")
+	p.result.Matches = append(p.result.Matches, Match{Kind: kind, Text: text})
+}
+
+// word returns the lowercased word at i, or "" past the end of the line.
+func (p *parser) word(i int) string {
+	if i < len(p.lower) {
+		return p.lower[i]
+	}
+	return ""
+}
+
+func (p *parser) matchRecurrence(i int) int {
+	if p.result.RecurrenceRule != "" {
+		return 0
+	}
+	if frequency, ok := adverbFrequencies[p.word(i)]; ok {
+		p.result.RecurrenceRule = "FREQ=" + frequency
+		return 1
+	}
+	if p.word(i) != "every" {
+		return 0
+	}
+
+	next := p.word(i + 1)
+	if frequency, ok := frequencies[next]; ok {
+		p.result.RecurrenceRule = "FREQ=" + frequency
+		return 2
+	}
+	switch next {
+	case "weekday":
+		p.setWeeklyOn(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+		return 2
+	case "weekend":
+		p.setWeeklyOn(time.Saturday, time.Sunday)
+		return 2
+	case "other":
+		if frequency, ok := frequencies[p.word(i+2)]; ok {
+			p.result.RecurrenceRule = "FREQ=" + frequency + ";INTERVAL=2"
+			return 3
+		}
+		return 0
+	}
+	if weekday, ok := weekdays[next]; ok {
+		p.setWeeklyOn(weekday)
+		return 2
+	}
+	if interval, err := strconv.Atoi(next); err == nil && interval > 0 {
+		if frequency, ok := frequencies[p.word(i+2)]; ok {
+			p.result.RecurrenceRule = fmt.Sprintf("FREQ=%s;INTERVAL=%d", frequency, interval)
+			return 3
+		}
+	}
+	return 0
+}
+
+func (p *parser) setWeeklyOn(days ...time.Weekday) {
+	names := make([]string, len(days))
+	for i, day := range days {
+		names[i] = rruleDays[day]
+	}
+	p.result.RecurrenceRule = "FREQ=WEEKLY;BYDAY=" + strings.Join(names, ",")
+	p.recurDays = days
+}
+
+func (p *parser) matchDate(i int) int {
+	if p.date != nil {
+		return 0
+	}
+	today := p.now
+
+	switch p.word(i) {
+	case "today":
+		p.setDate(today)
+		return 1
+	case "tonight":
+		p.setDate(today)
+		p.tonight = true
+		return 1
+	case "tomorrow", "tmr":
+		p.setDate(today.AddDate(0, 0, 1))
+		return 1
+	case "on", "this":
+		if consumed := p.matchDate(i + 1); consumed > 0 {
+			return consumed + 1
+		}
+		return 0
+	case "next":
+		switch p.word(i + 1) {
+		case "week":
+			p.setDate(nextWeekday(today, time.Monday))
+			return 2
+		case "month":
+			p.setDate(time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()))
+			return 2
+		case "year":
+			p.setDate(time.Date(today.Year()+1, time.January, 1, 0, 0, 0, 0, today.Location()))
+			return 2
+		}
+		if weekday, ok := weekdays[p.word(i+1)]; ok {
+			p.setDate(nextWeekday(today, weekday))
+			return 2
+		}
+		return 0
+	case "in":
+		return p.matchRelativeDate(i)
+	}
+
+	if weekday, ok := weekdays[p.word(i)]; ok {
+		p.setDate(nextWeekday(today, weekday))
+		return 1
+	}
+	if groups := isoDatePattern.FindStringSubmatch(p.word(i)); groups != nil {
+		year, _ := strconv.Atoi(groups[1])
+		month, _ := strconv.Atoi(groups[2])
+		day, _ := strconv.Atoi(groups[3])
+		if validDate(year, time.Month(month), day) {
+			p.date = &date{year, time.Month(month), day}
+			return 1
+		}
+		return 0
+	}
+	return p.matchMonthDay(i)
+}
+
+// matchRelativeDate matches "in 3 days", "in a week" and the like.
+func (p *parser) matchRelativeDate(i int) int {
+	count := p.word(i + 1)
+	amount, err := strconv.Atoi(count)
+	if count == "a" || count == "an" {
+		amount, err = 1, nil
+	}
+	if err != nil || amount <= 0 {
+		return 0
+	}
+
+	today := p.now
+	switch frequencies[p.word(i+2)] {
+	case "DAILY":
+		p.setDate(today.AddDate(0, 0, amount))
+	case "WEEKLY":
+		p.setDate(today.AddDate(0, 0, 7*amount))
+	case "MONTHLY":
+		p.setDate(today.AddDate(0, amount, 0))
+	case "YEARLY":
+		p.setDate(today.AddDate(amount, 0, 0))
+	default:
+		return 0
+	}
+	return 3
+}
+
+// matchMonthDay matches "oct 20", "october 20th" and "20 oct", in the current
+// year unless that date has passed.
+func (p *parser) matchMonthDay(i int) int {
+	var month time.Month
+	var day string
+	if m, ok := months[p.word(i)]; ok {
+		month, day = m, p.word(i+1)
+	} else if m, ok := months[p.word(i+1)]; ok {
+		month, day = m, p.word(i)
+	} else {
+		return 0
+	}
+
+	groups := ordinalPattern.FindStringSubmatch(day)
+	if groups == nil {
+		return 0
+	}
+	dayOfMonth, _ := strconv.Atoi(groups[1])
+	year := p.now.Year()
+	if !validDate(year, month, dayOfMonth) {
+		return 0
+	}
+	if time.Date(year, month, dayOfMonth, 0, 0, 0, 0, p.now.Location()).Before(startOfDay(p.now)) {
+		year++
+	}
+	p.date = &date{year, month, dayOfMonth}
+	return 2
+}
+
+func (p *parser) matchTime(i int) int {
+	if p.clock != nil {
+		return 0
+	}
+	if p.word(i) == "at" {
+		// After "at", a bare hour like "at 9" is taken as a time too.
+		if consumed := p.matchClock(i+1, true); consumed > 0 {
+			return consumed + 1
+		}
+		return 0
+	}
+	return p.matchClock(i, false)
+}
+
+func (p *parser) matchClock(i int, bareHour bool) int {
+	switch p.word(i) {
+	case "noon":
+		p.clock = &clock{12, 0}
+		return 1
+	case "midnight":
+		p.clock = &clock{0, 0}
+		return 1
+	}
+
+	text, consumed := p.word(i), 1
+	if suffix := p.word(i + 1); (suffix == "am" || suffix == "pm") && clockPattern.MatchString(text) {
+		text, consumed = text+suffix, 2
+	}
+	groups := clockPattern.FindStringSubmatch(text)
+	if groups == nil || (groups[2] == "" && groups[3] == "" && !bareHour) {
+		return 0
+	}
+
+	hour, _ := strconv.Atoi(groups[1])
+	minute := 0
+	if groups[2] != "" {
+		minute, _ = strconv.Atoi(groups[2])
+	}
+	switch groups[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0
+		}
+		hour %= 12
+		if groups[3] == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return 0
+	}
+	p.clock = &clock{hour, minute}
+	return consumed
+}
+
+func (p *parser) matchTag(i int) int {
+	name := strings.TrimRight(p.words[i], ",.;")
+	if len(name) < 2 || name[0] != '#' {
+		return 0
+	}
+	name = name[1:]
+	for _, tag := range p.result.Tags {
+		if strings.EqualFold(tag, name) {
+			return 1
+		}
+	}
+	p.result.Tags = append(p.result.Tags, name)
+	return 1
+}
+
+func (p *parser) matchPriority(i int) int {
+	priority, ok := priorities[p.word(i)]
+	if !ok || p.result.Priority != "" {
+		return 0
+	}
+	p.result.Priority = priority
+	return 1
+}
+
+func (p *parser) setDate(t time.Time) {
+	p.date = &date{t.Year(), t.Month(), t.Day()}
+}
+
+// resolveDueDate combines the date and time found into the due date.
+func (p *parser) resolveDueDate() {
+	if p.tonight && p.clock == nil {
+		p.clock = &clock{eveningHour, 0}
+	}
+
+	if p.date == nil {
+		switch {
+		case len(p.recurDays) > 0:
+			// The first of the days that is still ahead, time included.
+			day := firstWeekday(p.now, p.recurDays)
+			if p.clock != nil && p.at(day, *p.clock).Before(p.now) {
+				day = firstWeekday(p.now.AddDate(0, 0, 1), p.recurDays)
+			}
+			p.setDate(day)
+		case p.clock != nil:
+			// A bare time means the next time the clock shows it.
+			due := p.at(p.now, *p.clock)
+			if due.Before(p.now) {
+				due = due.AddDate(0, 0, 1)
+			}
+			p.setDate(due)
+		case p.result.RecurrenceRule != "":
+			p.setDate(p.now)
+		default:
+			return
+		}
+	}
+
+	if p.clock == nil {
+		due := time.Date(p.date.year, p.date.month, p.date.day, 0, 0, 0, 0, time.UTC)
+		p.result.DueDate = &due
+		p.result.AllDay = true
+		return
+	}
+	day := time.Date(p.date.year, p.date.month, p.date.day, 0, 0, 0, 0, p.now.Location())
+	due := p.at(day, *p.clock)
+	p.result.DueDate = &due
+}
+
+func (p *parser) at(day time.Time, c clock) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), c.hour, c.minute, 0, 0, p.now.Location())
+}
+
+// nextWeekday returns the first day after t that falls on the weekday.
+func nextWeekday(t time.Time, weekday time.Weekday) time.Time {
+	days := (int(weekday) - int(t.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return t.AddDate(0, 0, days)
+}
+
+// firstWeekday returns the first day from t onwards that falls on any of the
+// weekdays.
+func firstWeekday(t time.Time, weekdays []time.Weekday) time.Time {
+	for days := 0; days < 7; days++ {
+		day := t.AddDate(0, 0, days)
+		for _, weekday := range weekdays {
+			if day.Weekday() == weekday {
+				return day
+			}
+		}
+	}
+	return t
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func validDate(year int, month time.Month, day int) bool {
+	if month < time.January || month > time.December || day < 1 {
+		return false
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Day() == day
+}
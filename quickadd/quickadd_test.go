@@ -0,0 +1,144 @@
+package quickadd
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+// buenosAires has no daylight saving time, so the expected dates below don't
+// depend on the tz database of the machine running the tests.
+var buenosAires = time.FixedZone("ART", -3*60*60)
+
+// now is a Wednesday afternoon.
+var now = time.Date(2026, time.October, 14, 15, 30, 0, 0, buenosAires)
+
+func allDay(year int, month time.Month, day int) *time.Time {
+	due := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &due
+}
+
+func timed(year int, month time.Month, day, hour, minute int) *time.Time {
+	due := time.Date(year, month, day, hour, minute, 0, 0, buenosAires)
+	return &due
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line     string
+		title    string
+		due      *time.Time
+		rule     string
+		tags     []string
+		priority models.TaskPriority
+	}{
+		// Plain titles.
+		{line: "Buy milk", title: "Buy milk"},
+		{line: "I may call mom", title: "I may call mom"},
+		{line: "Polish the mar on the table", title: "Polish the mar on the table"},
+		{line: "Book the sun lounger", title: "Book the sun lounger"},
+
+		// Dates.
+		{line: "Pay rent today", title: "Pay rent", due: allDay(2026, time.October, 14)},
+		{line: "Call mom tonight", title: "Call mom", due: timed(2026, time.October, 14, 21, 0)},
+		{line: "Call mom tonight at 8pm", title: "Call mom", due: timed(2026, time.October, 14, 20, 0)},
+		{line: "Dentist tomorrow 9am", title: "Dentist", due: timed(2026, time.October, 15, 9, 0)},
+		{line: "Dentist tmr at 9:45", title: "Dentist", due: timed(2026, time.October, 15, 9, 45)},
+		{line: "Standup friday", title: "Standup", due: allDay(2026, time.October, 16)},
+		{line: "Standup on fri", title: "Standup", due: allDay(2026, time.October, 16)},
+		{line: "Retro wednesday", title: "Retro", due: allDay(2026, time.October, 21)},
+		{line: "Review next monday noon", title: "Review", due: timed(2026, time.October, 19, 12, 0)},
+		{line: "Plan next week", title: "Plan", due: allDay(2026, time.October, 19)},
+		{line: "Budget next month", title: "Budget", due: allDay(2026, time.November, 1)},
+		{line: "Renew passport in 3 days", title: "Renew passport", due: allDay(2026, time.October, 17)},
+		{line: "Renew passport in a week", title: "Renew passport", due: allDay(2026, time.October, 21)},
+
+		// Month-day forms, in the current year unless the date has passed.
+		{line: "Taxes oct 20", title: "Taxes", due: allDay(2026, time.October, 20)},
+		{line: "Taxes 20th october", title: "Taxes", due: allDay(2026, time.October, 20)},
+		{line: "Taxes october 14", title: "Taxes", due: allDay(2026, time.October, 14)},
+		{line: "Party mar 5", title: "Party", due: allDay(2027, time.March, 5)},
+		{line: "Party may 1st 7pm", title: "Party", due: timed(2027, time.May, 1, 19, 0)},
+		{line: "Party feb 30", title: "Party feb 30"},
+
+		// ISO dates.
+		{line: "Launch 2026-12-01", title: "Launch", due: allDay(2026, time.December, 1)},
+		{line: "Launch 2026-12-01 at 10", title: "Launch", due: timed(2026, time.December, 1, 10, 0)},
+		{line: "Launch 2026-02-30", title: "Launch 2026-02-30"},
+
+		// A time alone means its next occurrence.
+		{line: "Call back 4pm", title: "Call back", due: timed(2026, time.October, 14, 16, 0)},
+		{line: "Call back 3pm", title: "Call back", due: timed(2026, time.October, 15, 15, 0)},
+		{line: "Call back at 9", title: "Call back", due: timed(2026, time.October, 15, 9, 0)},
+		{line: "Call back 15:30", title: "Call back", due: timed(2026, time.October, 14, 15, 30)},
+		{line: "Take out trash midnight", title: "Take out trash", due: timed(2026, time.October, 15, 0, 0)},
+		{line: "Read 9 chapters", title: "Read 9 chapters"},
+		{line: "Meet 13pm", title: "Meet 13pm"},
+
+		// Recurrences.
+		{line: "Stretch every day", title: "Stretch", due: allDay(2026, time.October, 14), rule: "FREQ=DAILY"},
+		{line: "Stretch daily 8am", title: "Stretch", due: timed(2026, time.October, 15, 8, 0), rule: "FREQ=DAILY"},
+		{line: "Water plants every 3 days", title: "Water plants", due: allDay(2026, time.October, 14), rule: "FREQ=DAILY;INTERVAL=3"},
+		{line: "Clean every other week", title: "Clean", due: allDay(2026, time.October, 14), rule: "FREQ=WEEKLY;INTERVAL=2"},
+		{line: "Pay rent every month oct 31", title: "Pay rent", due: allDay(2026, time.October, 31), rule: "FREQ=MONTHLY"},
+		{line: "Commute every weekday", title: "Commute", due: allDay(2026, time.October, 14), rule: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{line: "Commute every weekday 9am", title: "Commute", due: timed(2026, time.October, 15, 9, 0), rule: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{line: "Hike every weekend", title: "Hike", due: allDay(2026, time.October, 17), rule: "FREQ=WEEKLY;BYDAY=SA,SU"},
+		{line: "Team sync every monday 10am", title: "Team sync", due: timed(2026, time.October, 19, 10, 0), rule: "FREQ=WEEKLY;BYDAY=MO"},
+		{line: "Gym every wednesday 6pm", title: "Gym", due: timed(2026, time.October, 14, 18, 0), rule: "FREQ=WEEKLY;BYDAY=WE"},
+		{line: "Gym every wednesday 7am", title: "Gym", due: timed(2026, time.October, 21, 7, 0), rule: "FREQ=WEEKLY;BYDAY=WE"},
+		{line: "Do every other thing", title: "Do every other thing"},
+
+		// Tags and priorities.
+		{line: "Fix login bug #work #Backend !high", title: "Fix login bug", tags: []string{"work", "Backend"}, priority: models.PriorityHigh},
+		{line: "Fix login bug #work, #WORK", title: "Fix login bug", tags: []string{"work"}},
+		{line: "Ship it !urgent !low", title: "Ship it !low", priority: models.PriorityUrgent},
+		{line: "Pick # of guests", title: "Pick # of guests"},
+		{line: "Pay rent tomorrow 9am #home !high every month", title: "Pay rent", due: timed(2026, time.October, 15, 9, 0), rule: "FREQ=MONTHLY", tags: []string{"home"}, priority: models.PriorityHigh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got := Parse(tt.line, now)
+
+			if got.Title != tt.title {
+				t.Errorf("Title = %q, want %q", got.Title, tt.title)
+			}
+			switch {
+			case tt.due == nil && got.DueDate != nil:
+				t.Errorf("DueDate = %v, want none", got.DueDate)
+			case tt.due != nil && (got.DueDate == nil || !got.DueDate.Equal(*tt.due)):
+				t.Errorf("DueDate = %v, want %v", got.DueDate, tt.due)
+			}
+			if wantAllDay := tt.due != nil && tt.due.Location() == time.UTC; got.AllDay != wantAllDay {
+				t.Errorf("AllDay = %v, want %v", got.AllDay, wantAllDay)
+			}
+			if got.RecurrenceRule != tt.rule {
+				t.Errorf("RecurrenceRule = %q, want %q", got.RecurrenceRule, tt.rule)
+			}
+			if !slices.Equal(got.Tags, tt.tags) {
+				t.Errorf("Tags = %q, want %q", got.Tags, tt.tags)
+			}
+			if got.Priority != tt.priority {
+				t.Errorf("Priority = %q, want %q", got.Priority, tt.priority)
+			}
+		})
+	}
+}
+
+func TestParseMatches(t *testing.T) {
+	got := Parse("Pay rent tomorrow at 9am, #home !high every month", now)
+
+	want := []Match{
+		{Kind: MatchDate, Text: "tomorrow"},
+		{Kind: MatchTime, Text: "at 9am"},
+		{Kind: MatchTag, Text: "#home"},
+		{Kind: MatchPriority, Text: "!high"},
+		{Kind: MatchRecurrence, Text: "every month"},
+	}
+	if !slices.Equal(got.Matches, want) {
+		t.Errorf("Matches = %+v, want %+v", got.Matches, want)
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+)
+
+type AttachmentRepository interface {
+	Create(attachment *models.Attachment) error
+	FindByID(id uint) (*models.Attachment, error)
+	FindByTask(taskID uint) ([]models.Attachment, error)
+	Delete(id uint) error
+}
+
+type gormAttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &gormAttachmentRepository{db: db}
+}
+
+func (r *gormAttachmentRepository) Create(attachment *models.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *gormAttachmentRepository) FindByID(id uint) (*models.Attachment, error) {
+	var attachment models.Attachment
+	result := r.db.First(&attachment, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrAttachmentNotFound
+	}
+	return &attachment, result.Error
+}
+
+// FindByTask returns the task's attachments, oldest first.
+func (r *gormAttachmentRepository) FindByTask(taskID uint) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	result := r.db.Where("task_id = ?", taskID).Order("created_at").Order("id").Find(&attachments)
+	return attachments, result.Error
+}
+
+func (r *gormAttachmentRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.Attachment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAttachmentNotFound
+	}
+	return nil
+}
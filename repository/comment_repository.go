@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCommentNotFound = errors.New("comment not found")
+)
+
+type CommentRepository interface {
+	Create(comment *models.Comment) error
+	FindByID(id uint) (*models.Comment, error)
+	FindByTask(taskID uint) ([]models.Comment, error)
+	Update(comment *models.Comment) error
+	Delete(id uint) error
+}
+
+type gormCommentRepository struct {
+	db *gorm.DB
+}
+
+func NewGormCommentRepository(db *gorm.DB) CommentRepository {
+	return &gormCommentRepository{db: db}
+}
+
+func (r *gormCommentRepository) Create(comment *models.Comment) error {
+	return r.db.Create(comment).Error
+}
+
+func (r *gormCommentRepository) FindByID(id uint) (*models.Comment, error) {
+	var comment models.Comment
+	result := r.db.First(&comment, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrCommentNotFound
+	}
+	return &comment, result.Error
+}
+
+// FindByTask returns the task's comments, oldest first.
+func (r *gormCommentRepository) FindByTask(taskID uint) ([]models.Comment, error) {
+	var comments []models.Comment
+	result := r.db.Where("task_id = ?", taskID).Order("created_at").Order("id").Find(&comments)
+	return comments, result.Error
+}
+
+func (r *gormCommentRepository) Update(comment *models.Comment) error {
+	return r.db.Save(comment).Error
+}
+
+func (r *gormCommentRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.Comment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvitationNotFound = errors.New("invitation not found")
+)
+
+type InvitationRepository interface {
+	Create(invitation *models.WorkspaceInvitation) error
+	FindByID(id uint) (*models.WorkspaceInvitation, error)
+	FindByToken(token string) (*models.WorkspaceInvitation, error)
+	FindPending(workspaceID uint, email string) (*models.WorkspaceInvitation, error)
+	FindPendingByWorkspace(workspaceID uint, now time.Time) ([]models.WorkspaceInvitation, error)
+	FindPendingByEmail(email string, now time.Time) ([]models.WorkspaceInvitation, error)
+	Update(invitation *models.WorkspaceInvitation) error
+	Delete(id uint) error
+
+	// Accept marks the invitation accepted and adds its invitee to the
+	// workspace, in a single transaction.
+	Accept(invitation *models.WorkspaceInvitation, member *models.WorkspaceMember) error
+}
+
+type gormInvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &gormInvitationRepository{db: db}
+}
+
+func (r *gormInvitationRepository) Create(invitation *models.WorkspaceInvitation) error {
+	return r.db.Omit("Workspace").Create(invitation).Error
+}
+
+func (r *gormInvitationRepository) FindByID(id uint) (*models.WorkspaceInvitation, error) {
+	var invitation models.WorkspaceInvitation
+	result := r.db.First(&invitation, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrInvitationNotFound
+	}
+	return &invitation, result.Error
+}
+
+func (r *gormInvitationRepository) FindByToken(token string) (*models.WorkspaceInvitation, error) {
+	var invitation models.WorkspaceInvitation
+	result := r.db.Preload("Workspace").Where("token = ?", token).First(&invitation)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrInvitationNotFound
+	}
+	return &invitation, result.Error
+}
+
+// FindPending returns the workspace's pending invitation for the email,
+// whether or not it has expired.
+func (r *gormInvitationRepository) FindPending(workspaceID uint, email string) (*models.WorkspaceInvitation, error) {
+	var invitation models.WorkspaceInvitation
+	result := r.db.
+		Where("workspace_id = ? AND LOWER(email) = LOWER(?) AND status = ?", workspaceID, email, models.InvitationPending).
+		First(&invitation)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrInvitationNotFound
+	}
+	return &invitation, result.Error
+}
+
+func (r *gormInvitationRepository) FindPendingByWorkspace(workspaceID uint, now time.Time) ([]models.WorkspaceInvitation, error) {
+	var invitations []models.WorkspaceInvitation
+	result := r.db.
+		Where("workspace_id = ? AND status = ? AND expires_at > ?", workspaceID, models.InvitationPending, now).
+		Order("id").
+		Find(&invitations)
+	return invitations, result.Error
+}
+
+func (r *gormInvitationRepository) FindPendingByEmail(email string, now time.Time) ([]models.WorkspaceInvitation, error) {
+	var invitations []models.WorkspaceInvitation
+	result := r.db.Preload("Workspace").
+		Where("LOWER(email) = LOWER(?) AND status = ? AND expires_at > ?", email, models.InvitationPending, now).
+		Order("id DESC").
+		Find(&invitations)
+	return invitations, result.Error
+}
+
+func (r *gormInvitationRepository) Update(invitation *models.WorkspaceInvitation) error {
+	return r.db.Omit("Workspace").Save(invitation).Error
+}
+
+func (r *gormInvitationRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.WorkspaceInvitation{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+func (r *gormInvitationRepository) Accept(invitation *models.WorkspaceInvitation, member *models.WorkspaceMember) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		invitation.Status = models.InvitationAccepted
+		if err := tx.Omit("Workspace").Save(invitation).Error; err != nil {
+			return err
+		}
+		return (&gormWorkspaceRepository{db: tx}).AddMember(member)
+	})
+}
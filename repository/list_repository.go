@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrListNotFound = errors.New("list not found")
+)
+
+type ListRepository interface {
+	// Create creates the list along with its default statuses.
+	Create(list *models.List) error
+	FindByID(id uint) (*models.List, error)
+	FindByUser(userID uint, includeArchived bool) ([]models.List, error)
+	FindByWorkspace(workspaceID uint, includeArchived bool) ([]models.List, error)
+	FindSharedWith(userID uint) ([]models.List, error)
+	FindInbox(userID uint) (*models.List, error)
+	Update(list *models.List) error
+	Delete(id uint, moveTasksTo *uint) error
+}
+
+type gormListRepository struct {
+	db *gorm.DB
+}
+
+func NewGormListRepository(db *gorm.DB) ListRepository {
+	return &gormListRepository{db: db}
+}
+
+func (r *gormListRepository) Create(list *models.List) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(list).Error; err != nil {
+			return err
+		}
+		statuses := make([]models.TaskStatus, len(models.DefaultStatuses))
+		for i, status := range models.DefaultStatuses {
+			status.ListID = list.ID
+			statuses[i] = status
+		}
+		return tx.Create(&statuses).Error
+	})
+}
+
+func (r *gormListRepository) FindByID(id uint) (*models.List, error) {
+	var list models.List
+	result := r.db.First(&list, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrListNotFound
+	}
+	return &list, result.Error
+}
+
+// FindByUser returns the lists in the user's personal space.
+func (r *gormListRepository) FindByUser(userID uint, includeArchived bool) ([]models.List, error) {
+	return r.findLists(r.db.Where("user_id = ? AND workspace_id IS NULL", userID), includeArchived)
+}
+
+func (r *gormListRepository) FindByWorkspace(workspaceID uint, includeArchived bool) ([]models.List, error) {
+	return r.findLists(r.db.Where("workspace_id = ?", workspaceID), includeArchived)
+}
+
+func (r *gormListRepository) findLists(query *gorm.DB, includeArchived bool) ([]models.List, error) {
+	var lists []models.List
+	if !includeArchived {
+		query = query.Where("archived = ?", false)
+	}
+	result := query.Order("is_inbox DESC").Order("name").Find(&lists)
+	return lists, result.Error
+}
+
+// FindSharedWith returns the lists shared with the user.
+func (r *gormListRepository) FindSharedWith(userID uint) ([]models.List, error) {
+	var lists []models.List
+	result := r.db.Where("id IN (SELECT list_id FROM shares WHERE user_id = ?)", userID).Order("name").Find(&lists)
+	return lists, result.Error
+}
+
+func (r *gormListRepository) FindInbox(userID uint) (*models.List, error) {
+	var list models.List
+	result := r.db.Where("user_id = ? AND is_inbox = ?", userID, true).First(&list)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrListNotFound
+	}
+	return &list, result.Error
+}
+
+func (r *gormListRepository) Update(list *models.List) error {
+	return r.db.Save(list).Error
+}
+
+// Delete removes the list and its shares after moving the list owner's tasks
+// to the moveTasksTo list, so no task is left pointing at a deleted list.
+// Tasks owned by anyone else, or every task when moveTasksTo is nil, are
+// detached from the list instead.
+func (r *gormListRepository) Delete(id uint, moveTasksTo *uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if moveTasksTo != nil {
+			ownerID := tx.Model(&models.List{}).Select("user_id").Where("id = ?", id)
+			if err := tx.Model(&models.Task{}).Where("list_id = ? AND user_id = (?)", id, ownerID).Update("list_id", *moveTasksTo).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&models.Task{}).Where("list_id = ?", id).Update("list_id", nil).Error; err != nil {
+			return err
+		}
+		if err := syncStatuses(tx, "list_id IS DISTINCT FROM ? AND status_id IN (SELECT id FROM task_statuses WHERE list_id = ?)", id, id); err != nil {
+			return err
+		}
+		if err := tx.Where("list_id = ?", id).Delete(&models.Share{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.List{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrListNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	// FindByUser returns the user's newest notifications first, optionally
+	// only the unread ones.
+	FindByUser(userID uint, unreadOnly bool, limit int) ([]models.Notification, error)
+	MarkRead(id, userID uint, at time.Time) error
+	MarkAllRead(userID uint, at time.Time) error
+}
+
+type gormNotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &gormNotificationRepository{db: db}
+}
+
+func (r *gormNotificationRepository) Create(notification *models.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+func (r *gormNotificationRepository) FindByUser(userID uint, unreadOnly bool, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	query := r.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	result := query.Order("id DESC").Limit(limit).Find(&notifications)
+	return notifications, result.Error
+}
+
+// MarkRead marks the user's notification as read, keeping the time it was
+// first read.
+func (r *gormNotificationRepository) MarkRead(id, userID uint, at time.Time) error {
+	result := r.db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", at))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *gormNotificationRepository) MarkAllRead(userID uint, at time.Time) error {
+	return r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at).Error
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReminderNotFound = errors.New("reminder not found")
+)
+
+// reminderDueSQL is when a reminder comes due, given its task is joined in.
+// Relative reminders follow their task's due date, counting all-day tasks as
+// due at 9:00 in their time zone, and never come due while it has none.
+const reminderDueSQL = `COALESCE(reminders.remind_at, (
+	CASE WHEN tasks.all_day
+		THEN ((tasks.due_date AT TIME ZONE 'UTC')::date + time '09:00') AT TIME ZONE tasks.time_zone
+		ELSE tasks.due_date
+	END) - make_interval(mins => reminders.minutes_before))`
+
+// claimRemindersSQL leases due reminders of open tasks to one deliverer,
+// skipping any that another one has locked.
+const claimRemindersSQL = `
+	UPDATE reminders SET next_attempt_at = ?, attempts = attempts + 1, updated_at = ?
+	WHERE id IN (
+		SELECT reminders.id FROM reminders JOIN tasks ON tasks.id = reminders.task_id
+		WHERE reminders.status = 'pending'
+			AND (reminders.next_attempt_at IS NULL OR reminders.next_attempt_at <= ?)
+			AND ` + reminderDueSQL + ` <= ?
+			AND tasks.deleted_at IS NULL AND NOT tasks.completed
+		ORDER BY reminders.id
+		LIMIT ?
+		FOR UPDATE OF reminders SKIP LOCKED
+	)
+	RETURNING id`
+
+type ReminderRepository interface {
+	Create(reminder *models.Reminder) error
+	FindByID(id uint) (*models.Reminder, error)
+	FindByTask(taskID, userID uint) ([]models.Reminder, error)
+	Update(reminder *models.Reminder) error
+	Delete(id uint) error
+
+	// ClaimDue leases up to limit due reminders until leaseUntil, so they are
+	// not delivered twice, and returns them along with their task and user.
+	ClaimDue(now, leaseUntil time.Time, limit int) ([]models.Reminder, error)
+	// SkipInactive marks the due reminders of completed and deleted tasks as
+	// skipped, returning how many there were.
+	SkipInactive(now time.Time) (int64, error)
+	// NextDueAt returns when the next pending reminder comes due, or nil if
+	// none is scheduled.
+	NextDueAt() (*time.Time, error)
+}
+
+type gormReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewGormReminderRepository(db *gorm.DB) ReminderRepository {
+	return &gormReminderRepository{db: db}
+}
+
+func (r *gormReminderRepository) Create(reminder *models.Reminder) error {
+	return r.db.Omit("Task", "User").Create(reminder).Error
+}
+
+func (r *gormReminderRepository) FindByID(id uint) (*models.Reminder, error) {
+	var reminder models.Reminder
+	result := r.db.First(&reminder, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrReminderNotFound
+	}
+	return &reminder, result.Error
+}
+
+// FindByTask returns the user's reminders on the task, oldest first.
+func (r *gormReminderRepository) FindByTask(taskID, userID uint) ([]models.Reminder, error) {
+	var reminders []models.Reminder
+	result := r.db.Where("task_id = ? AND user_id = ?", taskID, userID).Order("id").Find(&reminders)
+	return reminders, result.Error
+}
+
+func (r *gormReminderRepository) Update(reminder *models.Reminder) error {
+	return r.db.Omit("Task", "User").Save(reminder).Error
+}
+
+func (r *gormReminderRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.Reminder{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReminderNotFound
+	}
+	return nil
+}
+
+func (r *gormReminderRepository) ClaimDue(now, leaseUntil time.Time, limit int) ([]models.Reminder, error) {
+	ids, err := findIDs(r.db, claimRemindersSQL, leaseUntil, now, now, now, limit)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var reminders []models.Reminder
+	result := r.db.Preload("Task").Preload("User").Where("id IN ?", ids).Order("id").Find(&reminders)
+	return reminders, result.Error
+}
+
+func (r *gormReminderRepository) SkipInactive(now time.Time) (int64, error) {
+	result := r.db.Exec(`
+		UPDATE reminders SET status = ?, updated_at = ? FROM tasks
+		WHERE tasks.id = reminders.task_id AND reminders.status = ?
+			AND (tasks.deleted_at IS NOT NULL OR tasks.completed)
+			AND `+reminderDueSQL+` <= ?`,
+		models.ReminderSkipped, now, models.ReminderPending, now)
+	return result.RowsAffected, result.Error
+}
+
+func (r *gormReminderRepository) NextDueAt() (*time.Time, error) {
+	var next *time.Time
+	err := r.db.Raw(`
+		SELECT MIN(GREATEST(`+reminderDueSQL+`, reminders.next_attempt_at))
+		FROM reminders JOIN tasks ON tasks.id = reminders.task_id
+		WHERE reminders.status = ? AND tasks.deleted_at IS NULL AND NOT tasks.completed`,
+		models.ReminderPending).Row().Scan(&next)
+	return next, err
+}
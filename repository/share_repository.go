@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrShareNotFound      = errors.New("share not found")
+	ErrShareAlreadyExists = errors.New("already shared with this user")
+)
+
+type ShareRepository interface {
+	Create(share *models.Share) error
+	FindByID(id uint) (*models.Share, error)
+	FindByTask(taskID uint) ([]models.Share, error)
+	FindByList(listID uint) ([]models.Share, error)
+	FindByUser(userID uint) ([]models.Share, error)
+	FindRoles(userID uint, taskIDs []uint, listID *uint) ([]models.ShareRole, error)
+	Update(share *models.Share) error
+	Delete(id uint) error
+}
+
+type gormShareRepository struct {
+	db *gorm.DB
+}
+
+func NewGormShareRepository(db *gorm.DB) ShareRepository {
+	return &gormShareRepository{db: db}
+}
+
+func (r *gormShareRepository) Create(share *models.Share) error {
+	result := r.db.Omit("User").Create(share)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return ErrShareAlreadyExists
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	return r.db.Take(&share.User, share.UserID).Error
+}
+
+func (r *gormShareRepository) FindByID(id uint) (*models.Share, error) {
+	var share models.Share
+	result := r.db.Preload("User").First(&share, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrShareNotFound
+	}
+	return &share, result.Error
+}
+
+func (r *gormShareRepository) FindByTask(taskID uint) ([]models.Share, error) {
+	var shares []models.Share
+	result := r.db.Preload("User").Where("task_id = ?", taskID).Order("id").Find(&shares)
+	return shares, result.Error
+}
+
+func (r *gormShareRepository) FindByList(listID uint) ([]models.Share, error) {
+	var shares []models.Share
+	result := r.db.Preload("User").Where("list_id = ?", listID).Order("id").Find(&shares)
+	return shares, result.Error
+}
+
+// FindByUser returns everything shared with the user, most recent first.
+func (r *gormShareRepository) FindByUser(userID uint) ([]models.Share, error) {
+	var shares []models.Share
+	result := r.db.Where("user_id = ?", userID).Order("id DESC").Find(&shares)
+	return shares, result.Error
+}
+
+// FindRoles returns the roles the user was given on any of the tasks or on
+// the list.
+func (r *gormShareRepository) FindRoles(userID uint, taskIDs []uint, listID *uint) ([]models.ShareRole, error) {
+	query := r.db.Model(&models.Share{}).Where("user_id = ?", userID)
+	switch {
+	case len(taskIDs) > 0 && listID != nil:
+		query = query.Where("task_id IN ? OR list_id = ?", taskIDs, *listID)
+	case len(taskIDs) > 0:
+		query = query.Where("task_id IN ?", taskIDs)
+	case listID != nil:
+		query = query.Where("list_id = ?", *listID)
+	default:
+		return nil, nil
+	}
+
+	var roles []models.ShareRole
+	result := query.Pluck("role", &roles)
+	return roles, result.Error
+}
+
+func (r *gormShareRepository) Update(share *models.Share) error {
+	return r.db.Omit("User").Save(share).Error
+}
+
+func (r *gormShareRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.Share{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrShareNotFound
+	}
+	return nil
+}
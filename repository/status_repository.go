@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrStatusNotFound = errors.New("status not found")
+	ErrStatusExists   = errors.New("the list already has a status with this name")
+)
+
+// taskStatusSQL picks the status a task should have in its list, given
+// whether it is completed: its current status if that still fits, otherwise
+// the first status with the matching completion, preferring the category of
+// the current one. It is NULL for tasks outside any list.
+const taskStatusSQL = `(
+	SELECT statuses.id FROM task_statuses AS statuses
+	WHERE statuses.list_id = tasks.list_id
+	ORDER BY (statuses.category = 'done') <> ?,
+		statuses.id IS DISTINCT FROM tasks.status_id,
+		statuses.category IS DISTINCT FROM (
+			SELECT current.category FROM task_statuses AS current WHERE current.id = tasks.status_id
+		),
+		statuses.position, statuses.id
+	LIMIT 1)`
+
+// syncStatuses gives every task matching the condition, trashed or not, the
+// status that fits its list and completion.
+func syncStatuses(db *gorm.DB, query any, args ...any) error {
+	return db.Unscoped().Model(&models.Task{}).Where(query, args...).
+		UpdateColumn("status_id", gorm.Expr(taskStatusSQL, gorm.Expr("tasks.completed"))).Error
+}
+
+type StatusRepository interface {
+	Create(status *models.TaskStatus) error
+	FindByID(id uint) (*models.TaskStatus, error)
+	FindByList(listID uint) ([]models.TaskStatus, error)
+	NextPosition(listID uint) (int, error)
+	Update(status *models.TaskStatus) error
+	// CountTasks counts the tasks with the status, trashed ones included.
+	CountTasks(id uint) (int64, error)
+	// Delete removes the status, moving its tasks to the list's other
+	// statuses.
+	Delete(id uint) error
+}
+
+type gormStatusRepository struct {
+	db *gorm.DB
+}
+
+func NewGormStatusRepository(db *gorm.DB) StatusRepository {
+	return &gormStatusRepository{db: db}
+}
+
+func (r *gormStatusRepository) Create(status *models.TaskStatus) error {
+	result := r.db.Create(status)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return ErrStatusExists
+	}
+	return result.Error
+}
+
+func (r *gormStatusRepository) FindByID(id uint) (*models.TaskStatus, error) {
+	var status models.TaskStatus
+	result := r.db.First(&status, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrStatusNotFound
+	}
+	return &status, result.Error
+}
+
+// FindByList returns the list's statuses in board order.
+func (r *gormStatusRepository) FindByList(listID uint) ([]models.TaskStatus, error) {
+	var statuses []models.TaskStatus
+	result := r.db.Where("list_id = ?", listID).Order("position").Order("id").Find(&statuses)
+	return statuses, result.Error
+}
+
+// NextPosition returns a position that sorts after every status of the list.
+func (r *gormStatusRepository) NextPosition(listID uint) (int, error) {
+	var maxPosition int
+	query := r.db.Model(&models.TaskStatus{}).Where("list_id = ?", listID).Select("COALESCE(MAX(position), 0)")
+	if err := query.Scan(&maxPosition).Error; err != nil {
+		return 0, err
+	}
+	return maxPosition + 1, nil
+}
+
+func (r *gormStatusRepository) Update(status *models.TaskStatus) error {
+	result := r.db.Save(status)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return ErrStatusExists
+	}
+	return result.Error
+}
+
+func (r *gormStatusRepository) CountTasks(id uint) (int64, error) {
+	var count int64
+	result := r.db.Unscoped().Model(&models.Task{}).Where("status_id = ?", id).Count(&count)
+	return count, result.Error
+}
+
+func (r *gormStatusRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.TaskStatus{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStatusNotFound
+		}
+		return syncStatuses(tx, "status_id = ?", id)
+	})
+}
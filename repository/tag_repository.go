@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTagNotFound      = errors.New("tag not found")
+	ErrTagAlreadyExists = errors.New("tag with this name already exists")
+)
+
+type TagRepository interface {
+	Create(tag *models.Tag) error
+	FindByID(id uint) (*models.Tag, error)
+	FindByUser(userID uint) ([]models.Tag, error)
+	FindByIDs(userID uint, ids []uint) ([]models.Tag, error)
+	FindByNames(userID uint, names []string) ([]models.Tag, error)
+	Update(tag *models.Tag) error
+	Delete(id uint) error
+}
+
+type gormTagRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTagRepository(db *gorm.DB) TagRepository {
+	return &gormTagRepository{db: db}
+}
+
+func (r *gormTagRepository) Create(tag *models.Tag) error {
+	result := r.db.Create(tag)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return ErrTagAlreadyExists
+	}
+	return result.Error
+}
+
+func (r *gormTagRepository) FindByID(id uint) (*models.Tag, error) {
+	var tag models.Tag
+	result := r.db.First(&tag, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrTagNotFound
+	}
+	return &tag, result.Error
+}
+
+func (r *gormTagRepository) FindByUser(userID uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	result := r.db.Where("user_id = ?", userID).Order("name").Find(&tags)
+	return tags, result.Error
+}
+
+func (r *gormTagRepository) FindByIDs(userID uint, ids []uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	result := r.db.Where("user_id = ? AND id IN ?", userID, ids).Find(&tags)
+	return tags, result.Error
+}
+
+// FindByNames returns the user's tags with any of the given names, ignoring
+// case.
+func (r *gormTagRepository) FindByNames(userID uint, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(names) == 0 {
+		return tags, nil
+	}
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+	result := r.db.Where("user_id = ? AND LOWER(name) IN ?", userID, lowered).Order("id").Find(&tags)
+	return tags, result.Error
+}
+
+func (r *gormTagRepository) Update(tag *models.Tag) error {
+	result := r.db.Save(tag)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return ErrTagAlreadyExists
+	}
+	return result.Error
+}
+
+// Delete removes the tag for good, along with its task associations, so its
+// name can be reused right away.
+func (r *gormTagRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM task_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Delete(&models.Tag{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTagNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDependencyExists   = errors.New("task is already blocked by this task")
+	ErrDependencyNotFound = errors.New("dependency not found")
+)
+
+func (r *gormTaskRepository) AddDependency(dependency *models.TaskDependency) error {
+	result := r.db.Create(dependency)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return ErrDependencyExists
+	}
+	return result.Error
+}
+
+func (r *gormTaskRepository) RemoveDependency(taskID, blockedByID uint) error {
+	result := r.db.Where("task_id = ? AND blocked_by_id = ?", taskID, blockedByID).Delete(&models.TaskDependency{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDependencyNotFound
+	}
+	return nil
+}
+
+// FindBlockers returns the live tasks the task is blocked by.
+func (r *gormTaskRepository) FindBlockers(taskID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	result := r.db.Scopes(taskDetails).
+		Joins("JOIN task_dependencies ON task_dependencies.blocked_by_id = tasks.id").
+		Where("task_dependencies.task_id = ?", taskID).
+		Order("tasks.id").
+		Find(&tasks)
+	return tasks, result.Error
+}
+
+// FindDependents returns the live tasks blocked by the task.
+func (r *gormTaskRepository) FindDependents(taskID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	result := r.db.Scopes(taskDetails).
+		Joins("JOIN task_dependencies ON task_dependencies.task_id = tasks.id").
+		Where("task_dependencies.blocked_by_id = ?", taskID).
+		Order("tasks.id").
+		Find(&tasks)
+	return tasks, result.Error
+}
+
+// FindBlockerIDs returns the IDs of every task blocking any of the given
+// tasks, deleted or not.
+func (r *gormTaskRepository) FindBlockerIDs(ids []uint) ([]uint, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var blockerIDs []uint
+	result := r.db.Model(&models.TaskDependency{}).
+		Where("task_id IN ?", ids).
+		Distinct().
+		Pluck("blocked_by_id", &blockerIDs)
+	return blockerIDs, result.Error
+}
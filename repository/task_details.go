@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// taskStatColumns fill the computed, read-only fields of models.Task.
+var taskStatColumns = []string{
+	`(SELECT COUNT(*) FROM tasks AS subtasks
+		WHERE subtasks.parent_id = tasks.id AND subtasks.deleted_at IS NULL) AS subtask_count`,
+	`(SELECT COUNT(*) FROM tasks AS subtasks
+		WHERE subtasks.parent_id = tasks.id AND subtasks.deleted_at IS NULL AND subtasks.completed) AS completed_subtask_count`,
+	`(SELECT COUNT(*) FROM comments
+		WHERE comments.task_id = tasks.id AND comments.deleted_at IS NULL) AS comment_count`,
+	`EXISTS (SELECT 1 FROM task_dependencies
+		JOIN tasks AS blockers ON blockers.id = task_dependencies.blocked_by_id
+		WHERE task_dependencies.task_id = tasks.id AND blockers.deleted_at IS NULL AND NOT blockers.completed) AS blocked`,
+	`(SELECT COALESCE(SUM(EXTRACT(EPOCH FROM COALESCE(time_entries.ended_at, now()) - time_entries.started_at)), 0)::bigint
+		FROM time_entries WHERE time_entries.task_id = tasks.id) AS tracked_seconds`,
+}
+
+var taskStatsSQL = strings.Join(taskStatColumns, ", ")
+
+// taskDetails loads everything returned alongside a task: its tags and the
+// computed stats.
+func taskDetails(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tags").Select("tasks.*, " + taskStatsSQL)
+}
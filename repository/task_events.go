@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"github.com/RLRama/listario-backend/models"
+)
+
+const (
+	DefaultTaskHistoryPageSize = 50
+	MaxTaskHistoryPageSize     = 100
+)
+
+const taskEventCursorScope = "task_events"
+
+// taskEventKeys orders a task's history from the newest event to the oldest.
+var taskEventKeys = []sortKey{{expr: "task_events.id", desc: true, kind: sortKeyInt}}
+
+type TaskEventPage struct {
+	Events     []models.TaskEvent
+	NextCursor string
+}
+
+func (r *gormTaskRepository) RecordEvents(events ...models.TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.Create(&events).Error
+}
+
+// FindEvents returns a page of the task's history, newest first. Cursor is the
+// NextCursor of the previous page.
+func (r *gormTaskRepository) FindEvents(taskID uint, cursor string, limit int) (*TaskEventPage, error) {
+	query := r.db.Where("task_events.task_id = ?", taskID)
+
+	if cursor != "" {
+		values, err := decodeCursor(cursor, taskEventCursorScope, taskEventKeys)
+		if err != nil {
+			return nil, err
+		}
+		condition, args := keysetCondition(taskEventKeys, values)
+		query = query.Where(condition, args...)
+	}
+
+	if limit <= 0 || limit > MaxTaskHistoryPageSize {
+		limit = DefaultTaskHistoryPageSize
+	}
+
+	var events []models.TaskEvent
+	result := orderByKeys(query, taskEventKeys).Limit(limit + 1).Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	page := &TaskEventPage{Events: events}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		next, err := encodeCursorValues(taskEventCursorScope, []any{page.Events[limit-1].ID})
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = next
+	}
+	return page, nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const descendantIDsSQL = `
+	WITH RECURSIVE descendants AS (
+		SELECT id FROM tasks WHERE parent_id = ? AND deleted_at IS NULL
+		UNION
+		SELECT tasks.id FROM tasks JOIN descendants ON tasks.parent_id = descendants.id
+		WHERE tasks.deleted_at IS NULL
+	)
+	SELECT id FROM descendants`
+
+const ancestorIDsSQL = `
+	WITH RECURSIVE ancestors AS (
+		SELECT parent_id AS id FROM tasks WHERE id = ?
+		UNION
+		SELECT tasks.parent_id FROM tasks JOIN ancestors ON tasks.id = ancestors.id
+	)
+	SELECT id FROM ancestors WHERE id IS NOT NULL`
+
+func (r *gormTaskRepository) FindChildren(parentID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	result := r.db.Scopes(taskDetails).
+		Where("tasks.parent_id = ?", parentID).
+		Order("tasks.position").
+		Order("tasks.id").
+		Find(&tasks)
+	return tasks, result.Error
+}
+
+// FindDescendantIDs returns the IDs of every live subtask below the task, at
+// any depth.
+func (r *gormTaskRepository) FindDescendantIDs(id uint) ([]uint, error) {
+	return findIDs(r.db, descendantIDsSQL, id)
+}
+
+// FindAncestorIDs returns the IDs of the task's parent, grandparent and so on
+// up to the top-level task.
+func (r *gormTaskRepository) FindAncestorIDs(id uint) ([]uint, error) {
+	return findIDs(r.db, ancestorIDsSQL, id)
+}
+
+// SetCompleted completes or reopens the given tasks, and returns the IDs of
+// those that actually changed.
+func (r *gormTaskRepository) SetCompleted(ids []uint, completed bool) ([]uint, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var changed []models.Task
+	result := r.db.Model(&changed).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("id IN ? AND completed <> ?", ids, completed).
+		Updates(map[string]any{"completed": completed, "status_id": gorm.Expr(taskStatusSQL, completed)})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	changedIDs := make([]uint, len(changed))
+	for i, task := range changed {
+		changedIDs[i] = task.ID
+	}
+	return changedIDs, nil
+}
+
+// SetListID moves the tasks to the list, giving them its matching statuses.
+func (r *gormTaskRepository) SetListID(ids []uint, listID uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Task{}).Where("id IN ?", ids).Update("list_id", listID).Error; err != nil {
+			return err
+		}
+		return syncStatuses(tx, "id IN ?", ids)
+	})
+}
+
+// SetPositions renumbers the given tasks so they sort in the given order.
+func (r *gormTaskRepository) SetPositions(ids []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			if err := tx.Model(&models.Task{}).Where("id = ?", id).Update("position", float64(i+1)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// NextPosition returns a position that sorts after every sibling of the task.
+func (r *gormTaskRepository) NextPosition(task *models.Task) (float64, error) {
+	var maxPosition float64
+	query := siblings(r.db, task).Select("COALESCE(MAX(position), 0)")
+	if err := query.Scan(&maxPosition).Error; err != nil {
+		return 0, err
+	}
+	return maxPosition + 1, nil
+}
+
+// AdjacentPosition returns the position of the closest sibling on the given
+// side of the neighbour, ignoring the neighbour itself and the excluded task.
+// Siblings tied with the neighbour count as adjacent. It returns nil when the
+// neighbour is the first or last sibling.
+func (r *gormTaskRepository) AdjacentPosition(neighbour *models.Task, excludeID uint, after bool) (*float64, error) {
+	query := siblings(r.db, neighbour).
+		Where("id NOT IN ?", []uint{neighbour.ID, excludeID})
+	if after {
+		query = query.Select("MIN(position)").Where("position >= ?", neighbour.Position)
+	} else {
+		query = query.Select("MAX(position)").Where("position <= ?", neighbour.Position)
+	}
+
+	var position *float64
+	if err := query.Scan(&position).Error; err != nil {
+		return nil, err
+	}
+	return position, nil
+}
+
+// FindSiblingIDs returns the IDs of the task and its siblings, in their manual
+// order.
+func (r *gormTaskRepository) FindSiblingIDs(task *models.Task) ([]uint, error) {
+	var ids []uint
+	result := siblings(r.db, task).Order("position").Order("id").Pluck("id", &ids)
+	return ids, result.Error
+}
+
+// siblings selects the live tasks the task's manual position is relative to:
+// the subtasks of its parent, or the top-level tasks of its list, whoever owns
+// them, since lists are viewed in that order by everyone who can see them.
+// Top-level tasks outside any list, which only workspaces have, are ordered
+// per owner.
+func siblings(db *gorm.DB, task *models.Task) *gorm.DB {
+	query := db.Model(&models.Task{})
+	switch {
+	case task.ParentID != nil:
+		return query.Where("parent_id = ?", *task.ParentID)
+	case task.ListID != nil:
+		return query.Where("parent_id IS NULL AND list_id = ?", *task.ListID)
+	case task.WorkspaceID != nil:
+		return query.Where("parent_id IS NULL AND list_id IS NULL AND workspace_id = ? AND user_id = ?", *task.WorkspaceID, task.UserID)
+	}
+	return query.Where("parent_id IS NULL AND list_id IS NULL AND workspace_id IS NULL AND user_id = ?", task.UserID)
+}
+
+func findIDs(db *gorm.DB, sql string, values ...any) ([]uint, error) {
+	var ids []uint
+	result := db.Raw(sql, values...).Scan(&ids)
+	return ids, result.Error
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestSiblings(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=invalid"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := func(id uint) *uint { return &id }
+
+	tests := []struct {
+		name string
+		task models.Task
+		want string
+	}{
+		{
+			name: "subtask",
+			task: models.Task{UserID: 1, ParentID: id(9), ListID: id(3)},
+			want: `SELECT "id" FROM "tasks" WHERE parent_id = 9 AND "tasks"."deleted_at" IS NULL`,
+		},
+		{
+			name: "in a list",
+			task: models.Task{UserID: 1, ListID: id(3), WorkspaceID: id(7)},
+			want: `SELECT "id" FROM "tasks" WHERE (parent_id IS NULL AND list_id = 3) AND "tasks"."deleted_at" IS NULL`,
+		},
+		{
+			name: "list-less in a workspace",
+			task: models.Task{UserID: 1, WorkspaceID: id(7)},
+			want: `SELECT "id" FROM "tasks" WHERE (parent_id IS NULL AND list_id IS NULL AND workspace_id = 7 AND user_id = 1) AND "tasks"."deleted_at" IS NULL`,
+		},
+		{
+			name: "list-less and personal",
+			task: models.Task{UserID: 1},
+			want: `SELECT "id" FROM "tasks" WHERE (parent_id IS NULL AND list_id IS NULL AND workspace_id IS NULL AND user_id = 1) AND "tasks"."deleted_at" IS NULL`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				var ids []uint
+				return siblings(tx, &tt.task).Pluck("id", &ids)
+			})
+			if got != tt.want {
+				t.Errorf("SQL = %s\nwant  %s", got, tt.want)
+			}
+		})
+	}
+}
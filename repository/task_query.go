@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultTaskPageSize = 50
+	MaxTaskPageSize     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+)
+
+type TaskSort string
+
+const (
+	// TaskSortDefault orders tasks by their manual position.
+	TaskSortDefault   TaskSort = ""
+	TaskSortPosition  TaskSort = "position"
+	TaskSortCreatedAt TaskSort = "created_at"
+	TaskSortUpdatedAt TaskSort = "updated_at"
+	TaskSortDueDate   TaskSort = "due_date"
+	TaskSortTitle     TaskSort = "title"
+	// TaskSortPriority orders tasks from most to least important, then by
+	// earliest due date, leaving tasks without a due date last.
+	TaskSortPriority TaskSort = "priority"
+)
+
+func (s TaskSort) IsValid() bool {
+	switch s {
+	case TaskSortDefault, TaskSortPosition, TaskSortCreatedAt, TaskSortUpdatedAt, TaskSortDueDate, TaskSortTitle, TaskSortPriority:
+		return true
+	}
+	return false
+}
+
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+func (d SortDirection) IsValid() bool {
+	return d == "" || d == SortAsc || d == SortDesc
+}
+
+// TaskQuery describes a filtered, sorted page of a user's tasks. Zero values
+// mean "no filter"; Cursor is the NextCursor of the previous page.
+type TaskQuery struct {
+	UserID      uint
+	Completed   *bool
+	DueFrom     *time.Time
+	DueTo       *time.Time
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Text        string
+	TagIDs      []uint
+	ListID      *uint
+	// AnyOwner includes other users' tasks in the list given by ListID, for
+	// callers that have checked the user may see them.
+	AnyOwner bool
+	// AssignedToMe selects the tasks assigned to the user instead of the ones
+	// they own.
+	AssignedToMe bool
+	// WorkspaceID selects every task in the workspace rather than the user's
+	// personal tasks.
+	WorkspaceID *uint
+	// TopLevelOnly leaves out subtasks.
+	TopLevelOnly bool
+	Sort         TaskSort
+	Direction    SortDirection
+	Cursor       string
+	Limit        int
+}
+
+// cursorScope identifies the ordering a cursor was issued for, so a cursor
+// can't be replayed against a different sort.
+func (q TaskQuery) cursorScope() string {
+	return string(q.Sort) + ":" + string(q.Direction)
+}
+
+type TaskPage struct {
+	Tasks      []models.Task
+	NextCursor string
+}
+
+func applyTaskFilters(query *gorm.DB, q TaskQuery) *gorm.DB {
+	if q.Completed != nil {
+		query = query.Where("tasks.completed = ?", *q.Completed)
+	}
+	if q.DueFrom != nil {
+		query = query.Where("tasks.due_date >= ?", *q.DueFrom)
+	}
+	if q.DueTo != nil {
+		query = query.Where("tasks.due_date < ?", *q.DueTo)
+	}
+	if q.CreatedFrom != nil {
+		query = query.Where("tasks.created_at >= ?", *q.CreatedFrom)
+	}
+	if q.CreatedTo != nil {
+		query = query.Where("tasks.created_at < ?", *q.CreatedTo)
+	}
+	if q.TopLevelOnly {
+		query = query.Where("tasks.parent_id IS NULL")
+	}
+	if q.ListID != nil {
+		query = query.Where("tasks.list_id = ?", *q.ListID)
+	}
+	if len(q.TagIDs) > 0 {
+		query = query.Where("tasks.id IN (SELECT task_id FROM task_tags WHERE tag_id IN ?)", q.TagIDs)
+	}
+	if q.Text != "" {
+		pattern := "%" + escapeLike(q.Text) + "%"
+		query = query.Where("(tasks.title ILIKE ? OR tasks.content ILIKE ?)", pattern, pattern)
+	}
+	return query
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+type sortKeyKind int
+
+const (
+	sortKeyTime sortKeyKind = iota
+	sortKeyInt
+	sortKeyFloat
+	sortKeyString
+)
+
+// sortKey is one column of a keyset ordering. Nullable keys always sort their
+// NULLs last, whatever the direction.
+type sortKey struct {
+	expr     string
+	desc     bool
+	nullable bool
+	kind     sortKeyKind
+	value    func(task models.Task) any
+}
+
+func taskSortKeys(sort TaskSort, direction SortDirection) []sortKey {
+	// Priority reads most naturally from most to least important, so that is
+	// its default direction.
+	desc := direction == SortDesc || (direction == "" && sort == TaskSortPriority)
+	var keys []sortKey
+
+	switch sort {
+	case TaskSortUpdatedAt:
+		keys = []sortKey{{expr: "tasks.updated_at", desc: desc, kind: sortKeyTime, value: func(t models.Task) any { return t.UpdatedAt }}}
+	case TaskSortDueDate:
+		keys = []sortKey{dueDateKey(desc)}
+	case TaskSortTitle:
+		keys = []sortKey{{expr: "tasks.title", desc: desc, kind: sortKeyString, value: func(t models.Task) any { return t.Title }}}
+	case TaskSortPriority:
+		keys = []sortKey{
+			{expr: priorityRankSQL, desc: desc, kind: sortKeyInt, value: func(t models.Task) any { return t.Priority.Rank() }},
+			dueDateKey(false),
+		}
+	case TaskSortCreatedAt:
+		keys = []sortKey{{expr: "tasks.created_at", desc: desc, kind: sortKeyTime, value: func(t models.Task) any { return t.CreatedAt }}}
+	case TaskSortDefault, TaskSortPosition:
+		keys = []sortKey{{expr: "tasks.position", desc: desc, kind: sortKeyFloat, value: func(t models.Task) any { return t.Position }}}
+	}
+
+	return append(keys, sortKey{expr: "tasks.id", desc: desc && sort != TaskSortPriority, kind: sortKeyInt, value: func(t models.Task) any { return t.ID }})
+}
+
+func dueDateKey(desc bool) sortKey {
+	return sortKey{expr: "tasks.due_date", desc: desc, nullable: true, kind: sortKeyTime, value: func(t models.Task) any {
+		if t.DueDate == nil {
+			return nil
+		}
+		return *t.DueDate
+	}}
+}
+
+func orderByKeys(query *gorm.DB, keys []sortKey) *gorm.DB {
+	for _, key := range keys {
+		order := key.expr + " ASC"
+		if key.desc {
+			order = key.expr + " DESC"
+		}
+		if key.nullable {
+			order += " NULLS LAST"
+		}
+		query = query.Order(order)
+	}
+	return query
+}
+
+// keysetCondition builds the WHERE clause selecting rows that come strictly
+// after the row whose key values are given, following the keys' ordering.
+func keysetCondition(keys []sortKey, values []any) (string, []any) {
+	var terms []string
+	var args []any
+
+	for i, key := range keys {
+		var parts []string
+		var partArgs []any
+
+		for j := 0; j < i; j++ {
+			if values[j] == nil {
+				parts = append(parts, keys[j].expr+" IS NULL")
+			} else {
+				parts = append(parts, keys[j].expr+" = ?")
+				partArgs = append(partArgs, values[j])
+			}
+		}
+
+		// Nothing sorts after a NULL, since NULLs always come last.
+		if values[i] == nil {
+			continue
+		}
+		op := " > ?"
+		if key.desc {
+			op = " < ?"
+		}
+		after := key.expr + op
+		if key.nullable {
+			after = "(" + after + " OR " + key.expr + " IS NULL)"
+		}
+		parts = append(parts, after)
+		partArgs = append(partArgs, values[i])
+
+		terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+		args = append(args, partArgs...)
+	}
+
+	if len(terms) == 0 {
+		return "FALSE", nil
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", args
+}
+
+type cursorPayload struct {
+	Scope  string `json:"s"`
+	Values []any  `json:"v"`
+}
+
+func encodeCursor(scope string, keys []sortKey, task models.Task) (string, error) {
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		values[i] = key.value(task)
+	}
+	return encodeCursorValues(scope, values)
+}
+
+// encodeCursorValues builds a cursor from the key values of the last row of a
+// page, in the order decodeCursor expects them.
+func encodeCursorValues(scope string, values []any) (string, error) {
+	payload := cursorPayload{Scope: scope, Values: make([]any, len(values))}
+	for i, value := range values {
+		if t, ok := value.(time.Time); ok {
+			value = t.UTC().Format(time.RFC3339Nano)
+		}
+		payload.Values[i] = value
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(cursor, scope string, keys []sortKey) ([]any, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if payload.Scope != scope || len(payload.Values) != len(keys) {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		if payload.Values[i] == nil {
+			if !key.nullable {
+				return nil, ErrInvalidCursor
+			}
+			continue
+		}
+
+		switch key.kind {
+		case sortKeyTime:
+			s, ok := payload.Values[i].(string)
+			if !ok {
+				return nil, ErrInvalidCursor
+			}
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			values[i] = t
+		case sortKeyInt:
+			n, ok := payload.Values[i].(json.Number)
+			if !ok {
+				return nil, ErrInvalidCursor
+			}
+			v, err := n.Int64()
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			values[i] = v
+		case sortKeyFloat:
+			n, ok := payload.Values[i].(json.Number)
+			if !ok {
+				return nil, ErrInvalidCursor
+			}
+			v, err := n.Float64()
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			values[i] = v
+		case sortKeyString:
+			s, ok := payload.Values[i].(string)
+			if !ok {
+				return nil, ErrInvalidCursor
+			}
+			values[i] = s
+		}
+	}
+	return values, nil
+}
+
+// priorityRankSQL maps the priority column to its models.TaskPriority rank so
+// tasks can be ordered by importance rather than alphabetically.
+var priorityRankSQL = func() string {
+	var b strings.Builder
+	b.WriteString("CASE tasks.priority")
+	for rank, priority := range models.TaskPriorities {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", priority, rank)
+	}
+	b.WriteString(" ELSE 0 END")
+	return b.String()
+}()
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2026, time.October, 14, 15, 30, 0, 123456789, time.FixedZone("ART", -3*60*60))
+	due := time.Date(2026, time.October, 20, 12, 0, 0, 0, time.UTC)
+	task := models.Task{
+		Title:    "Pay rent",
+		Priority: models.PriorityHigh,
+		Position: 1.5,
+		DueDate:  &due,
+	}
+	task.ID = 42
+	task.CreatedAt = created
+	task.UpdatedAt = created.Add(time.Hour)
+	undated := task
+	undated.DueDate = nil
+
+	tests := []struct {
+		name string
+		sort TaskSort
+		task models.Task
+		want []any
+	}{
+		{name: "position", sort: TaskSortPosition, task: task, want: []any{1.5, int64(42)}},
+		{name: "created_at", sort: TaskSortCreatedAt, task: task, want: []any{created.UTC(), int64(42)}},
+		{name: "updated_at", sort: TaskSortUpdatedAt, task: task, want: []any{created.Add(time.Hour).UTC(), int64(42)}},
+		{name: "title", sort: TaskSortTitle, task: task, want: []any{"Pay rent", int64(42)}},
+		{name: "due_date", sort: TaskSortDueDate, task: task, want: []any{due, int64(42)}},
+		{name: "due_date without one", sort: TaskSortDueDate, task: undated, want: []any{nil, int64(42)}},
+		{name: "priority", sort: TaskSortPriority, task: undated, want: []any{int64(models.PriorityHigh.Rank()), nil, int64(42)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := TaskQuery{Sort: tt.sort}.cursorScope()
+			keys := taskSortKeys(tt.sort, "")
+
+			cursor, err := encodeCursor(scope, keys, tt.task)
+			if err != nil {
+				t.Fatalf("encodeCursor: %v", err)
+			}
+			got, err := decodeCursor(cursor, scope, keys)
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("decoded %d values, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if want, ok := tt.want[i].(time.Time); ok {
+					if value, ok := got[i].(time.Time); !ok || !value.Equal(want) {
+						t.Errorf("value %d = %#v, want %v", i, got[i], want)
+					}
+					continue
+				}
+				if !reflect.DeepEqual(got[i], tt.want[i]) {
+					t.Errorf("value %d = %#v, want %#v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	keys := taskSortKeys(TaskSortDueDate, SortAsc)
+	scope := TaskQuery{Sort: TaskSortDueDate, Direction: SortAsc}.cursorScope()
+	encode := func(payload string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(payload))
+	}
+	valid, err := encodeCursorValues(scope, []any{time.Date(2026, time.October, 20, 0, 0, 0, 0, time.UTC), 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		scope  string
+	}{
+		{name: "another sort", cursor: valid, scope: TaskQuery{Sort: TaskSortCreatedAt, Direction: SortAsc}.cursorScope()},
+		{name: "another direction", cursor: valid, scope: TaskQuery{Sort: TaskSortDueDate, Direction: SortDesc}.cursorScope()},
+		{name: "not base64", cursor: "not a cursor!", scope: scope},
+		{name: "not JSON", cursor: encode("due_date:asc"), scope: scope},
+		{name: "too few values", cursor: encode(`{"s":"due_date:asc","v":[42]}`), scope: scope},
+		{name: "null for a key that can't be", cursor: encode(`{"s":"due_date:asc","v":["2026-10-20T00:00:00Z",null]}`), scope: scope},
+		{name: "malformed time", cursor: encode(`{"s":"due_date:asc","v":["yesterday",42]}`), scope: scope},
+		{name: "string for a number", cursor: encode(`{"s":"due_date:asc","v":["2026-10-20T00:00:00Z","42"]}`), scope: scope},
+		{name: "fraction for an integer", cursor: encode(`{"s":"due_date:asc","v":["2026-10-20T00:00:00Z",4.2]}`), scope: scope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.cursor, tt.scope, keys); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeCursor returned %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+
+	if _, err := decodeCursor(valid, scope, keys); err != nil {
+		t.Errorf("decodeCursor rejected a cursor for its own scope: %v", err)
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	due := time.Date(2026, time.October, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		sort      TaskSort
+		direction SortDirection
+		values    []any
+		wantSQL   string
+		wantArgs  []any
+	}{
+		{
+			name:     "position",
+			sort:     TaskSortPosition,
+			values:   []any{1.5, int64(42)},
+			wantSQL:  "((tasks.position > ?) OR (tasks.position = ? AND tasks.id > ?))",
+			wantArgs: []any{1.5, 1.5, int64(42)},
+		},
+		{
+			name:      "title descending",
+			sort:      TaskSortTitle,
+			direction: SortDesc,
+			values:    []any{"Pay rent", int64(42)},
+			wantSQL:   "((tasks.title < ?) OR (tasks.title = ? AND tasks.id < ?))",
+			wantArgs:  []any{"Pay rent", "Pay rent", int64(42)},
+		},
+		{
+			name:     "due date, after a dated task",
+			sort:     TaskSortDueDate,
+			values:   []any{due, int64(42)},
+			wantSQL:  "(((tasks.due_date > ? OR tasks.due_date IS NULL)) OR (tasks.due_date = ? AND tasks.id > ?))",
+			wantArgs: []any{due, due, int64(42)},
+		},
+		{
+			name:      "due date descending, after a dated task",
+			sort:      TaskSortDueDate,
+			direction: SortDesc,
+			values:    []any{due, int64(42)},
+			wantSQL:   "(((tasks.due_date < ? OR tasks.due_date IS NULL)) OR (tasks.due_date = ? AND tasks.id < ?))",
+			wantArgs:  []any{due, due, int64(42)},
+		},
+		{
+			// NULLs sort last, so only other undated tasks can follow one.
+			name:     "due date, after an undated task",
+			sort:     TaskSortDueDate,
+			values:   []any{nil, int64(42)},
+			wantSQL:  "((tasks.due_date IS NULL AND tasks.id > ?))",
+			wantArgs: []any{int64(42)},
+		},
+		{
+			name:     "priority, after an undated task",
+			sort:     TaskSortPriority,
+			values:   []any{int64(3), nil, int64(42)},
+			wantSQL:  "((" + priorityRankSQL + " < ?) OR (" + priorityRankSQL + " = ? AND tasks.due_date IS NULL AND tasks.id > ?))",
+			wantArgs: []any{int64(3), int64(3), int64(42)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := keysetCondition(taskSortKeys(tt.sort, tt.direction), tt.values)
+			if sql != tt.wantSQL {
+				t.Errorf("SQL = %s\nwant  %s", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/RLRama/listario-backend/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
@@ -12,11 +14,54 @@ var (
 )
 
 type TaskRepository interface {
+	// Transaction runs fn with a repository whose queries all belong to one
+	// database transaction, committed only if fn returns nil.
+	Transaction(fn func(repo TaskRepository) error) error
+	// Tags returns a tag repository on the same connection, so that within a
+	// transaction tags are read and created as part of it.
+	Tags() TagRepository
+
 	Create(task *models.Task) error
 	FindByID(id uint) (*models.Task, error)
-	FindByUser(userID uint) ([]models.Task, error)
+	FindByIDs(ids []uint) ([]models.Task, error)
+	FindByUser(userID uint, workspaceID *uint, sort TaskSort) ([]models.Task, error)
+	FindSharedWith(userID uint) ([]models.Task, error)
+	FindByList(listID uint) ([]models.Task, error)
+	FindPage(query TaskQuery) (*TaskPage, error)
+	Search(userID uint, workspaceID *uint, text string, limit, offset int) ([]TaskSearchResult, error)
 	Update(task *models.Task) error
+	ReplaceTags(task *models.Task, tags []models.Tag) error
+	AddTags(task *models.Task, tags []models.Tag) error
 	Delete(id uint) error
+
+	FindChildren(parentID uint) ([]models.Task, error)
+	FindDescendantIDs(id uint) ([]uint, error)
+	FindAncestorIDs(id uint) ([]uint, error)
+	SetCompleted(ids []uint, completed bool) ([]uint, error)
+	SetListID(ids []uint, listID uint) error
+	SetPositions(ids []uint) error
+	NextPosition(task *models.Task) (float64, error)
+	AdjacentPosition(neighbour *models.Task, excludeID uint, after bool) (*float64, error)
+	FindSiblingIDs(task *models.Task) ([]uint, error)
+
+	FindTrash(userID uint) ([]models.Task, error)
+	FindTrashedByID(id uint) (*models.Task, error)
+	Restore(id uint) error
+	FindSubtreeIDs(id uint) ([]uint, error)
+	FindTrashedBefore(cutoff time.Time) ([]uint, error)
+	Purge(ids []uint) ([]string, error)
+
+	RecordEvents(events ...models.TaskEvent) error
+	FindEvents(taskID uint, cursor string, limit int) (*TaskEventPage, error)
+
+	UpdateSeriesRule(rootID uint, rule string) error
+	HasOccurrenceAfter(rootID uint, dueDate time.Time) (bool, error)
+
+	AddDependency(dependency *models.TaskDependency) error
+	RemoveDependency(taskID, blockedByID uint) error
+	FindBlockers(taskID uint) ([]models.Task, error)
+	FindDependents(taskID uint) ([]models.Task, error)
+	FindBlockerIDs(ids []uint) ([]uint, error)
 }
 
 type gormTaskRepository struct {
@@ -27,36 +72,180 @@ func NewGormTaskRepository(db *gorm.DB) TaskRepository {
 	return &gormTaskRepository{db: db}
 }
 
+func (r *gormTaskRepository) Transaction(fn func(repo TaskRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&gormTaskRepository{db: tx})
+	})
+}
+
+func (r *gormTaskRepository) Tags() TagRepository {
+	return NewGormTagRepository(r.db)
+}
+
 func (r *gormTaskRepository) Create(task *models.Task) error {
-	return r.db.Create(task).Error
+	if err := r.db.Create(task).Error; err != nil {
+		return err
+	}
+	return r.syncStatus(task)
 }
 
 func (r *gormTaskRepository) FindByID(id uint) (*models.Task, error) {
 	var task models.Task
-	result := r.db.First(&task, id)
+	result := r.db.Scopes(taskDetails).First(&task, id)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, ErrTaskNotFound
 	}
 	return &task, result.Error
 }
 
-func (r *gormTaskRepository) FindByUser(userID uint) ([]models.Task, error) {
+func (r *gormTaskRepository) FindByIDs(ids []uint) ([]models.Task, error) {
 	var tasks []models.Task
-	result := r.db.Where("user_id = ?", userID).Find(&tasks)
+	result := r.db.Where("id IN ?", ids).Find(&tasks)
 	return tasks, result.Error
 }
 
-func (r *gormTaskRepository) Update(task *models.Task) error {
-	return r.db.Save(task).Error
+// FindByUser returns the user's personal tasks, or every task in the
+// workspace when workspaceID is set.
+func (r *gormTaskRepository) FindByUser(userID uint, workspaceID *uint, sort TaskSort) ([]models.Task, error) {
+	var tasks []models.Task
+	query := r.db.Scopes(taskDetails, taskSpace(userID, workspaceID))
+	query = orderByKeys(query, taskSortKeys(sort, ""))
+
+	result := query.Find(&tasks)
+	return tasks, result.Error
 }
 
-func (r *gormTaskRepository) Delete(id uint) error {
-	result := r.db.Delete(&models.Task{}, id)
+// FindSharedWith returns the tasks shared directly with the user.
+func (r *gormTaskRepository) FindSharedWith(userID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	result := r.db.Scopes(taskDetails).
+		Where("tasks.id IN (SELECT task_id FROM shares WHERE user_id = ?)", userID).
+		Find(&tasks)
+	return tasks, result.Error
+}
+
+// FindByList returns the top-level tasks in the list, whoever owns them, in
+// their manual order.
+func (r *gormTaskRepository) FindByList(listID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	result := r.db.Scopes(taskDetails).
+		Where("tasks.list_id = ? AND tasks.parent_id IS NULL", listID).
+		Order("tasks.position").
+		Order("tasks.id").
+		Find(&tasks)
+	return tasks, result.Error
+}
+
+// taskSpace selects every task in the workspace, or the user's personal tasks
+// when workspaceID is nil.
+func taskSpace(userID uint, workspaceID *uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if workspaceID != nil {
+			return db.Where("tasks.workspace_id = ?", *workspaceID)
+		}
+		return db.Where("tasks.user_id = ? AND tasks.workspace_id IS NULL", userID)
+	}
+}
+
+func (r *gormTaskRepository) FindPage(q TaskQuery) (*TaskPage, error) {
+	keys := taskSortKeys(q.Sort, q.Direction)
+
+	query := r.db.Scopes(taskDetails)
+	switch {
+	case q.AssignedToMe:
+		query = query.Where("tasks.assignee_id = ?", q.UserID)
+	case q.AnyOwner && q.ListID != nil:
+	default:
+		query = query.Scopes(taskSpace(q.UserID, q.WorkspaceID))
+	}
+	query = applyTaskFilters(query, q)
+
+	if q.Cursor != "" {
+		values, err := decodeCursor(q.Cursor, q.cursorScope(), keys)
+		if err != nil {
+			return nil, err
+		}
+		condition, args := keysetCondition(keys, values)
+		query = query.Where(condition, args...)
+	}
+
+	limit := q.Limit
+	if limit <= 0 || limit > MaxTaskPageSize {
+		limit = DefaultTaskPageSize
+	}
+
+	var tasks []models.Task
+	result := orderByKeys(query, keys).Limit(limit + 1).Find(&tasks)
 	if result.Error != nil {
-		return result.Error
+		return nil, result.Error
 	}
-	if result.RowsAffected == 0 {
-		return ErrTaskNotFound
+
+	page := &TaskPage{Tasks: tasks}
+	if len(tasks) > limit {
+		page.Tasks = tasks[:limit]
+		cursor, err := encodeCursor(q.cursorScope(), keys, page.Tasks[limit-1])
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = cursor
 	}
-	return nil
+	return page, nil
+}
+
+func (r *gormTaskRepository) Update(task *models.Task) error {
+	if err := r.db.Omit(clause.Associations).Save(task).Error; err != nil {
+		return err
+	}
+	return r.syncStatus(task)
+}
+
+// syncStatus gives the task the status that fits its list and completion,
+// keeping its current one if it still does.
+func (r *gormTaskRepository) syncStatus(task *models.Task) error {
+	return r.db.Raw("UPDATE tasks SET status_id = "+taskStatusSQL+" WHERE id = ? RETURNING status_id", task.Completed, task.ID).
+		Row().Scan(&task.StatusID)
+}
+
+func (r *gormTaskRepository) ReplaceTags(task *models.Task, tags []models.Tag) error {
+	return r.db.Model(task).Association("Tags").Replace(tags)
+}
+
+func (r *gormTaskRepository) AddTags(task *models.Task, tags []models.Tag) error {
+	return r.db.Model(task).Association("Tags").Append(tags)
+}
+
+// Delete soft-deletes the task together with all of its subtasks, in a single
+// statement so they share the same deletion time.
+func (r *gormTaskRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ids, err := findIDs(tx, descendantIDsSQL, id)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.Task{}, append(ids, id))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTaskNotFound
+		}
+		return nil
+	})
+}
+
+// UpdateSeriesRule changes the recurrence rule of every open occurrence in the
+// series. Completed occurrences keep the rule they were completed under.
+func (r *gormTaskRepository) UpdateSeriesRule(rootID uint, rule string) error {
+	return r.db.Model(&models.Task{}).
+		Where("(id = ? OR series_id = ?) AND completed = ?", rootID, rootID, false).
+		Update("recurrence_rule", rule).Error
+}
+
+func (r *gormTaskRepository) HasOccurrenceAfter(rootID uint, dueDate time.Time) (bool, error) {
+	var count int64
+	result := r.db.Model(&models.Task{}).
+		Where("(id = ? OR series_id = ?) AND due_date > ?", rootID, rootID, dueDate).
+		Count(&count)
+	return count > 0, result.Error
 }
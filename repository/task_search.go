@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"errors"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 50
+)
+
+var (
+	ErrEmptySearchQuery = errors.New("search query has no searchable terms")
+)
+
+// The headline marks matches with private-use characters rather than HTML, so
+// the snippet can be escaped as a whole before the markers become <mark> tags.
+const (
+	snippetStartSel = "\ue000"
+	snippetStopSel  = "\ue001"
+
+	searchHeadlineOptions = "StartSel=" + snippetStartSel + ", StopSel=" + snippetStopSel + ", MaxFragments=2, MaxWords=20, MinWords=5"
+)
+
+var snippetMarkers = strings.NewReplacer(snippetStartSel, "<mark>", snippetStopSel, "</mark>")
+
+type TaskSearchResult struct {
+	models.Task
+	Rank    float64
+	Snippet string
+}
+
+// Search finds the user's personal tasks, or the tasks of the workspace when
+// workspaceID is set, matching every word of the text as a prefix.
+func (r *gormTaskRepository) Search(userID uint, workspaceID *uint, text string, limit, offset int) ([]TaskSearchResult, error) {
+	tsQuery := prefixTSQuery(text)
+	if tsQuery == "" {
+		return nil, ErrEmptySearchQuery
+	}
+	if limit <= 0 || limit > MaxSearchLimit {
+		limit = DefaultSearchLimit
+	}
+
+	var results []TaskSearchResult
+	result := r.db.Model(&models.Task{}).
+		Select("tasks.*, "+taskStatsSQL+", ts_rank(tasks.search_vector, search_query) AS rank, "+
+			"ts_headline('simple', coalesce(tasks.title, '') || ' ' || coalesce(tasks.content, ''), search_query, ?) AS snippet",
+			searchHeadlineOptions).
+		Joins("CROSS JOIN to_tsquery('simple', ?) AS search_query", tsQuery).
+		Scopes(taskSpace(userID, workspaceID)).
+		Where("tasks.search_vector @@ search_query").
+		Order("rank DESC").
+		Order("tasks.id").
+		Limit(limit).
+		Offset(offset).
+		Scan(&results)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for i := range results {
+		results[i].Snippet = highlightSnippet(results[i].Snippet)
+	}
+	return results, r.loadSearchResultTags(results)
+}
+
+// highlightSnippet turns a headline into HTML: the task's text is escaped, so
+// the only markup is the <mark> tags around the matches.
+func highlightSnippet(headline string) string {
+	return snippetMarkers.Replace(html.EscapeString(headline))
+}
+
+// loadSearchResultTags fills in the tags of each result, since Preload doesn't
+// apply to raw scans.
+func (r *gormTaskRepository) loadSearchResultTags(results []TaskSearchResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(results))
+	for i, result := range results {
+		ids[i] = result.ID
+	}
+
+	var tasks []models.Task
+	if err := r.db.Preload("Tags").Select("id").Find(&tasks, ids).Error; err != nil {
+		return err
+	}
+
+	tagsByTask := make(map[uint][]models.Tag, len(tasks))
+	for _, task := range tasks {
+		tagsByTask[task.ID] = task.Tags
+	}
+	for i := range results {
+		results[i].Tags = tagsByTask[results[i].ID]
+	}
+	return nil
+}
+
+// prefixTSQuery turns free text into a tsquery matching every word as a
+// prefix, e.g. "gro list" becomes "gro:* & list:*". Anything that isn't a
+// letter or digit is dropped so user input can't inject tsquery operators.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = word + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
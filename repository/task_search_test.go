@@ -0,0 +1,64 @@
+package repository
+
+import "testing"
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		name     string
+		headline string
+		want     string
+	}{
+		{
+			name:     "plain text",
+			headline: "Buy \ue000milk\ue001 on the way home",
+			want:     "Buy <mark>milk</mark> on the way home",
+		},
+		{
+			name:     "script title",
+			headline: "<script>alert(document.cookie)</script> \ue000groceries\ue001",
+			want:     "&lt;script&gt;alert(document.cookie)&lt;/script&gt; <mark>groceries</mark>",
+		},
+		{
+			name:     "markup in the match",
+			headline: "\ue000<b>bold\ue001</b>",
+			want:     "<mark>&lt;b&gt;bold</mark>&lt;/b&gt;",
+		},
+		{
+			name:     "attribute breakout",
+			headline: "Fix \"quotes\" & 'apostrophes' in \ue000titles\ue001",
+			want:     "Fix &#34;quotes&#34; &amp; &#39;apostrophes&#39; in <mark>titles</mark>",
+		},
+		{
+			name:     "already escaped text stays literal",
+			headline: "&lt;mark&gt; is not a \ue000match\ue001",
+			want:     "&amp;lt;mark&amp;gt; is not a <mark>match</mark>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightSnippet(tt.headline); got != tt.want {
+				t.Errorf("highlightSnippet = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixTSQuery(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "gro list", want: "gro:* & list:*"},
+		{text: "Café", want: "café:*"},
+		{text: "a & b | !c:*", want: "a:* & b:* & c:*"},
+		{text: "<script>", want: "script:*"},
+		{text: "!!!", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := prefixTSQuery(tt.text); got != tt.want {
+			t.Errorf("prefixTSQuery(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+// trashedSubtreeIDsSQL finds the subtasks deleted together with a task, which
+// share its deletion time. Subtasks trashed on their own earlier stay trashed
+// when the task is restored.
+const trashedSubtreeIDsSQL = `
+	WITH RECURSIVE subtree AS (
+		SELECT id, deleted_at FROM tasks WHERE id = ?
+		UNION
+		SELECT tasks.id, tasks.deleted_at FROM tasks JOIN subtree ON tasks.parent_id = subtree.id
+		WHERE tasks.deleted_at = subtree.deleted_at
+	)
+	SELECT id FROM subtree`
+
+// subtreeIDsSQL finds a task and every task below it, deleted or not.
+const subtreeIDsSQL = `
+	WITH RECURSIVE subtree AS (
+		SELECT id FROM tasks WHERE id = ?
+		UNION
+		SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.id
+	)
+	SELECT id FROM subtree`
+
+// FindTrash returns the user's deleted tasks, most recently deleted first.
+// Subtasks deleted along with their parent are left out, since they are
+// restored and purged with it.
+func (r *gormTaskRepository) FindTrash(userID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	result := r.db.Unscoped().Scopes(taskDetails).
+		Where("tasks.user_id = ? AND tasks.deleted_at IS NOT NULL", userID).
+		Where(`NOT EXISTS (SELECT 1 FROM tasks AS parents
+			WHERE parents.id = tasks.parent_id AND parents.deleted_at = tasks.deleted_at)`).
+		Order("tasks.deleted_at DESC").
+		Order("tasks.id DESC").
+		Find(&tasks)
+	return tasks, result.Error
+}
+
+func (r *gormTaskRepository) FindTrashedByID(id uint) (*models.Task, error) {
+	var task models.Task
+	result := r.db.Unscoped().Scopes(taskDetails).Where("tasks.deleted_at IS NOT NULL").First(&task, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrTaskNotFound
+	}
+	return &task, result.Error
+}
+
+// Restore brings the task back from the trash, together with the subtasks
+// that were deleted with it.
+func (r *gormTaskRepository) Restore(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ids, err := findIDs(tx, trashedSubtreeIDsSQL, id)
+		if err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.Task{}).
+			Where("id IN ? AND deleted_at IS NOT NULL", ids).
+			Update("deleted_at", nil).Error
+	})
+}
+
+// FindSubtreeIDs returns the IDs of the task and every task below it, deleted
+// or not.
+func (r *gormTaskRepository) FindSubtreeIDs(id uint) ([]uint, error) {
+	return findIDs(r.db, subtreeIDsSQL, id)
+}
+
+// FindTrashedBefore returns the IDs of the tasks that have been in the trash
+// since before the cutoff.
+func (r *gormTaskRepository) FindTrashedBefore(cutoff time.Time) ([]uint, error) {
+	var ids []uint
+	result := r.db.Unscoped().Model(&models.Task{}).Where("deleted_at < ?", cutoff).Pluck("id", &ids)
+	return ids, result.Error
+}
+
+// Purge permanently deletes the tasks along with the rows that belong to them,
+// and returns the storage keys of their attachments so the caller can delete
+// the blobs.
+func (r *gormTaskRepository) Purge(ids []uint) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var keys []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Attachment{}).Where("task_id IN ?", ids).Pluck("storage_key", &keys).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN ?", ids).Delete(&models.Attachment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM task_tags WHERE task_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN ?", ids).Delete(&models.TaskEvent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("task_id IN ?", ids).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN ?", ids).Delete(&models.Share{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN ? OR blocked_by_id IN ?", ids, ids).Delete(&models.TaskDependency{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN ?", ids).Delete(&models.Reminder{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN ?", ids).Delete(&models.Notification{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN ?", ids).Delete(&models.TimeEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Task{}, ids).Error
+	})
+	return keys, err
+}
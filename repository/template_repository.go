@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+)
+
+type TemplateRepository interface {
+	// Create stores the template along with its whole task tree.
+	Create(template *models.TaskTemplate) error
+	FindByID(id uint) (*models.TaskTemplate, error)
+	// FindByUser returns the templates in the user's personal space.
+	FindByUser(userID uint) ([]models.TaskTemplate, error)
+	FindByWorkspace(workspaceID uint) ([]models.TaskTemplate, error)
+	Delete(id uint) error
+}
+
+type gormTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTemplateRepository(db *gorm.DB) TemplateRepository {
+	return &gormTemplateRepository{db: db}
+}
+
+func (r *gormTemplateRepository) Create(template *models.TaskTemplate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(template).Error; err != nil {
+			return err
+		}
+		return createTemplateTask(tx, template.ID, nil, 0, &template.Root)
+	})
+}
+
+// createTemplateTask stores the task and, below it, its subtasks in order.
+func createTemplateTask(tx *gorm.DB, templateID uint, parentID *uint, position int, task *models.TemplateTask) error {
+	task.TemplateID = templateID
+	task.ParentID = parentID
+	task.Position = position
+	if err := tx.Create(task).Error; err != nil {
+		return err
+	}
+	for i := range task.Subtasks {
+		if err := createTemplateTask(tx, templateID, &task.ID, i+1, &task.Subtasks[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *gormTemplateRepository) FindByID(id uint) (*models.TaskTemplate, error) {
+	var template models.TaskTemplate
+	result := r.db.First(&template, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrTemplateNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	templates := []models.TaskTemplate{template}
+	if err := r.loadTrees(templates); err != nil {
+		return nil, err
+	}
+	return &templates[0], nil
+}
+
+func (r *gormTemplateRepository) FindByUser(userID uint) ([]models.TaskTemplate, error) {
+	return r.findTemplates(r.db.Where("user_id = ? AND workspace_id IS NULL", userID))
+}
+
+func (r *gormTemplateRepository) FindByWorkspace(workspaceID uint) ([]models.TaskTemplate, error) {
+	return r.findTemplates(r.db.Where("workspace_id = ?", workspaceID))
+}
+
+func (r *gormTemplateRepository) findTemplates(query *gorm.DB) ([]models.TaskTemplate, error) {
+	var templates []models.TaskTemplate
+	if err := query.Order("name").Order("id").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	if err := r.loadTrees(templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// loadTrees loads the tasks of the templates and nests them under their
+// roots.
+func (r *gormTemplateRepository) loadTrees(templates []models.TaskTemplate) error {
+	if len(templates) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(templates))
+	for i, template := range templates {
+		ids[i] = template.ID
+	}
+
+	var tasks []models.TemplateTask
+	result := r.db.Where("template_id IN ?", ids).Order("position").Order("id").Find(&tasks)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	children := make(map[uint][]models.TemplateTask)
+	roots := make(map[uint]models.TemplateTask)
+	for _, task := range tasks {
+		if task.ParentID == nil {
+			roots[task.TemplateID] = task
+		} else {
+			children[*task.ParentID] = append(children[*task.ParentID], task)
+		}
+	}
+	for i := range templates {
+		root := roots[templates[i].ID]
+		nestTemplateTasks(&root, children)
+		templates[i].Root = root
+	}
+	return nil
+}
+
+func nestTemplateTasks(task *models.TemplateTask, children map[uint][]models.TemplateTask) {
+	task.Subtasks = children[task.ID]
+	for i := range task.Subtasks {
+		nestTemplateTasks(&task.Subtasks[i], children)
+	}
+}
+
+func (r *gormTemplateRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", id).Delete(&models.TemplateTask{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.TaskTemplate{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTemplateNotFound
+		}
+		return nil
+	})
+}
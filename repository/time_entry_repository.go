@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTimeEntryNotFound = errors.New("time entry not found")
+	ErrTimerRunning      = errors.New("another timer is already running")
+)
+
+type TimeEntryRepository interface {
+	Create(entry *models.TimeEntry) error
+	FindByID(id uint) (*models.TimeEntry, error)
+	// FindByTask returns the entries tracked on the task, newest first.
+	FindByTask(taskID uint) ([]models.TimeEntry, error)
+	// FindByUser returns the user's entries overlapping the period, oldest
+	// first, along with their tasks, trashed or not.
+	FindByUser(userID uint, from, to time.Time) ([]models.TimeEntry, error)
+	// FindRunning returns the user's running timer.
+	FindRunning(userID uint) (*models.TimeEntry, error)
+	// StartTimer stops the user's running timer, if any, when the new one
+	// starts, and then starts it.
+	StartTimer(entry *models.TimeEntry) error
+	Update(entry *models.TimeEntry) error
+	Delete(id uint) error
+}
+
+type gormTimeEntryRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTimeEntryRepository(db *gorm.DB) TimeEntryRepository {
+	return &gormTimeEntryRepository{db: db}
+}
+
+func (r *gormTimeEntryRepository) Create(entry *models.TimeEntry) error {
+	return translateTimerError(r.db.Omit("Task").Create(entry).Error)
+}
+
+func (r *gormTimeEntryRepository) FindByID(id uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	result := r.db.First(&entry, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrTimeEntryNotFound
+	}
+	return &entry, result.Error
+}
+
+func (r *gormTimeEntryRepository) FindByTask(taskID uint) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	result := r.db.Where("task_id = ?", taskID).Order("started_at DESC").Order("id DESC").Find(&entries)
+	return entries, result.Error
+}
+
+func (r *gormTimeEntryRepository) FindByUser(userID uint, from, to time.Time) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	result := r.db.
+		Preload("Task", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("user_id = ? AND started_at < ? AND (ended_at IS NULL OR ended_at > ?)", userID, to, from).
+		Order("started_at").
+		Order("id").
+		Find(&entries)
+	return entries, result.Error
+}
+
+func (r *gormTimeEntryRepository) FindRunning(userID uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	result := r.db.Where("user_id = ? AND ended_at IS NULL", userID).First(&entry)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrTimeEntryNotFound
+	}
+	return &entry, result.Error
+}
+
+func (r *gormTimeEntryRepository) StartTimer(entry *models.TimeEntry) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.TimeEntry{}).
+			Where("user_id = ? AND ended_at IS NULL", entry.UserID).
+			Updates(map[string]any{"ended_at": entry.StartedAt, "updated_at": entry.StartedAt}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Omit("Task").Create(entry).Error
+	})
+	return translateTimerError(err)
+}
+
+func (r *gormTimeEntryRepository) Update(entry *models.TimeEntry) error {
+	return translateTimerError(r.db.Omit("Task").Save(entry).Error)
+}
+
+func (r *gormTimeEntryRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.TimeEntry{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTimeEntryNotFound
+	}
+	return nil
+}
+
+// translateTimerError reports a violation of the one running timer per user
+// index, which only concurrent starts can cause, as ErrTimerRunning.
+func translateTimerError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrTimerRunning
+	}
+	return err
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	ErrMemberNotFound    = errors.New("workspace member not found")
+	ErrAlreadyMember     = errors.New("user is already a member of this workspace")
+)
+
+type WorkspaceRepository interface {
+	// Create creates the workspace along with its owner's membership.
+	Create(workspace *models.Workspace) error
+	FindByID(id uint) (*models.Workspace, error)
+	Update(workspace *models.Workspace) error
+
+	AddMember(member *models.WorkspaceMember) error
+	FindMember(workspaceID, userID uint) (*models.WorkspaceMember, error)
+	FindMembers(workspaceID uint) ([]models.WorkspaceMember, error)
+	FindMemberships(userID uint) ([]models.WorkspaceMember, error)
+	UpdateMember(member *models.WorkspaceMember) error
+	RemoveMember(workspaceID, userID uint) error
+}
+
+type gormWorkspaceRepository struct {
+	db *gorm.DB
+}
+
+func NewGormWorkspaceRepository(db *gorm.DB) WorkspaceRepository {
+	return &gormWorkspaceRepository{db: db}
+}
+
+func (r *gormWorkspaceRepository) Create(workspace *models.Workspace) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+		owner := &models.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      workspace.OwnerID,
+			Role:        models.WorkspaceRoleOwner,
+		}
+		return tx.Omit("Workspace", "User").Create(owner).Error
+	})
+}
+
+func (r *gormWorkspaceRepository) FindByID(id uint) (*models.Workspace, error) {
+	var workspace models.Workspace
+	result := r.db.First(&workspace, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrWorkspaceNotFound
+	}
+	return &workspace, result.Error
+}
+
+func (r *gormWorkspaceRepository) Update(workspace *models.Workspace) error {
+	return r.db.Save(workspace).Error
+}
+
+func (r *gormWorkspaceRepository) AddMember(member *models.WorkspaceMember) error {
+	result := r.db.Omit("Workspace", "User").Create(member)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return ErrAlreadyMember
+	}
+	return result.Error
+}
+
+func (r *gormWorkspaceRepository) FindMember(workspaceID, userID uint) (*models.WorkspaceMember, error) {
+	var member models.WorkspaceMember
+	result := r.db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrMemberNotFound
+	}
+	return &member, result.Error
+}
+
+func (r *gormWorkspaceRepository) FindMembers(workspaceID uint) ([]models.WorkspaceMember, error) {
+	var members []models.WorkspaceMember
+	result := r.db.Preload("User").Where("workspace_id = ?", workspaceID).Order("id").Find(&members)
+	return members, result.Error
+}
+
+// FindMemberships returns the user's memberships along with their
+// workspaces, ordered by workspace name.
+func (r *gormWorkspaceRepository) FindMemberships(userID uint) ([]models.WorkspaceMember, error) {
+	var members []models.WorkspaceMember
+	result := r.db.Joins("Workspace").
+		Where("workspace_members.user_id = ?", userID).
+		Order(`"Workspace".name`).
+		Find(&members)
+	return members, result.Error
+}
+
+func (r *gormWorkspaceRepository) UpdateMember(member *models.WorkspaceMember) error {
+	return r.db.Omit("Workspace", "User").Save(member).Error
+}
+
+// RemoveMember deletes the membership, switching the user back to their
+// personal space if the workspace was their active one.
+func (r *gormWorkspaceRepository) RemoveMember(workspaceID, userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Delete(&models.WorkspaceMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMemberNotFound
+		}
+		return tx.Model(&models.User{}).
+			Where("id = ? AND active_workspace_id = ?", userID, workspaceID).
+			Update("active_workspace_id", nil).Error
+	})
+}
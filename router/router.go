@@ -8,7 +8,7 @@ import (
 	"github.com/kataras/iris/v12/middleware/jwt"
 )
 
-func SetupRoutes(app *iris.Application, userHandler *handler.UserHandler, taskHandler *handler.TaskHandler, verifier *jwt.Verifier, rateLimiter iris.Handler) {
+func SetupRoutes(app *iris.Application, userHandler *handler.UserHandler, taskHandler *handler.TaskHandler, tagHandler *handler.TagHandler, listHandler *handler.ListHandler, commentHandler *handler.CommentHandler, attachmentHandler *handler.AttachmentHandler, shareHandler *handler.ShareHandler, workspaceHandler *handler.WorkspaceHandler, statusHandler *handler.StatusHandler, reminderHandler *handler.ReminderHandler, notificationHandler *handler.NotificationHandler, templateHandler *handler.TemplateHandler, timeEntryHandler *handler.TimeEntryHandler, importHandler *handler.ImportHandler, verifier *jwt.Verifier, rateLimiter iris.Handler) {
 	verifyMiddleware := verifier.Verify(func() interface{} {
 		return new(models.UserClaims)
 	})
@@ -36,16 +36,146 @@ func SetupRoutes(app *iris.Application, userHandler *handler.UserHandler, taskHa
 	{
 		userAPI.Get("/me", userHandler.GetMyDetails)
 		userAPI.Put("/me", userHandler.UpdateMyDetails)
+		userAPI.Put("/me/workspace", userHandler.SwitchWorkspace)
 		userAPI.Get("/logout", userHandler.Logout)
 	}
 	taskAPI := app.Party("/tasks")
 	taskAPI.Use(rateLimiter)
 	taskAPI.Use(verifyMiddleware)
+	taskAPI.Use(workspaceHandler.ResolveWorkspace)
 	{
 		taskAPI.Post("/", taskHandler.CreateTask)
 		taskAPI.Get("/", taskHandler.GetMyTasks)
+		taskAPI.Post("/quick", taskHandler.QuickAddTask)
+		taskAPI.Post("/batch", taskHandler.BatchTasks)
+		taskAPI.Get("/search", taskHandler.SearchTasks)
+		taskAPI.Get("/export", taskHandler.ExportTasks)
+		taskAPI.Post("/import", importHandler.ImportTasks)
+		taskAPI.Get("/trash", taskHandler.GetTrash)
 		taskAPI.Get("/{id:uint}", taskHandler.GetTask)
+		taskAPI.Get("/{id:uint}/history", taskHandler.GetTaskHistory)
 		taskAPI.Put("/{id:uint}", taskHandler.UpdateTask)
 		taskAPI.Delete("/{id:uint}", taskHandler.DeleteTask)
+		taskAPI.Post("/{id:uint}/restore", taskHandler.RestoreTask)
+		taskAPI.Delete("/{id:uint}/purge", taskHandler.PurgeTask)
+		taskAPI.Post("/{id:uint}/subtasks", taskHandler.CreateSubtask)
+		taskAPI.Get("/{id:uint}/subtasks", taskHandler.GetSubtasks)
+		taskAPI.Put("/{id:uint}/subtasks/order", taskHandler.ReorderSubtasks)
+		taskAPI.Patch("/{id:uint}/move", taskHandler.MoveTask)
+		taskAPI.Put("/{id:uint}/recurrence", taskHandler.SetRecurrence)
+		taskAPI.Delete("/{id:uint}/recurrence", taskHandler.StopRecurrence)
+		taskAPI.Put("/{id:uint}/assignee", taskHandler.AssignTask)
+		taskAPI.Delete("/{id:uint}/assignee", taskHandler.UnassignTask)
+		taskAPI.Post("/{id:uint}/dependencies", taskHandler.AddDependency)
+		taskAPI.Get("/{id:uint}/dependencies", taskHandler.GetDependencies)
+		taskAPI.Delete("/{id:uint}/dependencies/{blockerID:uint}", taskHandler.RemoveDependency)
+		taskAPI.Post("/{id:uint}/template", templateHandler.SaveTemplate)
+		taskAPI.Post("/{id:uint}/reminders", reminderHandler.CreateReminder)
+		taskAPI.Get("/{id:uint}/reminders", reminderHandler.GetReminders)
+		taskAPI.Delete("/{id:uint}/reminders/{reminderID:uint}", reminderHandler.DeleteReminder)
+		taskAPI.Post("/{id:uint}/timer/start", timeEntryHandler.StartTimer)
+		taskAPI.Post("/{id:uint}/timer/stop", timeEntryHandler.StopTimer)
+		taskAPI.Post("/{id:uint}/time-entries", timeEntryHandler.CreateTimeEntry)
+		taskAPI.Get("/{id:uint}/time-entries", timeEntryHandler.GetTimeEntries)
+		taskAPI.Post("/{id:uint}/comments", commentHandler.CreateComment)
+		taskAPI.Get("/{id:uint}/comments", commentHandler.GetComments)
+		taskAPI.Put("/{id:uint}/comments/{commentID:uint}", commentHandler.UpdateComment)
+		taskAPI.Delete("/{id:uint}/comments/{commentID:uint}", commentHandler.DeleteComment)
+		taskAPI.Post("/{id:uint}/attachments", attachmentHandler.UploadAttachment)
+		taskAPI.Get("/{id:uint}/attachments", attachmentHandler.GetAttachments)
+		taskAPI.Get("/{id:uint}/attachments/{attachmentID:uint}", attachmentHandler.DownloadAttachment)
+		taskAPI.Delete("/{id:uint}/attachments/{attachmentID:uint}", attachmentHandler.DeleteAttachment)
+		taskAPI.Post("/{id:uint}/shares", shareHandler.ShareTask)
+		taskAPI.Get("/{id:uint}/shares", shareHandler.GetTaskShares)
+	}
+	tagAPI := app.Party("/tags")
+	tagAPI.Use(rateLimiter)
+	tagAPI.Use(verifyMiddleware)
+	{
+		tagAPI.Post("/", tagHandler.CreateTag)
+		tagAPI.Get("/", tagHandler.GetMyTags)
+		tagAPI.Put("/{id:uint}", tagHandler.UpdateTag)
+		tagAPI.Delete("/{id:uint}", tagHandler.DeleteTag)
+	}
+	listAPI := app.Party("/lists")
+	listAPI.Use(rateLimiter)
+	listAPI.Use(verifyMiddleware)
+	listAPI.Use(workspaceHandler.ResolveWorkspace)
+	{
+		listAPI.Post("/", listHandler.CreateList)
+		listAPI.Get("/", listHandler.GetMyLists)
+		listAPI.Get("/{id:uint}", listHandler.GetList)
+		listAPI.Put("/{id:uint}", listHandler.UpdateList)
+		listAPI.Delete("/{id:uint}", listHandler.DeleteList)
+		listAPI.Post("/{id:uint}/shares", shareHandler.ShareList)
+		listAPI.Get("/{id:uint}/shares", shareHandler.GetListShares)
+		listAPI.Get("/{id:uint}/statuses", statusHandler.GetStatuses)
+		listAPI.Post("/{id:uint}/statuses", statusHandler.CreateStatus)
+		listAPI.Put("/{id:uint}/statuses/{statusID:uint}", statusHandler.UpdateStatus)
+		listAPI.Delete("/{id:uint}/statuses/{statusID:uint}", statusHandler.DeleteStatus)
+		listAPI.Get("/{id:uint}/board", statusHandler.GetBoard)
+	}
+	templateAPI := app.Party("/templates")
+	templateAPI.Use(rateLimiter)
+	templateAPI.Use(verifyMiddleware)
+	templateAPI.Use(workspaceHandler.ResolveWorkspace)
+	{
+		templateAPI.Get("/", templateHandler.GetTemplates)
+		templateAPI.Get("/{id:uint}", templateHandler.GetTemplate)
+		templateAPI.Delete("/{id:uint}", templateHandler.DeleteTemplate)
+		templateAPI.Post("/{id:uint}/instantiate", templateHandler.InstantiateTemplate)
+	}
+	workspaceAPI := app.Party("/workspaces")
+	workspaceAPI.Use(rateLimiter)
+	workspaceAPI.Use(verifyMiddleware)
+	{
+		workspaceAPI.Post("/", workspaceHandler.CreateWorkspace)
+		workspaceAPI.Get("/", workspaceHandler.GetMyWorkspaces)
+		workspaceAPI.Get("/{id:uint}", workspaceHandler.GetWorkspace)
+		workspaceAPI.Put("/{id:uint}", workspaceHandler.UpdateWorkspace)
+		workspaceAPI.Get("/{id:uint}/members", workspaceHandler.GetMembers)
+		workspaceAPI.Put("/{id:uint}/members/{userID:uint}", workspaceHandler.UpdateMember)
+		workspaceAPI.Delete("/{id:uint}/members/{userID:uint}", workspaceHandler.RemoveMember)
+		workspaceAPI.Post("/{id:uint}/invitations", workspaceHandler.InviteMember)
+		workspaceAPI.Get("/{id:uint}/invitations", workspaceHandler.GetInvitations)
+		workspaceAPI.Delete("/{id:uint}/invitations/{invitationID:uint}", workspaceHandler.RevokeInvitation)
+	}
+	invitationAPI := app.Party("/invitations")
+	invitationAPI.Use(rateLimiter)
+	invitationAPI.Use(verifyMiddleware)
+	{
+		invitationAPI.Get("/", workspaceHandler.GetMyInvitations)
+		invitationAPI.Post("/{token:string}/accept", workspaceHandler.AcceptInvitation)
+		invitationAPI.Post("/{token:string}/decline", workspaceHandler.DeclineInvitation)
+	}
+	shareAPI := app.Party("/shares")
+	shareAPI.Use(rateLimiter)
+	shareAPI.Use(verifyMiddleware)
+	{
+		shareAPI.Put("/{id:uint}", shareHandler.UpdateShare)
+		shareAPI.Delete("/{id:uint}", shareHandler.DeleteShare)
+	}
+	timeEntryAPI := app.Party("/time-entries")
+	timeEntryAPI.Use(rateLimiter)
+	timeEntryAPI.Use(verifyMiddleware)
+	{
+		timeEntryAPI.Get("/running", timeEntryHandler.GetRunningTimer)
+		timeEntryAPI.Get("/report", timeEntryHandler.GetTimeReport)
+		timeEntryAPI.Put("/{id:uint}", timeEntryHandler.UpdateTimeEntry)
+		timeEntryAPI.Delete("/{id:uint}", timeEntryHandler.DeleteTimeEntry)
+	}
+	notificationAPI := app.Party("/notifications")
+	notificationAPI.Use(rateLimiter)
+	notificationAPI.Use(verifyMiddleware)
+	{
+		notificationAPI.Get("/", notificationHandler.GetNotifications)
+		notificationAPI.Post("/read", notificationHandler.MarkAllNotificationsRead)
+		notificationAPI.Post("/{id:uint}/read", notificationHandler.MarkNotificationRead)
+	}
+	sharedAPI := app.Party("/shared")
+	sharedAPI.Use(rateLimiter)
+	sharedAPI.Use(verifyMiddleware)
+	{
+		sharedAPI.Get("/", shareHandler.GetSharedWithMe)
 	}
 }
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+// AccessLevel is what a user may do with a task or list, each level allowing
+// everything the ones below it do.
+type AccessLevel int
+
+const (
+	AccessNone AccessLevel = iota
+	// AccessView allows reading the item and everything in it.
+	AccessView
+	// AccessAssignee also allows completing or reopening a task assigned to
+	// the user.
+	AccessAssignee
+	// AccessEdit also allows changing it and adding to it.
+	AccessEdit
+	// AccessOwner also allows deleting and sharing it.
+	AccessOwner
+)
+
+// workspaceRoleAccess is the access a workspace role grants to every list and
+// task in the workspace.
+func workspaceRoleAccess(role models.WorkspaceRole) AccessLevel {
+	switch role {
+	case models.WorkspaceRoleOwner, models.WorkspaceRoleAdmin:
+		return AccessOwner
+	case models.WorkspaceRoleMember:
+		return AccessEdit
+	case models.WorkspaceRoleGuest:
+		return AccessView
+	}
+	return AccessNone
+}
+
+func roleAccess(role models.ShareRole) AccessLevel {
+	switch role {
+	case models.ShareEditor:
+		return AccessEdit
+	case models.ShareViewer:
+		return AccessView
+	}
+	return AccessNone
+}
+
+// accessPolicy decides what users may do with tasks and lists they don't own.
+// A task is covered by the shares on itself, on every task above it and on
+// its list; the owner of a list may edit any task in it, a task's assignee
+// may complete it, and members of a workspace get the access their role
+// grants to everything in it. The best of these applies.
+type accessPolicy struct {
+	taskRepo      repository.TaskRepository
+	listRepo      repository.ListRepository
+	shareRepo     repository.ShareRepository
+	workspaceRepo repository.WorkspaceRepository
+}
+
+// newAccessPolicy is how every service gets its policy, so none is ever left
+// with a repository its checks need unset.
+func newAccessPolicy(taskRepo repository.TaskRepository, listRepo repository.ListRepository, shareRepo repository.ShareRepository, workspaceRepo repository.WorkspaceRepository) *accessPolicy {
+	return &accessPolicy{
+		taskRepo:      taskRepo,
+		listRepo:      listRepo,
+		shareRepo:     shareRepo,
+		workspaceRepo: workspaceRepo,
+	}
+}
+
+func (p *accessPolicy) taskAccess(task *models.Task, userID uint) (AccessLevel, error) {
+	if task.UserID == userID {
+		return AccessOwner, nil
+	}
+
+	level, err := p.workspaceAccess(task.WorkspaceID, userID)
+	if err != nil {
+		return AccessNone, err
+	}
+	if task.AssigneeID != nil && *task.AssigneeID == userID {
+		level = max(level, AccessAssignee)
+	}
+	if task.ListID != nil {
+		list, err := p.listRepo.FindByID(*task.ListID)
+		if err != nil && !errors.Is(err, repository.ErrListNotFound) {
+			return AccessNone, err
+		}
+		if err == nil && list.UserID == userID {
+			level = max(level, AccessEdit)
+		}
+	}
+
+	ancestorIDs, err := p.taskRepo.FindAncestorIDs(task.ID)
+	if err != nil {
+		return AccessNone, err
+	}
+	roles, err := p.shareRepo.FindRoles(userID, append(ancestorIDs, task.ID), task.ListID)
+	if err != nil {
+		return AccessNone, err
+	}
+	return bestAccess(level, roles), nil
+}
+
+func (p *accessPolicy) listAccess(list *models.List, userID uint) (AccessLevel, error) {
+	if list.UserID == userID {
+		return AccessOwner, nil
+	}
+
+	level, err := p.workspaceAccess(list.WorkspaceID, userID)
+	if err != nil {
+		return AccessNone, err
+	}
+	roles, err := p.shareRepo.FindRoles(userID, nil, &list.ID)
+	if err != nil {
+		return AccessNone, err
+	}
+	return bestAccess(level, roles), nil
+}
+
+// workspaceAccess is the access the user's role in the workspace grants, if
+// there is a workspace and they are a member of it.
+func (p *accessPolicy) workspaceAccess(workspaceID *uint, userID uint) (AccessLevel, error) {
+	if workspaceID == nil {
+		return AccessNone, nil
+	}
+	member, err := p.workspaceRepo.FindMember(*workspaceID, userID)
+	if errors.Is(err, repository.ErrMemberNotFound) {
+		return AccessNone, nil
+	}
+	if err != nil {
+		return AccessNone, err
+	}
+	return workspaceRoleAccess(member.Role), nil
+}
+
+func bestAccess(level AccessLevel, roles []models.ShareRole) AccessLevel {
+	for _, role := range roles {
+		level = max(level, roleAccess(role))
+	}
+	return level
+}
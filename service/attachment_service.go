@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/storage"
+)
+
+var (
+	ErrAttachmentAccessDenied   = errors.New("access to the requested attachment is denied")
+	ErrAttachmentTooLarge       = errors.New("attachment is too large")
+	ErrAttachmentTypeNotAllowed = errors.New("attachment type is not allowed")
+)
+
+const DefaultAttachmentMaxSizeMB = 10
+
+// allowedAttachmentTypes lists the MIME types files may be uploaded as.
+// Entries ending in "/" or "." allow a whole family of types.
+var allowedAttachmentTypes = []string{
+	"image/",
+	"text/plain",
+	"text/markdown",
+	"text/csv",
+	"application/pdf",
+	"application/json",
+	"application/zip",
+	"application/msword",
+	"application/vnd.openxmlformats-officedocument.",
+	"application/vnd.oasis.opendocument.",
+}
+
+// AttachmentService manages the files uploaded to tasks. Anyone who can view a
+// task can download its attachments and anyone who can edit it can upload
+// them; only the uploader or the task's owner may delete one.
+type AttachmentService interface {
+	MaxSize() int64
+	UploadAttachment(taskID, userID uint, upload AttachmentUpload) (*models.Attachment, error)
+	GetAttachments(taskID, userID uint) ([]models.Attachment, error)
+	OpenAttachment(taskID, attachmentID, userID uint) (*models.Attachment, io.ReadCloser, error)
+	DeleteAttachment(taskID, attachmentID, userID uint) error
+}
+
+// AttachmentUpload is a file as received from the client. ContentType is the
+// type the client declared, if any, which is only a hint.
+type AttachmentUpload struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Body        io.ReadSeeker
+}
+
+type attachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	taskService    TaskService
+	blobStore      storage.BlobStore
+	maxSize        int64
+}
+
+func NewAttachmentService(attachmentRepo repository.AttachmentRepository, taskService TaskService, blobStore storage.BlobStore, maxSize int64) AttachmentService {
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		taskService:    taskService,
+		blobStore:      blobStore,
+		maxSize:        maxSize,
+	}
+}
+
+func (s *attachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+func (s *attachmentService) UploadAttachment(taskID, userID uint, upload AttachmentUpload) (*models.Attachment, error) {
+	task, err := s.taskService.AuthorizeTask(taskID, userID, AccessEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.Size > s.maxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+	contentType, err := attachmentContentType(upload)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newStorageKey(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if err := s.blobStore.Put(ctx, key, upload.Body, upload.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		TaskID:      task.ID,
+		UploaderID:  userID,
+		FileName:    cleanFileName(upload.FileName),
+		ContentType: contentType,
+		Size:        upload.Size,
+		StorageKey:  key,
+	}
+	if err := s.attachmentRepo.Create(attachment); err != nil {
+		return nil, errors.Join(err, s.blobStore.Delete(ctx, key))
+	}
+	return attachment, nil
+}
+
+func (s *attachmentService) GetAttachments(taskID, userID uint) ([]models.Attachment, error) {
+	task, err := s.taskService.GetTask(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.attachmentRepo.FindByTask(task.ID)
+}
+
+// OpenAttachment returns the attachment along with a reader over its contents,
+// which the caller must close.
+func (s *attachmentService) OpenAttachment(taskID, attachmentID, userID uint) (*models.Attachment, io.ReadCloser, error) {
+	_, attachment, err := s.getAttachment(taskID, attachmentID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := s.blobStore.Get(context.Background(), attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, body, nil
+}
+
+func (s *attachmentService) DeleteAttachment(taskID, attachmentID, userID uint) error {
+	task, attachment, err := s.getAttachment(taskID, attachmentID, userID)
+	if err != nil {
+		return err
+	}
+	if attachment.UploaderID != userID && task.UserID != userID {
+		return ErrAttachmentAccessDenied
+	}
+
+	if err := s.attachmentRepo.Delete(attachment.ID); err != nil {
+		return err
+	}
+	return s.blobStore.Delete(context.Background(), attachment.StorageKey)
+}
+
+// getAttachment loads an attachment of the task, checking that the user can
+// access the task first.
+func (s *attachmentService) getAttachment(taskID, attachmentID, userID uint) (*models.Task, *models.Attachment, error) {
+	task, err := s.taskService.GetTask(taskID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.attachmentRepo.FindByID(attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment.TaskID != task.ID {
+		return nil, nil, repository.ErrAttachmentNotFound
+	}
+	return task, attachment, nil
+}
+
+// refinableAttachmentTypes lists the types a declared type may narrow a sniffed
+// one to, for formats http.DetectContentType cannot tell apart: plain text
+// formats, Office Open XML and OpenDocument files, which are zip archives, and
+// legacy Word documents, which it doesn't recognize at all.
+var refinableAttachmentTypes = map[string][]string{
+	"text/plain":               {"text/markdown", "text/csv", "application/json"},
+	"application/zip":          {"application/vnd.openxmlformats-officedocument.", "application/vnd.oasis.opendocument."},
+	"application/octet-stream": {"application/msword"},
+}
+
+// attachmentContentType settles the upload's MIME type by sniffing its
+// contents, and checks that it is allowed. The type the client declared is
+// only used to narrow down a sniffed type that fits several formats, so a
+// file can't be stored as a type it isn't. SVG images are never allowed, as
+// they can carry scripts.
+func attachmentContentType(upload AttachmentUpload) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(upload.Body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if _, err := upload.Body.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+
+	if upload.ContentType != "" {
+		declared, _, err := mime.ParseMediaType(upload.ContentType)
+		if err != nil {
+			return "", ErrAttachmentTypeNotAllowed
+		}
+		if matchesAttachmentType(declared, refinableAttachmentTypes[contentType]) {
+			contentType = declared
+		}
+	}
+
+	if contentType == "image/svg+xml" || !matchesAttachmentType(contentType, allowedAttachmentTypes) {
+		return "", ErrAttachmentTypeNotAllowed
+	}
+	return contentType, nil
+}
+
+// matchesAttachmentType reports whether the type is one of the given ones,
+// where entries ending in "/" or "." match every type they prefix.
+func matchesAttachmentType(contentType string, types []string) bool {
+	for _, t := range types {
+		if contentType == t || (strings.HasSuffix(t, "/") || strings.HasSuffix(t, ".")) && strings.HasPrefix(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// newStorageKey picks a random, unguessable key for a new blob of the task.
+func newStorageKey(taskID uint) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(random)), nil
+}
+
+// cleanFileName keeps only the base name of an uploaded file, which some
+// clients send with a path.
+func cleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
+
+// AttachmentMaxSizeFromEnv reads the largest accepted upload from
+// ATTACHMENT_MAX_SIZE_MB, defaulting to DefaultAttachmentMaxSizeMB.
+func AttachmentMaxSizeFromEnv() (int64, error) {
+	megabytes := DefaultAttachmentMaxSizeMB
+	if value := os.Getenv("ATTACHMENT_MAX_SIZE_MB"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return 0, fmt.Errorf("ATTACHMENT_MAX_SIZE_MB must be a positive number of megabytes, got %q", value)
+		}
+		megabytes = parsed
+	}
+	return int64(megabytes) << 20, nil
+}
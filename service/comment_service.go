@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+var (
+	ErrCommentAccessDenied = errors.New("access to the requested comment is denied")
+)
+
+// CommentService manages the comments on tasks. Anyone who can view a task can
+// read its comments and anyone who can edit it can post them; only a comment's
+// author may edit it, and the author or the task's owner may delete it.
+type CommentService interface {
+	CreateComment(taskID, userID uint, req models.CreateCommentRequest) (*models.Comment, error)
+	GetComments(taskID, userID uint) ([]models.Comment, error)
+	UpdateComment(taskID, commentID, userID uint, req models.UpdateCommentRequest) (*models.Comment, error)
+	DeleteComment(taskID, commentID, userID uint) error
+}
+
+type commentService struct {
+	commentRepo repository.CommentRepository
+	taskService TaskService
+}
+
+func NewCommentService(commentRepo repository.CommentRepository, taskService TaskService) CommentService {
+	return &commentService{
+		commentRepo: commentRepo,
+		taskService: taskService,
+	}
+}
+
+func (s *commentService) CreateComment(taskID, userID uint, req models.CreateCommentRequest) (*models.Comment, error) {
+	task, err := s.taskService.AuthorizeTask(taskID, userID, AccessEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		TaskID:   task.ID,
+		AuthorID: userID,
+		Body:     req.Body,
+	}
+	if err := s.commentRepo.Create(comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) GetComments(taskID, userID uint) ([]models.Comment, error) {
+	task, err := s.taskService.GetTask(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.commentRepo.FindByTask(task.ID)
+}
+
+func (s *commentService) UpdateComment(taskID, commentID, userID uint, req models.UpdateCommentRequest) (*models.Comment, error) {
+	_, comment, err := s.getComment(taskID, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, ErrCommentAccessDenied
+	}
+
+	if req.Body != comment.Body {
+		now := time.Now()
+		comment.Body = req.Body
+		comment.EditedAt = &now
+	}
+	if err := s.commentRepo.Update(comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) DeleteComment(taskID, commentID, userID uint) error {
+	task, comment, err := s.getComment(taskID, commentID, userID)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != userID && task.UserID != userID {
+		return ErrCommentAccessDenied
+	}
+	return s.commentRepo.Delete(comment.ID)
+}
+
+// getComment loads a comment on the task, checking that the user can access
+// the task first.
+func (s *commentService) getComment(taskID, commentID, userID uint) (*models.Task, *models.Comment, error) {
+	task, err := s.taskService.GetTask(taskID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	comment, err := s.commentRepo.FindByID(commentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if comment.TaskID != task.ID {
+		return nil, nil, repository.ErrCommentNotFound
+	}
+	return task, comment, nil
+}
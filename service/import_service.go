@@ -0,0 +1,277 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/RLRama/listario-backend/importer"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/utils"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// MaxImportFileSize bounds the size of an uploaded import file.
+	MaxImportFileSize = 5 << 20
+	// maxImportTasks bounds how many tasks a single import can create.
+	maxImportTasks = 2000
+)
+
+var (
+	ErrImportFormat      = errors.New("unknown import format")
+	ErrInvalidImportFile = errors.New("the file could not be read")
+	ErrImportTooLarge    = errors.New("a file can hold at most 2000 tasks")
+	ErrImportInvalid     = errors.New("some tasks in the file are invalid, so none were imported")
+)
+
+// ImportService creates tasks from files exported by Listario or other to-do
+// applications. A file is imported in full or not at all: any invalid task
+// stops the whole import.
+type ImportService interface {
+	ImportTasks(userID uint, workspaceID *uint, req TaskImport, now time.Time) (*ImportReport, error)
+}
+
+// TaskImport is a file to import. Top-level tasks go to ListID, or to the
+// inbox or no list like other new tasks. A dry run only reads and validates
+// the file, reporting what would be created.
+type TaskImport struct {
+	Format string
+	File   io.Reader
+	ListID *uint
+	DryRun bool
+}
+
+// ImportReport lists the tasks of a file in the order they are created, or
+// the problems that kept it from being imported.
+type ImportReport struct {
+	Format  string
+	DryRun  bool
+	Tasks   []ImportedTask
+	Created int
+	Errors  []importer.RowError
+}
+
+// ImportedTask is a valid task of a file. ParentRow is the row of its parent,
+// or 0 for top-level tasks.
+type ImportedTask struct {
+	importer.Task
+	ParentRow int
+}
+
+type importService struct {
+	taskService TaskService
+	userRepo    repository.UserRepository
+	validator   *utils.CustomValidator
+}
+
+func NewImportService(taskService TaskService, userRepo repository.UserRepository, validator *utils.CustomValidator) ImportService {
+	return &importService{
+		taskService: taskService,
+		userRepo:    userRepo,
+		validator:   validator,
+	}
+}
+
+// ImportTasks reads the file and, unless it is a dry run, creates its tasks
+// within one transaction. Subtasks whose parent isn't in the file become
+// top-level tasks. If any task is invalid the report comes back along with
+// ErrImportInvalid and nothing is created.
+func (s *importService) ImportTasks(userID uint, workspaceID *uint, req TaskImport, now time.Time) (*ImportReport, error) {
+	format, ok := importer.Lookup(req.Format)
+	if !ok {
+		return nil, ErrImportFormat
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := time.LoadLocation(user.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	result, err := format.Parse(req.File, importer.Options{Now: now.In(location)})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+	if len(result.Tasks) > maxImportTasks {
+		return nil, ErrImportTooLarge
+	}
+
+	report := &ImportReport{Format: req.Format, DryRun: req.DryRun, Errors: result.Errors}
+	valid := make([]importer.Task, 0, len(result.Tasks))
+	for _, task := range result.Tasks {
+		if errs := s.validateTask(task); len(errs) > 0 {
+			report.Errors = append(report.Errors, errs...)
+			continue
+		}
+		valid = append(valid, task)
+	}
+
+	trees, tasks, errs := buildImportTrees(valid, req.ListID)
+	report.Tasks = tasks
+	report.Errors = append(report.Errors, errs...)
+
+	if len(report.Errors) > 0 {
+		sortRowErrors(report.Errors)
+		if req.DryRun {
+			return report, nil
+		}
+		return report, ErrImportInvalid
+	}
+	if req.DryRun || len(trees) == 0 {
+		return report, nil
+	}
+
+	if _, err := s.taskService.CreateTaskTrees(userID, workspaceID, trees); err != nil {
+		return nil, err
+	}
+	report.Created = len(tasks)
+	return report, nil
+}
+
+// validateTask checks the task against its validation tags and the rules
+// tasks are created with, returning a row error for each problem.
+func (s *importService) validateTask(task importer.Task) []importer.RowError {
+	var errs []importer.RowError
+	if err := s.validator.Struct(task); err != nil {
+		var fieldErrs validator.ValidationErrors
+		if !errors.As(err, &fieldErrs) {
+			return []importer.RowError{{Row: task.Row, Message: err.Error()}}
+		}
+		for _, fieldErr := range fieldErrs {
+			errs = append(errs, importer.RowError{
+				Row:     task.Row,
+				Field:   importFieldName(fieldErr),
+				Message: describeFieldError(fieldErr),
+			})
+		}
+	}
+
+	if task.RecurrenceRule != "" {
+		if task.DueDate == nil {
+			errs = append(errs, importer.RowError{Row: task.Row, Field: "recurrence_rule", Message: ErrRecurrenceNeedsDueDate.Error()})
+		} else if _, err := normalizeRecurrenceRule(task.RecurrenceRule); err != nil {
+			errs = append(errs, importer.RowError{Row: task.Row, Field: "recurrence_rule", Message: "is not a valid RFC 5545 RRULE"})
+		}
+	}
+	return errs
+}
+
+// importFieldName names the field of a validation error as it is written in
+// JSON, keeping any index, as in "tags[2]".
+func importFieldName(fieldErr validator.FieldError) string {
+	name := fieldErr.StructField()
+	index := ""
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name, index = name[:i], name[i:]
+	}
+	if field, ok := reflect.TypeOf(importer.Task{}).FieldByName(name); ok {
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag != "" && tag != "-" {
+			name = tag
+		}
+	}
+	return name + index
+}
+
+func describeFieldError(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s characters long", fieldErr.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters long", fieldErr.Param())
+	case "priority":
+		priorities := make([]string, len(models.TaskPriorities))
+		for i, priority := range models.TaskPriorities {
+			priorities[i] = string(priority)
+		}
+		return "must be one of " + strings.Join(priorities, ", ")
+	case "timezone":
+		return "must be an IANA time zone such as Europe/Madrid"
+	default:
+		return fmt.Sprintf("failed the %q check", fieldErr.Tag())
+	}
+}
+
+// buildImportTrees nests the tasks under their parents, keeping their order
+// among siblings, and lists them in the order they will be created. Tasks
+// that reuse another's reference, or that are their own ancestors, are
+// reported as errors.
+func buildImportTrees(tasks []importer.Task, listID *uint) ([]TaskTree, []ImportedTask, []importer.RowError) {
+	var errs []importer.RowError
+	rows := make(map[string]int, len(tasks))
+	for _, task := range tasks {
+		if task.Ref == "" {
+			continue
+		}
+		if row, ok := rows[task.Ref]; ok {
+			errs = append(errs, importer.RowError{Row: task.Row, Field: "id", Message: fmt.Sprintf("is already used by row %d", row)})
+			continue
+		}
+		rows[task.Ref] = task.Row
+	}
+
+	var roots []importer.Task
+	children := make(map[string][]importer.Task)
+	for _, task := range tasks {
+		if _, ok := rows[task.ParentRef]; task.ParentRef != "" && ok {
+			children[task.ParentRef] = append(children[task.ParentRef], task)
+		} else {
+			roots = append(roots, task)
+		}
+	}
+
+	var created []ImportedTask
+	reached := make(map[int]bool, len(tasks))
+	var build func(task importer.Task, parentRow int) TaskTree
+	build = func(task importer.Task, parentRow int) TaskTree {
+		reached[task.Row] = true
+		created = append(created, ImportedTask{Task: task, ParentRow: parentRow})
+		tree := TaskTree{
+			Task: models.CreateTaskRequest{
+				Title:          task.Title,
+				Content:        task.Content,
+				Priority:       task.Priority,
+				DueDate:        task.DueDate,
+				AllDay:         task.AllDay,
+				TimeZone:       task.TimeZone,
+				RecurrenceRule: task.RecurrenceRule,
+			},
+			TagNames:  task.Tags,
+			Completed: task.Completed,
+		}
+		if task.Ref != "" && rows[task.Ref] == task.Row {
+			for _, child := range children[task.Ref] {
+				tree.Subtasks = append(tree.Subtasks, build(child, task.Row))
+			}
+		}
+		return tree
+	}
+
+	trees := make([]TaskTree, len(roots))
+	for i, root := range roots {
+		trees[i] = build(root, 0)
+		trees[i].Task.ListID = listID
+	}
+
+	// Whatever can't be reached from a top-level task hangs off a cycle.
+	for _, task := range tasks {
+		if !reached[task.Row] && rows[task.Ref] == task.Row {
+			errs = append(errs, importer.RowError{Row: task.Row, Field: "parent_id", Message: "makes the task its own ancestor"})
+		}
+	}
+	return trees, created, errs
+}
+
+func sortRowErrors(errs []importer.RowError) {
+	slices.SortStableFunc(errs, func(a, b importer.RowError) int { return a.Row - b.Row })
+}
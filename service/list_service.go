@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+var (
+	ErrListAccessDenied = errors.New("access to the requested list is denied")
+	ErrInboxImmutable   = errors.New("the inbox list cannot be archived or deleted")
+)
+
+type ListService interface {
+	CreateList(userID uint, workspaceID *uint, req models.CreateListRequest) (*models.List, error)
+	GetLists(userID uint, workspaceID *uint, includeArchived bool) ([]models.List, error)
+	GetList(listID, userID uint) (*models.List, error)
+	AuthorizeList(listID, userID uint, level AccessLevel) (*models.List, error)
+	UpdateList(listID, userID uint, req models.UpdateListRequest) (*models.List, error)
+	DeleteList(listID, userID uint) error
+}
+
+type listService struct {
+	listRepo repository.ListRepository
+	access   *accessPolicy
+}
+
+func NewListService(repo repository.ListRepository, taskRepo repository.TaskRepository, shareRepo repository.ShareRepository, workspaceRepo repository.WorkspaceRepository) ListService {
+	return &listService{
+		listRepo: repo,
+		access:   newAccessPolicy(taskRepo, repo, shareRepo, workspaceRepo),
+	}
+}
+
+// CreateList creates a list in the workspace, or in the user's personal space
+// when workspaceID is nil.
+func (s *listService) CreateList(userID uint, workspaceID *uint, req models.CreateListRequest) (*models.List, error) {
+	level, err := s.access.workspaceAccess(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceID != nil && level < AccessEdit {
+		return nil, ErrWorkspaceAccessDenied
+	}
+
+	list := &models.List{
+		Name:        req.Name,
+		Color:       req.Color,
+		Icon:        req.Icon,
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+	}
+
+	if err := s.listRepo.Create(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetLists returns the lists in the workspace, or in the user's personal space
+// when workspaceID is nil. Callers check that the user belongs to the
+// workspace.
+func (s *listService) GetLists(userID uint, workspaceID *uint, includeArchived bool) ([]models.List, error) {
+	if workspaceID != nil {
+		return s.listRepo.FindByWorkspace(*workspaceID, includeArchived)
+	}
+	if _, err := ensureInbox(s.listRepo, userID); err != nil {
+		return nil, err
+	}
+	return s.listRepo.FindByUser(userID, includeArchived)
+}
+
+func (s *listService) GetList(listID, userID uint) (*models.List, error) {
+	return s.AuthorizeList(listID, userID, AccessView)
+}
+
+// AuthorizeList loads the list if the user has at least the given access to
+// it.
+func (s *listService) AuthorizeList(listID, userID uint, level AccessLevel) (*models.List, error) {
+	list, err := s.listRepo.FindByID(listID)
+	if err != nil {
+		return nil, err
+	}
+
+	granted, err := s.access.listAccess(list, userID)
+	if err != nil {
+		return nil, err
+	}
+	if granted < level {
+		return nil, ErrListAccessDenied
+	}
+	return list, nil
+}
+
+func (s *listService) UpdateList(listID, userID uint, req models.UpdateListRequest) (*models.List, error) {
+	list, err := s.AuthorizeList(listID, userID, AccessOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		list.Name = req.Name
+	}
+	if req.Color != "" {
+		list.Color = req.Color
+	}
+	if req.Icon != "" {
+		list.Icon = req.Icon
+	}
+	if req.Archived != nil {
+		if list.IsInbox && *req.Archived {
+			return nil, ErrInboxImmutable
+		}
+		list.Archived = *req.Archived
+	}
+
+	if err := s.listRepo.Update(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// DeleteList deletes the list and its shares. In a personal space the owner's
+// tasks in the list move to their inbox; any other tasks, and every task of a
+// workspace list, are left without a list.
+func (s *listService) DeleteList(listID, userID uint) error {
+	list, err := s.AuthorizeList(listID, userID, AccessOwner)
+	if err != nil {
+		return err
+	}
+	if list.IsInbox {
+		return ErrInboxImmutable
+	}
+	if list.WorkspaceID != nil {
+		return s.listRepo.Delete(list.ID, nil)
+	}
+
+	inbox, err := ensureInbox(s.listRepo, list.UserID)
+	if err != nil {
+		return err
+	}
+	return s.listRepo.Delete(list.ID, &inbox.ID)
+}
+
+// ensureInbox returns the user's inbox list, creating it if the user doesn't
+// have one yet.
+func ensureInbox(listRepo repository.ListRepository, userID uint) (*models.List, error) {
+	inbox, err := listRepo.FindInbox(userID)
+	if err == nil {
+		return inbox, nil
+	}
+	if !errors.Is(err, repository.ErrListNotFound) {
+		return nil, err
+	}
+
+	inbox = &models.List{
+		Name:    models.InboxListName,
+		IsInbox: true,
+		UserID:  userID,
+	}
+	if err := listRepo.Create(inbox); err != nil {
+		// A concurrent request may have just created it.
+		if existing, findErr := listRepo.FindInbox(userID); findErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return inbox, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+// notificationLimit caps how many notifications are returned at once.
+const notificationLimit = 100
+
+// NotificationService manages the in-app notifications of users, who can
+// only see and read their own.
+type NotificationService interface {
+	GetNotifications(userID uint, unreadOnly bool) ([]models.Notification, error)
+	MarkRead(notificationID, userID uint) error
+	MarkAllRead(userID uint) error
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{notificationRepo: notificationRepo}
+}
+
+func (s *notificationService) GetNotifications(userID uint, unreadOnly bool) ([]models.Notification, error) {
+	return s.notificationRepo.FindByUser(userID, unreadOnly, notificationLimit)
+}
+
+func (s *notificationService) MarkRead(notificationID, userID uint) error {
+	return s.notificationRepo.MarkRead(notificationID, userID, time.Now())
+}
+
+func (s *notificationService) MarkAllRead(userID uint) error {
+	return s.notificationRepo.MarkAllRead(userID, time.Now())
+}
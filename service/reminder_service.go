@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/notify"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+var (
+	ErrReminderChannelUnavailable = errors.New("reminder channel is not available")
+	ErrReminderNeedsDueDate       = errors.New("reminders relative to the due date need a task with a due date")
+	ErrReminderInPast             = errors.New("reminder time is in the past")
+)
+
+const (
+	// reminderBatchSize is how many due reminders are delivered per claim.
+	reminderBatchSize = 20
+	// reminderDeliveryTimeout bounds a single delivery attempt.
+	reminderDeliveryTimeout = 30 * time.Second
+	// reminderLease holds back claimed reminders from other deliverers for
+	// long enough to deliver a whole batch. Reminders claimed by a process
+	// that stopped before delivering them are retried once it runs out.
+	reminderLease = 2 * reminderBatchSize * reminderDeliveryTimeout
+	// maxReminderAttempts is how many times delivery is tried before the
+	// reminder is marked as failed, waiting twice as long after each failure.
+	maxReminderAttempts = 5
+	reminderRetryDelay  = time.Minute
+)
+
+// ReminderService manages the reminders users set on tasks and delivers them
+// once due. Anyone who can see a task can set reminders on it, which only
+// they can see and remove.
+type ReminderService interface {
+	CreateReminder(taskID, userID uint, req models.CreateReminderRequest) (*models.Reminder, error)
+	GetReminders(taskID, userID uint) ([]models.Reminder, error)
+	DeleteReminder(taskID, reminderID, userID uint) error
+
+	// DeliverDueReminders delivers the reminders due at now, returning how
+	// many were sent.
+	DeliverDueReminders(ctx context.Context, now time.Time) (int, error)
+	// NextReminderAt returns when the next pending reminder comes due, or nil
+	// if there is none.
+	NextReminderAt() (*time.Time, error)
+	// Scheduled receives whenever a reminder is created, so a waiting
+	// deliverer can reconsider when the next one is due.
+	Scheduled() <-chan struct{}
+}
+
+type reminderService struct {
+	reminderRepo repository.ReminderRepository
+	taskService  TaskService
+	notifiers    map[models.ReminderChannel]notify.Notifier
+	scheduled    chan struct{}
+}
+
+func NewReminderService(reminderRepo repository.ReminderRepository, taskService TaskService, notifiers map[models.ReminderChannel]notify.Notifier) ReminderService {
+	return &reminderService{
+		reminderRepo: reminderRepo,
+		taskService:  taskService,
+		notifiers:    notifiers,
+		scheduled:    make(chan struct{}, 1),
+	}
+}
+
+func (s *reminderService) CreateReminder(taskID, userID uint, req models.CreateReminderRequest) (*models.Reminder, error) {
+	task, err := s.taskService.AuthorizeTask(taskID, userID, AccessView)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.notifiers[req.Channel]; !ok {
+		return nil, ErrReminderChannelUnavailable
+	}
+	if req.RemindAt != nil && !req.RemindAt.After(time.Now()) {
+		return nil, ErrReminderInPast
+	}
+	if req.MinutesBefore != nil && task.DueDate == nil {
+		return nil, ErrReminderNeedsDueDate
+	}
+
+	reminder := &models.Reminder{
+		TaskID:        task.ID,
+		UserID:        userID,
+		RemindAt:      req.RemindAt,
+		MinutesBefore: req.MinutesBefore,
+		Channel:       req.Channel,
+		Status:        models.ReminderPending,
+	}
+	if req.Channel == models.ReminderWebhook {
+		reminder.WebhookURL = req.WebhookURL
+	}
+	if err := s.reminderRepo.Create(reminder); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.scheduled <- struct{}{}:
+	default:
+	}
+	return reminder, nil
+}
+
+func (s *reminderService) GetReminders(taskID, userID uint) ([]models.Reminder, error) {
+	task, err := s.taskService.GetTask(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.reminderRepo.FindByTask(task.ID, userID)
+}
+
+// DeleteReminder removes one of the user's reminders, reporting anyone else's
+// as not found.
+func (s *reminderService) DeleteReminder(taskID, reminderID, userID uint) error {
+	if _, err := s.taskService.GetTask(taskID, userID); err != nil {
+		return err
+	}
+	reminder, err := s.reminderRepo.FindByID(reminderID)
+	if err != nil {
+		return err
+	}
+	if reminder.TaskID != taskID || reminder.UserID != userID {
+		return repository.ErrReminderNotFound
+	}
+	return s.reminderRepo.Delete(reminder.ID)
+}
+
+func (s *reminderService) DeliverDueReminders(ctx context.Context, now time.Time) (int, error) {
+	if _, err := s.reminderRepo.SkipInactive(now); err != nil {
+		return 0, err
+	}
+	reminders, err := s.reminderRepo.ClaimDue(now, now.Add(reminderLease), reminderBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range reminders {
+		// Reminders left undelivered here are retried once their lease runs
+		// out, possibly by another process.
+		if ctx.Err() != nil {
+			break
+		}
+		reminder := &reminders[i]
+
+		err := s.deliver(ctx, reminder)
+		switch {
+		case err == nil:
+			deliveredAt := time.Now()
+			reminder.Status = models.ReminderSent
+			reminder.SentAt = &deliveredAt
+			reminder.NextAttemptAt = nil
+			reminder.LastError = ""
+			sent++
+		case errors.Is(err, ErrTaskAccessDenied):
+			reminder.Status = models.ReminderSkipped
+			reminder.NextAttemptAt = nil
+		case errors.Is(err, ErrReminderChannelUnavailable), errors.Is(err, notify.ErrNoRecipient),
+			reminder.Attempts >= maxReminderAttempts:
+			reminder.Status = models.ReminderFailed
+			reminder.NextAttemptAt = nil
+			reminder.LastError = err.Error()
+		default:
+			retryAt := now.Add(reminderRetryDelay << (reminder.Attempts - 1))
+			reminder.NextAttemptAt = &retryAt
+			reminder.LastError = err.Error()
+		}
+		if err != nil {
+			logger.Warn().Err(err).Uint("reminderID", reminder.ID).Int("attempts", reminder.Attempts).Msg("Failed to deliver reminder")
+		}
+
+		if err := s.reminderRepo.Update(reminder); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// deliver sends the reminder over its channel, as long as its user can still
+// see the task.
+func (s *reminderService) deliver(ctx context.Context, reminder *models.Reminder) error {
+	if _, err := s.taskService.AuthorizeTask(reminder.TaskID, reminder.UserID, AccessView); err != nil {
+		return err
+	}
+	notifier, ok := s.notifiers[reminder.Channel]
+	if !ok {
+		return ErrReminderChannelUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reminderDeliveryTimeout)
+	defer cancel()
+	return notifier.Notify(ctx, reminderMessage(reminder))
+}
+
+func reminderMessage(reminder *models.Reminder) notify.Message {
+	task := reminder.Task
+	body := task.Title
+	if task.DueDate != nil {
+		body += "\n\nDue " + formatDueDate(&task)
+	}
+	if task.Content != "" {
+		body += "\n\n" + task.Content
+	}
+
+	return notify.Message{
+		UserID:     reminder.UserID,
+		Email:      reminder.User.Email,
+		WebhookURL: reminder.WebhookURL,
+		TaskID:     task.ID,
+		ReminderID: reminder.ID,
+		Subject:    fmt.Sprintf("Reminder: %s", task.Title),
+		Body:       body,
+		DueDate:    task.DueDate,
+	}
+}
+
+// formatDueDate renders the task's due date in its own time zone.
+func formatDueDate(task *models.Task) string {
+	if task.AllDay {
+		return task.DueDate.UTC().Format("Monday, January 2, 2006")
+	}
+	location, err := time.LoadLocation(task.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	return task.DueDate.In(location).Format("Monday, January 2, 2006 at 15:04 MST")
+}
+
+func (s *reminderService) NextReminderAt() (*time.Time, error) {
+	return s.reminderRepo.NextDueAt()
+}
+
+func (s *reminderService) Scheduled() <-chan struct{} {
+	return s.scheduled
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+var (
+	ErrShareAccessDenied   = errors.New("access to the requested share is denied")
+	ErrCannotShareWithSelf = errors.New("cannot share with the owner")
+)
+
+// ShareService manages who tasks and lists are shared with. Only the owner of
+// a task or list may share it or change its shares; anyone who can view it
+// may see who it is shared with, and a user may remove a share given to them.
+type ShareService interface {
+	ShareTask(taskID, userID uint, req models.CreateShareRequest) (*models.Share, error)
+	ShareList(listID, userID uint, req models.CreateShareRequest) (*models.Share, error)
+	GetTaskShares(taskID, userID uint) ([]models.Share, error)
+	GetListShares(listID, userID uint) ([]models.Share, error)
+	UpdateShare(shareID, userID uint, req models.UpdateShareRequest) (*models.Share, error)
+	DeleteShare(shareID, userID uint) error
+	GetSharedWithMe(userID uint) (*SharedWithMe, error)
+}
+
+// SharedWithMe is everything other users have shared with a user, each item
+// along with the share that grants it.
+type SharedWithMe struct {
+	Tasks []SharedTask
+	Lists []SharedList
+}
+
+type SharedTask struct {
+	Share models.Share
+	Task  models.Task
+}
+
+type SharedList struct {
+	Share models.Share
+	List  models.List
+}
+
+type shareService struct {
+	shareRepo   repository.ShareRepository
+	userRepo    repository.UserRepository
+	taskRepo    repository.TaskRepository
+	listRepo    repository.ListRepository
+	taskService TaskService
+	listService ListService
+}
+
+func NewShareService(shareRepo repository.ShareRepository, userRepo repository.UserRepository, taskRepo repository.TaskRepository, listRepo repository.ListRepository, taskService TaskService, listService ListService) ShareService {
+	return &shareService{
+		shareRepo:   shareRepo,
+		userRepo:    userRepo,
+		taskRepo:    taskRepo,
+		listRepo:    listRepo,
+		taskService: taskService,
+		listService: listService,
+	}
+}
+
+func (s *shareService) ShareTask(taskID, userID uint, req models.CreateShareRequest) (*models.Share, error) {
+	task, err := s.taskService.AuthorizeTask(taskID, userID, AccessOwner)
+	if err != nil {
+		return nil, err
+	}
+	return s.createShare(&models.Share{TaskID: &task.ID, SharedByID: userID, Role: req.Role}, task.UserID, req.Email)
+}
+
+func (s *shareService) ShareList(listID, userID uint, req models.CreateShareRequest) (*models.Share, error) {
+	list, err := s.listService.AuthorizeList(listID, userID, AccessOwner)
+	if err != nil {
+		return nil, err
+	}
+	return s.createShare(&models.Share{ListID: &list.ID, SharedByID: userID, Role: req.Role}, list.UserID, req.Email)
+}
+
+// createShare gives the user with the email the share, unless they already
+// own what it grants access to.
+func (s *shareService) createShare(share *models.Share, ownerID uint, email string) (*models.Share, error) {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == ownerID {
+		return nil, ErrCannotShareWithSelf
+	}
+
+	share.UserID = user.ID
+	if err := s.shareRepo.Create(share); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+func (s *shareService) GetTaskShares(taskID, userID uint) ([]models.Share, error) {
+	task, err := s.taskService.GetTask(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.shareRepo.FindByTask(task.ID)
+}
+
+func (s *shareService) GetListShares(listID, userID uint) ([]models.Share, error) {
+	list, err := s.listService.GetList(listID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.shareRepo.FindByList(list.ID)
+}
+
+func (s *shareService) UpdateShare(shareID, userID uint, req models.UpdateShareRequest) (*models.Share, error) {
+	share, err := s.shareRepo.FindByID(shareID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTargetOwner(share, userID); err != nil {
+		return nil, err
+	}
+
+	share.Role = req.Role
+	if err := s.shareRepo.Update(share); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// DeleteShare revokes the share, which either the owner of what it grants
+// access to or the user it was given to may do.
+func (s *shareService) DeleteShare(shareID, userID uint) error {
+	share, err := s.shareRepo.FindByID(shareID)
+	if err != nil {
+		return err
+	}
+	if share.UserID != userID {
+		if err := s.checkTargetOwner(share, userID); err != nil {
+			return err
+		}
+	}
+	return s.shareRepo.Delete(share.ID)
+}
+
+// checkTargetOwner checks that the user owns the task or list the share grants
+// access to.
+func (s *shareService) checkTargetOwner(share *models.Share, userID uint) error {
+	var err error
+	if share.TaskID != nil {
+		_, err = s.taskService.AuthorizeTask(*share.TaskID, userID, AccessOwner)
+	} else {
+		_, err = s.listService.AuthorizeList(*share.ListID, userID, AccessOwner)
+	}
+
+	if errors.Is(err, ErrTaskAccessDenied) || errors.Is(err, ErrListAccessDenied) ||
+		errors.Is(err, repository.ErrTaskNotFound) || errors.Is(err, repository.ErrListNotFound) {
+		return ErrShareAccessDenied
+	}
+	return err
+}
+
+func (s *shareService) GetSharedWithMe(userID uint) (*SharedWithMe, error) {
+	shares, err := s.shareRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.taskRepo.FindSharedWith(userID)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := s.listRepo.FindSharedWith(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasksByID := make(map[uint]models.Task, len(tasks))
+	for _, task := range tasks {
+		tasksByID[task.ID] = task
+	}
+	listsByID := make(map[uint]models.List, len(lists))
+	for _, list := range lists {
+		listsByID[list.ID] = list
+	}
+
+	// Shares of trashed tasks have no task to show and are left out.
+	shared := &SharedWithMe{Tasks: []SharedTask{}, Lists: []SharedList{}}
+	for _, share := range shares {
+		if share.TaskID != nil {
+			if task, ok := tasksByID[*share.TaskID]; ok {
+				shared.Tasks = append(shared.Tasks, SharedTask{Share: share, Task: task})
+			}
+		} else if share.ListID != nil {
+			if list, ok := listsByID[*share.ListID]; ok {
+				shared.Lists = append(shared.Lists, SharedList{Share: share, List: list})
+			}
+		}
+	}
+	return shared, nil
+}
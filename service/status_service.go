@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+var (
+	ErrStatusRequired = errors.New("a list needs at least one open and one done status")
+	ErrStatusInUse    = errors.New("a status can only move into or out of the done category once no task has it")
+)
+
+// StatusService manages the workflow statuses of lists. Anyone who can see a
+// list can see its statuses and board, but only its owner configures them.
+type StatusService interface {
+	GetStatuses(listID, userID uint) ([]models.TaskStatus, error)
+	CreateStatus(listID, userID uint, req models.CreateStatusRequest) (*models.TaskStatus, error)
+	UpdateStatus(listID, userID, statusID uint, req models.UpdateStatusRequest) (*models.TaskStatus, error)
+	DeleteStatus(listID, userID, statusID uint) error
+	GetBoard(listID, userID uint) (*Board, error)
+}
+
+// Board is a list's top-level tasks grouped by status, with a column for each
+// of its statuses in order.
+type Board struct {
+	List    *models.List
+	Columns []BoardColumn
+}
+
+type BoardColumn struct {
+	Status models.TaskStatus
+	Tasks  []models.Task
+}
+
+type statusService struct {
+	statusRepo  repository.StatusRepository
+	taskRepo    repository.TaskRepository
+	listService ListService
+}
+
+func NewStatusService(statusRepo repository.StatusRepository, taskRepo repository.TaskRepository, listService ListService) StatusService {
+	return &statusService{
+		statusRepo:  statusRepo,
+		taskRepo:    taskRepo,
+		listService: listService,
+	}
+}
+
+func (s *statusService) GetStatuses(listID, userID uint) ([]models.TaskStatus, error) {
+	if _, err := s.listService.AuthorizeList(listID, userID, AccessView); err != nil {
+		return nil, err
+	}
+	return s.statusRepo.FindByList(listID)
+}
+
+func (s *statusService) CreateStatus(listID, userID uint, req models.CreateStatusRequest) (*models.TaskStatus, error) {
+	if _, err := s.listService.AuthorizeList(listID, userID, AccessOwner); err != nil {
+		return nil, err
+	}
+
+	status := &models.TaskStatus{
+		ListID:   listID,
+		Name:     req.Name,
+		Category: req.Category,
+	}
+	if req.Position != nil {
+		status.Position = *req.Position
+	} else {
+		position, err := s.statusRepo.NextPosition(listID)
+		if err != nil {
+			return nil, err
+		}
+		status.Position = position
+	}
+
+	if err := s.statusRepo.Create(status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// UpdateStatus renames, reorders or recategorizes the status. It can only move
+// into or out of the done category while no task has it, since that would
+// complete or reopen those tasks behind the back of the usual checks: their
+// hierarchy, recurrence, dependencies and history.
+func (s *statusService) UpdateStatus(listID, userID, statusID uint, req models.UpdateStatusRequest) (*models.TaskStatus, error) {
+	status, err := s.getStatus(listID, userID, statusID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		status.Name = req.Name
+	}
+	if req.Position != nil {
+		status.Position = *req.Position
+	}
+	if req.Category != "" && req.Category != status.Category {
+		wasDone := status.IsDone()
+		status.Category = req.Category
+		if status.IsDone() != wasDone {
+			if err := s.checkStatusesRemain(listID, statusID, wasDone); err != nil {
+				return nil, err
+			}
+			count, err := s.statusRepo.CountTasks(statusID)
+			if err != nil {
+				return nil, err
+			}
+			if count > 0 {
+				return nil, ErrStatusInUse
+			}
+		}
+	}
+
+	if err := s.statusRepo.Update(status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// DeleteStatus deletes the status, moving its tasks to the first other status
+// of the list with the same completion.
+func (s *statusService) DeleteStatus(listID, userID, statusID uint) error {
+	status, err := s.getStatus(listID, userID, statusID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkStatusesRemain(listID, statusID, status.IsDone()); err != nil {
+		return err
+	}
+	return s.statusRepo.Delete(status.ID)
+}
+
+func (s *statusService) GetBoard(listID, userID uint) (*Board, error) {
+	list, err := s.listService.AuthorizeList(listID, userID, AccessView)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := s.statusRepo.FindByList(listID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.taskRepo.FindByList(listID)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &Board{List: list, Columns: make([]BoardColumn, len(statuses))}
+	columns := make(map[uint]*BoardColumn, len(statuses))
+	for i, status := range statuses {
+		board.Columns[i] = BoardColumn{Status: status, Tasks: []models.Task{}}
+		columns[status.ID] = &board.Columns[i]
+	}
+	for _, task := range tasks {
+		if task.StatusID == nil {
+			continue
+		}
+		if column, ok := columns[*task.StatusID]; ok {
+			column.Tasks = append(column.Tasks, task)
+		}
+	}
+	return board, nil
+}
+
+// getStatus loads a status of the list, if the user owns the list.
+func (s *statusService) getStatus(listID, userID, statusID uint) (*models.TaskStatus, error) {
+	if _, err := s.listService.AuthorizeList(listID, userID, AccessOwner); err != nil {
+		return nil, err
+	}
+	status, err := s.statusRepo.FindByID(statusID)
+	if err != nil {
+		return nil, err
+	}
+	if status.ListID != listID {
+		return nil, repository.ErrStatusNotFound
+	}
+	return status, nil
+}
+
+// checkStatusesRemain makes sure the list keeps another status with the given
+// completion once the status is deleted or moved to the other side, so its
+// tasks can always be completed and reopened.
+func (s *statusService) checkStatusesRemain(listID, statusID uint, done bool) error {
+	statuses, err := s.statusRepo.FindByList(listID)
+	if err != nil {
+		return err
+	}
+	for _, other := range statuses {
+		if other.ID != statusID && other.IsDone() == done {
+			return nil
+		}
+	}
+	return ErrStatusRequired
+}
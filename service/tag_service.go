@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"hash/fnv"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+var (
+	ErrTagAccessDenied = errors.New("access to the requested tag is denied")
+)
+
+// tagPalette holds the colors handed out to tags created without one.
+var tagPalette = []string{
+	"#e57373", "#f06292", "#ba68c8", "#7986cb", "#4fc3f7",
+	"#4db6ac", "#81c784", "#dce775", "#ffb74d", "#a1887f",
+}
+
+type TagService interface {
+	CreateTag(userID uint, req models.CreateTagRequest) (*models.Tag, error)
+	GetTags(userID uint) ([]models.Tag, error)
+	GetTag(tagID, userID uint) (*models.Tag, error)
+	UpdateTag(tagID, userID uint, req models.UpdateTagRequest) (*models.Tag, error)
+	DeleteTag(tagID, userID uint) error
+}
+
+type tagService struct {
+	tagRepo repository.TagRepository
+}
+
+func NewTagService(repo repository.TagRepository) TagService {
+	return &tagService{
+		tagRepo: repo,
+	}
+}
+
+func (s *tagService) CreateTag(userID uint, req models.CreateTagRequest) (*models.Tag, error) {
+	color := req.Color
+	if color == "" {
+		color = defaultTagColor(req.Name)
+	}
+
+	tag := &models.Tag{
+		Name:   req.Name,
+		Color:  color,
+		UserID: userID,
+	}
+
+	if err := s.tagRepo.Create(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (s *tagService) GetTags(userID uint) ([]models.Tag, error) {
+	return s.tagRepo.FindByUser(userID)
+}
+
+func (s *tagService) GetTag(tagID, userID uint) (*models.Tag, error) {
+	tag, err := s.tagRepo.FindByID(tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tag.UserID != userID {
+		return nil, ErrTagAccessDenied
+	}
+	return tag, nil
+}
+
+func (s *tagService) UpdateTag(tagID, userID uint, req models.UpdateTagRequest) (*models.Tag, error) {
+	tag, err := s.GetTag(tagID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		tag.Name = req.Name
+	}
+	if req.Color != "" {
+		tag.Color = req.Color
+	}
+
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (s *tagService) DeleteTag(tagID, userID uint) error {
+	tag, err := s.GetTag(tagID, userID)
+	if err != nil {
+		return err
+	}
+	return s.tagRepo.Delete(tag.ID)
+}
+
+// defaultTagColor picks a palette color from the tag name, so the same name
+// always gets the same color.
+func defaultTagColor(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return tagPalette[h.Sum32()%uint32(len(tagPalette))]
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+var (
+	ErrInvalidBatchOperation = errors.New("invalid batch operation")
+)
+
+// batchChange is an operation ready to run, with its list and tags resolved.
+type batchChange struct {
+	models.BatchOperation
+	list *models.List
+	tags []models.Tag
+}
+
+// BatchTasks runs the operations in order, within a single transaction. The
+// user's access to every task is resolved once up front; tasks the user may
+// not change as an operation requires are reported in the results and
+// skipped, while any other failure rolls the whole batch back.
+func (s *taskService) BatchTasks(userID uint, operations []models.BatchOperation) ([]models.BatchItemResult, error) {
+	changes := make([]batchChange, len(operations))
+	var ids []uint
+	for i, op := range operations {
+		change, err := s.resolveBatchOperation(userID, op)
+		if err != nil {
+			return nil, err
+		}
+		changes[i] = change
+		ids = append(ids, change.TaskIDs...)
+	}
+
+	tasks, err := s.taskRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	levels := make(map[uint]AccessLevel, len(tasks))
+	for i := range tasks {
+		level, err := s.access.taskAccess(&tasks[i], userID)
+		if err != nil {
+			return nil, err
+		}
+		levels[tasks[i].ID] = level
+	}
+
+	var results []models.BatchItemResult
+	err = s.transaction(func(tx *taskService) error {
+		for i, change := range changes {
+			for _, id := range change.TaskIDs {
+				itemErr := batchAccessError(levels, id, change.Action)
+				if itemErr == nil {
+					itemErr = tx.applyBatchChange(id, change, userID)
+				}
+				if itemErr != nil && !isBatchItemError(itemErr) {
+					return itemErr
+				}
+
+				result := models.BatchItemResult{Operation: i, TaskID: id, Success: itemErr == nil}
+				if itemErr != nil {
+					result.Error = itemErr.Error()
+				}
+				results = append(results, result)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// batchAccessError reports whether the user may apply the action to the task.
+// Assignees may complete and reopen a task and editors may also change its
+// priority, but only its owner may delete it, move it to another list or tag
+// it with their own tags.
+func batchAccessError(levels map[uint]AccessLevel, taskID uint, action models.BatchAction) error {
+	level, ok := levels[taskID]
+	if !ok {
+		return repository.ErrTaskNotFound
+	}
+
+	required := AccessEdit
+	switch action {
+	case models.BatchComplete, models.BatchUncomplete:
+		required = AccessAssignee
+	case models.BatchDelete, models.BatchMove, models.BatchTag:
+		required = AccessOwner
+	}
+	if level < required {
+		return ErrTaskAccessDenied
+	}
+	return nil
+}
+
+// resolveBatchOperation checks the operation's arguments and loads the list
+// and tags it refers to.
+func (s *taskService) resolveBatchOperation(userID uint, op models.BatchOperation) (batchChange, error) {
+	change := batchChange{BatchOperation: op}
+	change.TaskIDs = uniqueIDs(op.TaskIDs)
+
+	var err error
+	switch op.Action {
+	case models.BatchComplete, models.BatchUncomplete, models.BatchDelete:
+	case models.BatchMove:
+		if op.ListID == nil {
+			return change, ErrInvalidBatchOperation
+		}
+		change.list, err = s.findWritableList(userID, *op.ListID)
+	case models.BatchTag:
+		if len(op.TagIDs) == 0 {
+			return change, ErrInvalidBatchOperation
+		}
+		change.tags, err = s.resolveTags(userID, op.TagIDs)
+	case models.BatchSetPriority:
+		if !op.Priority.IsValid() {
+			return change, ErrInvalidBatchOperation
+		}
+	default:
+		return change, ErrInvalidBatchOperation
+	}
+	return change, err
+}
+
+// applyBatchChange applies the change to one task, reloading it first since
+// earlier operations in the batch may have changed or deleted it.
+func (s *taskService) applyBatchChange(taskID uint, change batchChange, actorID uint) error {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTaskNotFound) && change.Action == models.BatchDelete {
+			// Already deleted along with a parent earlier in the batch.
+			return nil
+		}
+		return err
+	}
+
+	before := snapshotTask(task)
+	switch change.Action {
+	case models.BatchComplete, models.BatchUncomplete:
+		return s.setCompleted(task, change.Action == models.BatchComplete, change.Force, actorID)
+	case models.BatchDelete:
+		if err := s.taskRepo.Delete(task.ID); err != nil {
+			return err
+		}
+		return s.recordEvent(task.ID, actorID, models.TaskEventDeleted, nil)
+	case models.BatchMove:
+		if !sameID(task.WorkspaceID, change.list.WorkspaceID) {
+			return ErrInvalidList
+		}
+		moved := !sameID(task.ListID, &change.list.ID)
+		task.ListID = &change.list.ID
+		if moved {
+			if err := s.placeLast(task); err != nil {
+				return err
+			}
+		}
+		if err := s.taskRepo.Update(task); err != nil {
+			return err
+		}
+		if err := s.moveSubtasksWith(task); err != nil {
+			return err
+		}
+	case models.BatchTag:
+		if err := s.taskRepo.AddTags(task, change.tags); err != nil {
+			return err
+		}
+	case models.BatchSetPriority:
+		task.Priority = change.Priority
+		if err := s.taskRepo.Update(task); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidBatchOperation
+	}
+	return s.recordChange(task, actorID, before)
+}
+
+// setCompleted completes or reopens the task with the same side effects as
+// UpdateTask: the hierarchy is kept consistent and completing a recurring
+// task spawns its next occurrence. A blocked task is only completed if forced.
+func (s *taskService) setCompleted(task *models.Task, completed, force bool, actorID uint) error {
+	if task.Completed == completed {
+		return nil
+	}
+	return s.setStatusAndCompletion(task, task.StatusID, completed, force, actorID)
+}
+
+// setStatus moves the task to the status, completing or reopening it to
+// match, with the same side effects as setCompleted.
+func (s *taskService) setStatus(task *models.Task, status *models.TaskStatus, force bool, actorID uint) error {
+	return s.setStatusAndCompletion(task, &status.ID, status.IsDone(), force, actorID)
+}
+
+func (s *taskService) setStatusAndCompletion(task *models.Task, statusID *uint, completed, force bool, actorID uint) error {
+	if completed && !task.Completed && task.Blocked && !force {
+		return ErrTaskBlocked
+	}
+
+	before := snapshotTask(task)
+	wasCompleted := task.Completed
+	task.StatusID = statusID
+	task.Completed = completed
+	if err := s.taskRepo.Update(task); err != nil {
+		return err
+	}
+	if err := s.recordChange(task, actorID, before); err != nil {
+		return err
+	}
+	if completed == wasCompleted {
+		return nil
+	}
+	if err := s.cascadeCompletion(task, actorID); err != nil {
+		return err
+	}
+	if completed && task.RecurrenceRule != "" {
+		return s.spawnNextOccurrence(task, actorID)
+	}
+	return nil
+}
+
+// isBatchItemError reports whether err only concerns a single task, so the
+// rest of the batch can go on.
+func isBatchItemError(err error) bool {
+	return errors.Is(err, repository.ErrTaskNotFound) || errors.Is(err, ErrTaskAccessDenied) ||
+		errors.Is(err, ErrTaskBlocked)
+}
+
+func uniqueIDs(ids []uint) []uint {
+	unique := make([]uint, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+var (
+	ErrInvalidDependency = errors.New("a task can only be blocked by another task you can see")
+	ErrDependencyCycle   = errors.New("the dependency would make the task block itself")
+	ErrTaskBlocked       = errors.New("the task is blocked by tasks that are still open")
+)
+
+// AddDependency marks the task as blocked by another one. Editors of the task
+// may add any task they can see as a blocker, as long as that doesn't close a
+// cycle.
+func (s *taskService) AddDependency(taskID, userID, blockedByID uint) (*models.Task, error) {
+	task, err := s.AuthorizeTask(taskID, userID, AccessEdit)
+	if err != nil {
+		return nil, err
+	}
+	if blockedByID == task.ID {
+		return nil, ErrInvalidDependency
+	}
+	if _, err := s.AuthorizeTask(blockedByID, userID, AccessView); err != nil {
+		if errors.Is(err, repository.ErrTaskNotFound) || errors.Is(err, ErrTaskAccessDenied) {
+			return nil, ErrInvalidDependency
+		}
+		return nil, err
+	}
+	if err := s.checkDependencyCycle(task.ID, blockedByID); err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.AddDependency(&models.TaskDependency{TaskID: task.ID, BlockedByID: blockedByID}); err != nil {
+		return nil, err
+	}
+	return s.taskRepo.FindByID(task.ID)
+}
+
+// GetDependencies returns the tasks the task is blocked by and those it
+// blocks, leaving out any the user can't see.
+func (s *taskService) GetDependencies(taskID, userID uint) (blockedBy, blocking []models.Task, err error) {
+	if _, err := s.AuthorizeTask(taskID, userID, AccessView); err != nil {
+		return nil, nil, err
+	}
+	blockers, err := s.taskRepo.FindBlockers(taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	dependents, err := s.taskRepo.FindDependents(taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if blockedBy, err = s.visibleTasks(blockers, userID); err != nil {
+		return nil, nil, err
+	}
+	if blocking, err = s.visibleTasks(dependents, userID); err != nil {
+		return nil, nil, err
+	}
+	return blockedBy, blocking, nil
+}
+
+func (s *taskService) RemoveDependency(taskID, userID, blockedByID uint) (*models.Task, error) {
+	task, err := s.AuthorizeTask(taskID, userID, AccessEdit)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.taskRepo.RemoveDependency(task.ID, blockedByID); err != nil {
+		return nil, err
+	}
+	return s.taskRepo.FindByID(task.ID)
+}
+
+// checkDependencyCycle walks up from the new blocker through the tasks
+// blocking it, failing if it reaches the task that would be blocked.
+func (s *taskService) checkDependencyCycle(taskID, blockedByID uint) error {
+	visited := map[uint]bool{blockedByID: true}
+	frontier := []uint{blockedByID}
+	for len(frontier) > 0 {
+		ids, err := s.taskRepo.FindBlockerIDs(frontier)
+		if err != nil {
+			return err
+		}
+		frontier = frontier[:0]
+		for _, id := range ids {
+			if id == taskID {
+				return ErrDependencyCycle
+			}
+			if !visited[id] {
+				visited[id] = true
+				frontier = append(frontier, id)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *taskService) visibleTasks(tasks []models.Task, userID uint) ([]models.Task, error) {
+	visible := make([]models.Task, 0, len(tasks))
+	for i := range tasks {
+		level, err := s.access.taskAccess(&tasks[i], userID)
+		if err != nil {
+			return nil, err
+		}
+		if level >= AccessView {
+			visible = append(visible, tasks[i])
+		}
+	}
+	return visible, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+const defaultTimeZone = "UTC"
+
+// TaskBuckets groups open tasks by where their due date falls relative to
+// "today" in each task's own time zone.
+type TaskBuckets struct {
+	Overdue   []models.Task
+	Today     []models.Task
+	Upcoming  []models.Task
+	NoDueDate []models.Task
+}
+
+// setDueDate stores dueDate on the task following its all-day semantics. Timed
+// tasks keep the exact instant; all-day tasks keep only the calendar date the
+// client sent, stored as midnight UTC so it doesn't shift with the time zone.
+func setDueDate(task *models.Task, dueDate *time.Time) error {
+	if _, err := time.LoadLocation(task.TimeZone); err != nil {
+		return ErrInvalidTimeZone
+	}
+
+	if dueDate == nil {
+		task.DueDate = nil
+		return nil
+	}
+
+	normalized := dueDate.UTC()
+	if task.AllDay {
+		year, month, day := dueDate.Date()
+		normalized = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	task.DueDate = &normalized
+	return nil
+}
+
+func groupTasksByDueDate(tasks []models.Task, now time.Time) *TaskBuckets {
+	buckets := &TaskBuckets{
+		Overdue:   []models.Task{},
+		Today:     []models.Task{},
+		Upcoming:  []models.Task{},
+		NoDueDate: []models.Task{},
+	}
+
+	for _, task := range tasks {
+		if task.Completed {
+			continue
+		}
+		if task.DueDate == nil {
+			buckets.NoDueDate = append(buckets.NoDueDate, task)
+			continue
+		}
+
+		switch compareToToday(task, now) {
+		case -1:
+			buckets.Overdue = append(buckets.Overdue, task)
+		case 0:
+			buckets.Today = append(buckets.Today, task)
+		default:
+			buckets.Upcoming = append(buckets.Upcoming, task)
+		}
+	}
+
+	return buckets
+}
+
+// compareToToday reports whether the task is overdue (-1), due today (0) or
+// due later (1), as seen from the task's time zone.
+func compareToToday(task models.Task, now time.Time) int {
+	loc, err := time.LoadLocation(task.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	localNow := now.In(loc)
+	today := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), 0, 0, 0, 0, time.UTC)
+
+	var dueDay time.Time
+	if task.AllDay {
+		dueDay = *task.DueDate
+	} else {
+		if task.DueDate.Before(now) {
+			return -1
+		}
+		localDue := task.DueDate.In(loc)
+		dueDay = time.Date(localDue.Year(), localDue.Month(), localDue.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	switch {
+	case dueDay.Before(today):
+		return -1
+	case dueDay.Equal(today):
+		return 0
+	default:
+		return 1
+	}
+}
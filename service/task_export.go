@@ -0,0 +1,29 @@
+package service
+
+import (
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+// ExportTasks passes every task matching the query to write, in order, loading
+// them a page at a time so exports of any size use little memory. The query's
+// cursor and limit are ignored.
+func (s *taskService) ExportTasks(userID uint, query repository.TaskQuery, write func(task models.Task) error) error {
+	query.Cursor = ""
+	query.Limit = repository.MaxTaskPageSize
+	for {
+		page, err := s.ListTasks(userID, query)
+		if err != nil {
+			return err
+		}
+		for _, task := range page.Tasks {
+			if err := write(task); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+// taskSnapshot holds the tracked fields of a task, keyed by their JSON name,
+// in a form that compares and serializes cleanly.
+type taskSnapshot map[string]any
+
+func snapshotTask(task *models.Task) taskSnapshot {
+	var dueDate any
+	if task.DueDate != nil {
+		dueDate = task.DueDate.UTC().Format(time.RFC3339)
+	}
+
+	tagIDs := make([]uint, 0, len(task.Tags))
+	for _, tag := range task.Tags {
+		if !slices.Contains(tagIDs, tag.ID) {
+			tagIDs = append(tagIDs, tag.ID)
+		}
+	}
+	slices.Sort(tagIDs)
+
+	return taskSnapshot{
+		"title":           task.Title,
+		"content":         task.Content,
+		"completed":       task.Completed,
+		"priority":        task.Priority,
+		"due_date":        dueDate,
+		"all_day":         task.AllDay,
+		"time_zone":       task.TimeZone,
+		"list_id":         optionalID(task.ListID),
+		"parent_id":       optionalID(task.ParentID),
+		"position":        task.Position,
+		"assignee_id":     optionalID(task.AssigneeID),
+		"status_id":       optionalID(task.StatusID),
+		"recurrence_rule": task.RecurrenceRule,
+		"tag_ids":         tagIDs,
+	}
+}
+
+func optionalID(id *uint) any {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
+
+// diffSnapshots returns the fields whose value differs between the two
+// snapshots. Diffing against an empty snapshot lists every set field.
+func diffSnapshots(before, after taskSnapshot) models.TaskChanges {
+	changes := models.TaskChanges{}
+	for field, value := range after {
+		if !reflect.DeepEqual(before[field], value) {
+			changes[field] = models.FieldChange{Before: before[field], After: value}
+		}
+	}
+	return changes
+}
+
+// recordChange records the difference between the task's snapshot and its
+// current state, if there is any. Changes to completion are recorded as
+// completed or reopened events.
+func (s *taskService) recordChange(task *models.Task, actorID uint, before taskSnapshot) error {
+	changes := diffSnapshots(before, snapshotTask(task))
+	if len(changes) == 0 {
+		return nil
+	}
+
+	eventType := models.TaskEventUpdated
+	if _, ok := changes["completed"]; ok {
+		eventType = models.TaskEventReopened
+		if task.Completed {
+			eventType = models.TaskEventCompleted
+		}
+	}
+	return s.recordEvent(task.ID, actorID, eventType, changes)
+}
+
+// recordCreation records that the task was created, with every field it was
+// created with.
+func (s *taskService) recordCreation(task *models.Task, actorID uint) error {
+	return s.recordEvent(task.ID, actorID, models.TaskEventCreated, diffSnapshots(taskSnapshot{}, snapshotTask(task)))
+}
+
+func (s *taskService) recordEvent(taskID, actorID uint, eventType models.TaskEventType, changes models.TaskChanges) error {
+	return s.taskRepo.RecordEvents(models.TaskEvent{
+		TaskID:  taskID,
+		ActorID: actorID,
+		Type:    eventType,
+		Changes: changes,
+	})
+}
+
+// recordCompletions records that the tasks were completed or reopened as a
+// side effect of another change.
+func (s *taskService) recordCompletions(ids []uint, actorID uint, completed bool) error {
+	eventType := models.TaskEventReopened
+	if completed {
+		eventType = models.TaskEventCompleted
+	}
+
+	events := make([]models.TaskEvent, len(ids))
+	for i, id := range ids {
+		events[i] = models.TaskEvent{
+			TaskID:  id,
+			ActorID: actorID,
+			Type:    eventType,
+			Changes: models.TaskChanges{"completed": {Before: !completed, After: completed}},
+		}
+	}
+	return s.taskRepo.RecordEvents(events...)
+}
+
+func (s *taskService) GetTaskHistory(taskID, userID uint, cursor string, limit int) (*repository.TaskEventPage, error) {
+	task, err := s.GetTask(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.taskRepo.FindEvents(task.ID, cursor, limit)
+}
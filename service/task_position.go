@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+// minPositionGap is the smallest gap left between two neighbours' positions
+// before their siblings get renumbered. Halving the gap on every move keeps
+// about 30 moves into the same spot well within float64 precision.
+const minPositionGap = 1e-9
+
+// errPositionsExhausted means there is no room left between two neighbours.
+var errPositionsExhausted = errors.New("no position left between neighbours")
+
+// positionBetween computes a position sorting between the task's requested
+// neighbours. When only one neighbour is given, the other side is its closest
+// sibling, so the task lands right next to it.
+func (s *taskService) positionBetween(task *models.Task, userID uint, beforeID, afterID *uint) (float64, error) {
+	var lower, upper *float64
+
+	if beforeID != nil {
+		before, err := s.findSibling(task, userID, *beforeID)
+		if err != nil {
+			return 0, err
+		}
+		lower = &before.Position
+		if afterID == nil {
+			if upper, err = s.taskRepo.AdjacentPosition(before, task.ID, true); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if afterID != nil {
+		after, err := s.findSibling(task, userID, *afterID)
+		if err != nil {
+			return 0, err
+		}
+		upper = &after.Position
+		if beforeID == nil {
+			if lower, err = s.taskRepo.AdjacentPosition(after, task.ID, false); err != nil {
+				return 0, err
+			}
+		} else if *beforeID == *afterID || *upper < *lower {
+			return 0, ErrInvalidMove
+		}
+	}
+
+	switch {
+	case lower == nil:
+		return *upper - 1, nil
+	case upper == nil:
+		return *lower + 1, nil
+	case *upper-*lower < minPositionGap:
+		return 0, errPositionsExhausted
+	}
+	return *lower + (*upper-*lower)/2, nil
+}
+
+// renumberSiblings spreads the positions of the task and its siblings back to
+// whole numbers, keeping their order.
+func (s *taskService) renumberSiblings(task *models.Task) error {
+	ids, err := s.taskRepo.FindSiblingIDs(task)
+	if err != nil {
+		return err
+	}
+	return s.taskRepo.SetPositions(ids)
+}
+
+// placeLast puts a top-level task after its siblings, for when it lands in
+// another list. Subtasks keep their place under their parent.
+func (s *taskService) placeLast(task *models.Task) error {
+	if task.ParentID != nil {
+		return nil
+	}
+	position, err := s.taskRepo.NextPosition(task)
+	if err != nil {
+		return err
+	}
+	task.Position = position
+	return nil
+}
+
+// findSibling loads a neighbour for the task, which must be one of its
+// siblings that the user can see, whoever owns it.
+func (s *taskService) findSibling(task *models.Task, userID, id uint) (*models.Task, error) {
+	if id == task.ID {
+		return nil, ErrInvalidMove
+	}
+
+	sibling, err := s.taskRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrTaskNotFound) {
+			return nil, ErrInvalidMove
+		}
+		return nil, err
+	}
+	if !areSiblings(sibling, task) {
+		return nil, ErrInvalidMove
+	}
+	level, err := s.access.taskAccess(sibling, userID)
+	if err != nil {
+		return nil, err
+	}
+	if level < AccessView {
+		return nil, ErrInvalidMove
+	}
+	return sibling, nil
+}
+
+// areSiblings reports whether the tasks are positioned relative to each other,
+// following the same rules as the repository's sibling queries.
+func areSiblings(a, b *models.Task) bool {
+	switch {
+	case a.ParentID != nil || b.ParentID != nil:
+		return sameID(a.ParentID, b.ParentID)
+	case a.ListID != nil || b.ListID != nil:
+		return sameID(a.ListID, b.ListID)
+	}
+	return sameID(a.WorkspaceID, b.WorkspaceID) && a.UserID == b.UserID
+}
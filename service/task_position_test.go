@@ -0,0 +1,164 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+// AdjacentPosition finds the closest sibling by going through every task, with
+// areSiblings standing in for the repository's sibling query.
+func (r *fakeTaskRepository) AdjacentPosition(neighbour *models.Task, excludeID uint, after bool) (*float64, error) {
+	var closest *float64
+	for _, task := range r.tasks {
+		if task.ID == neighbour.ID || task.ID == excludeID || !areSiblings(task, neighbour) {
+			continue
+		}
+		position := task.Position
+		if after && position >= neighbour.Position && (closest == nil || position < *closest) {
+			closest = &position
+		}
+		if !after && position <= neighbour.Position && (closest == nil || position > *closest) {
+			closest = &position
+		}
+	}
+	return closest, nil
+}
+
+type fakeListRepository struct {
+	repository.ListRepository
+	lists map[uint]*models.List
+}
+
+func (r *fakeListRepository) FindByID(id uint) (*models.List, error) {
+	list, ok := r.lists[id]
+	if !ok {
+		return nil, repository.ErrListNotFound
+	}
+	return list, nil
+}
+
+type fakeShareRepository struct {
+	repository.ShareRepository
+}
+
+func (r *fakeShareRepository) FindRoles(userID uint, taskIDs []uint, listID *uint) ([]models.ShareRole, error) {
+	return nil, nil
+}
+
+type fakeWorkspaceRepository struct {
+	repository.WorkspaceRepository
+	// roles maps workspace IDs to their members' roles.
+	roles map[uint]map[uint]models.WorkspaceRole
+}
+
+func (r *fakeWorkspaceRepository) FindMember(workspaceID, userID uint) (*models.WorkspaceMember, error) {
+	role, ok := r.roles[workspaceID][userID]
+	if !ok {
+		return nil, repository.ErrMemberNotFound
+	}
+	return &models.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: role}, nil
+}
+
+func TestPositionBetweenAcrossOwners(t *testing.T) {
+	const (
+		alice uint = 1
+		bob   uint = 2
+		carol uint = 3
+	)
+	workspaceID, listID, otherListID := uint(7), uint(3), uint(5)
+	task := func(id, owner uint, list *uint, position float64) *models.Task {
+		task := &models.Task{UserID: owner, WorkspaceID: &workspaceID, ListID: list, Position: position}
+		task.ID = id
+		return task
+	}
+	tasks := map[uint]*models.Task{
+		1: task(1, alice, &listID, 1),
+		2: task(2, bob, &listID, 2),
+		3: task(3, alice, &listID, 3),
+		4: task(4, bob, &otherListID, 1),
+	}
+
+	list := func(id, owner uint) *models.List {
+		list := &models.List{UserID: owner, WorkspaceID: &workspaceID}
+		list.ID = id
+		return list
+	}
+
+	repo := &fakeTaskRepository{tasks: tasks}
+	lists := &fakeListRepository{lists: map[uint]*models.List{
+		listID:      list(listID, alice),
+		otherListID: list(otherListID, bob),
+	}}
+	workspaces := &fakeWorkspaceRepository{roles: map[uint]map[uint]models.WorkspaceRole{
+		workspaceID: {alice: models.WorkspaceRoleMember, bob: models.WorkspaceRoleMember},
+	}}
+	s := &taskService{
+		taskRepo: repo,
+		access:   newAccessPolicy(repo, lists, &fakeShareRepository{}, workspaces),
+	}
+	id := func(id uint) *uint { return &id }
+
+	tests := []struct {
+		name     string
+		task     uint
+		userID   uint
+		beforeID *uint
+		afterID  *uint
+		want     float64
+		wantErr  error
+	}{
+		{name: "after a teammate's task", task: 1, userID: alice, beforeID: id(2), want: 2.5},
+		{name: "before a teammate's task", task: 3, userID: alice, afterID: id(2), want: 1.5},
+		{name: "between tasks of different owners", task: 2, userID: bob, beforeID: id(1), afterID: id(3), want: 2},
+		{name: "next to a task in another list", task: 1, userID: alice, beforeID: id(4), wantErr: ErrInvalidMove},
+		{name: "next to a task the user can't see", task: 1, userID: carol, beforeID: id(2), wantErr: ErrInvalidMove},
+		{name: "next to itself", task: 1, userID: alice, beforeID: id(1), wantErr: ErrInvalidMove},
+		{name: "next to a missing task", task: 1, userID: alice, afterID: id(99), wantErr: ErrInvalidMove},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.positionBetween(tasks[tt.task], tt.userID, tt.beforeID, tt.afterID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("positionBetween returned %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("positionBetween: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("position = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAreSiblings(t *testing.T) {
+	id := func(id uint) *uint { return &id }
+
+	tests := []struct {
+		name string
+		a, b models.Task
+		want bool
+	}{
+		{name: "same parent, different owners", a: models.Task{UserID: 1, ParentID: id(9)}, b: models.Task{UserID: 2, ParentID: id(9)}, want: true},
+		{name: "different parents", a: models.Task{ParentID: id(9), ListID: id(3)}, b: models.Task{ParentID: id(8), ListID: id(3)}, want: false},
+		{name: "subtask and top-level task", a: models.Task{ParentID: id(9), ListID: id(3)}, b: models.Task{ListID: id(3)}, want: false},
+		{name: "same list, different owners", a: models.Task{UserID: 1, ListID: id(3)}, b: models.Task{UserID: 2, ListID: id(3)}, want: true},
+		{name: "different lists", a: models.Task{UserID: 1, ListID: id(3)}, b: models.Task{UserID: 1, ListID: id(4)}, want: false},
+		{name: "list-less workspace tasks of one owner", a: models.Task{UserID: 1, WorkspaceID: id(7)}, b: models.Task{UserID: 1, WorkspaceID: id(7)}, want: true},
+		{name: "list-less workspace tasks of different owners", a: models.Task{UserID: 1, WorkspaceID: id(7)}, b: models.Task{UserID: 2, WorkspaceID: id(7)}, want: false},
+		{name: "list-less tasks in different spaces", a: models.Task{UserID: 1, WorkspaceID: id(7)}, b: models.Task{UserID: 1}, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := areSiblings(&tt.a, &tt.b); got != tt.want {
+			t.Errorf("%s: areSiblings = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/quickadd"
+)
+
+// maxTitleLength and maxTagNameLength match the validation of task and tag
+// requests.
+const (
+	maxTitleLength   = 100
+	maxTagNameLength = 50
+)
+
+var (
+	ErrQuickAddTitle = errors.New("the text must leave a title of 1 to 100 characters once dates, tags and the like are taken out")
+)
+
+// QuickAddTask creates a task from a single line of text such as "Pay rent
+// tomorrow 9am #home !high every month", read in the given time zone or the
+// user's own. Tags are matched by name, and created if the user has none
+// with that name. It returns what was understood along with the task.
+func (s *taskService) QuickAddTask(userID uint, workspaceID *uint, req models.QuickAddTaskRequest, now time.Time) (*models.Task, *quickadd.Result, error) {
+	timeZone := req.TimeZone
+	if timeZone == "" {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		timeZone = user.TimeZone
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, nil, ErrInvalidTimeZone
+	}
+
+	parsed := quickadd.Parse(req.Text, now.In(location))
+	if length := utf8.RuneCountInString(parsed.Title); length == 0 || length > maxTitleLength {
+		return nil, &parsed, ErrQuickAddTitle
+	}
+
+	level, err := s.access.workspaceAccess(workspaceID, userID)
+	if err != nil {
+		return nil, &parsed, err
+	}
+	if workspaceID != nil && level < AccessEdit {
+		return nil, &parsed, ErrWorkspaceAccessDenied
+	}
+
+	var task *models.Task
+	err = s.transaction(func(tx *taskService) error {
+		// Missing tags are created in the transaction too, so a task that
+		// can't be created leaves none behind.
+		tagIDs, err := tx.resolveTagNames(userID, parsed.Tags)
+		if err != nil {
+			return err
+		}
+
+		task, err = tx.createTask(userID, workspaceID, models.CreateTaskRequest{
+			Title:          parsed.Title,
+			Priority:       parsed.Priority,
+			DueDate:        parsed.DueDate,
+			AllDay:         parsed.AllDay,
+			TimeZone:       timeZone,
+			TagIDs:         tagIDs,
+			ListID:         req.ListID,
+			RecurrenceRule: parsed.RecurrenceRule,
+		}, nil)
+		return err
+	})
+	if err != nil {
+		return nil, &parsed, err
+	}
+	return task, &parsed, nil
+}
+
+// resolveTagNames returns the IDs of the user's tags with the given names,
+// ignoring case, creating any that don't exist yet.
+func (s *taskService) resolveTagNames(userID uint, names []string) ([]uint, error) {
+	existing, err := s.tagRepo.FindByNames(userID, names)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, tag := range existing {
+			if strings.EqualFold(tag.Name, name) {
+				ids, found = append(ids, tag.ID), true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		if utf8.RuneCountInString(name) > maxTagNameLength {
+			return nil, ErrInvalidTags
+		}
+		tag := &models.Tag{Name: name, Color: defaultTagColor(name), UserID: userID}
+		if err := s.tagRepo.Create(tag); err != nil {
+			return nil, err
+		}
+		ids = append(ids, tag.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+// fakeTagRepository keeps the tags created in memory.
+type fakeTagRepository struct {
+	repository.TagRepository
+	created []*models.Tag
+}
+
+func (r *fakeTagRepository) FindByNames(userID uint, names []string) ([]models.Tag, error) {
+	return nil, nil
+}
+
+func (r *fakeTagRepository) Create(tag *models.Tag) error {
+	r.created = append(r.created, tag)
+	return nil
+}
+
+func TestQuickAddTaskChecksAccessFirst(t *testing.T) {
+	workspaceID := uint(7)
+	tags := &fakeTagRepository{}
+	repo := &fakeTaskRepository{}
+	workspaces := &fakeWorkspaceRepository{roles: map[uint]map[uint]models.WorkspaceRole{
+		workspaceID: {1: models.WorkspaceRoleGuest},
+	}}
+	s := &taskService{
+		taskRepo: repo,
+		tagRepo:  tags,
+		access:   newAccessPolicy(repo, &fakeListRepository{}, &fakeShareRepository{}, workspaces),
+	}
+	now := time.Date(2026, time.October, 14, 12, 0, 0, 0, time.UTC)
+
+	for _, userID := range []uint{1, 2} {
+		_, _, err := s.QuickAddTask(userID, &workspaceID, models.QuickAddTaskRequest{Text: "Pay rent #home #bills", TimeZone: "UTC"}, now)
+		if !errors.Is(err, ErrWorkspaceAccessDenied) {
+			t.Errorf("QuickAddTask by user %d returned %v, want ErrWorkspaceAccessDenied", userID, err)
+		}
+	}
+	if len(tags.created) > 0 || len(repo.created) > 0 {
+		t.Errorf("created %d tags and %d tasks without access", len(tags.created), len(repo.created))
+	}
+}
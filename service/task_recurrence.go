@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/teambition/rrule-go"
+)
+
+var (
+	ErrInvalidRecurrence      = errors.New("invalid recurrence rule")
+	ErrRecurrenceNeedsDueDate = errors.New("recurring tasks need a due date")
+)
+
+// normalizeRecurrenceRule validates an RFC 5545 RRULE (with or without the
+// "RRULE:" prefix) and returns it in canonical form.
+func normalizeRecurrenceRule(rule string) (string, error) {
+	option, err := rrule.StrToROption(rule)
+	if err != nil || !option.Dtstart.IsZero() {
+		return "", ErrInvalidRecurrence
+	}
+	if _, err := rrule.NewRRule(*option); err != nil {
+		return "", ErrInvalidRecurrence
+	}
+	return option.RRuleString(), nil
+}
+
+// nextOccurrence works out the due date of the occurrence following the task,
+// along with the rule that occurrence carries on with. The rule is evaluated
+// from the task's due date in its own time zone, so "every Monday" means
+// Monday where the user is. ok is false once the series has run out.
+//
+// COUNT is tracked by handing each new occurrence one fewer remaining
+// repetition, so a series never needs to look back at its earlier tasks.
+func nextOccurrence(task *models.Task) (next time.Time, rule string, ok bool, err error) {
+	loc := time.UTC
+	if !task.AllDay {
+		if loc, err = time.LoadLocation(task.TimeZone); err != nil {
+			return time.Time{}, "", false, ErrInvalidTimeZone
+		}
+	}
+
+	option, err := rrule.StrToROptionInLocation(task.RecurrenceRule, loc)
+	if err != nil {
+		return time.Time{}, "", false, ErrInvalidRecurrence
+	}
+
+	remaining := option.Count
+	if remaining == 1 {
+		return time.Time{}, "", false, nil
+	}
+
+	option.Count = 0
+	option.Dtstart = task.DueDate.In(loc)
+	recurrence, err := rrule.NewRRule(*option)
+	if err != nil {
+		return time.Time{}, "", false, ErrInvalidRecurrence
+	}
+
+	next = recurrence.After(option.Dtstart, false)
+	if next.IsZero() {
+		return time.Time{}, "", false, nil
+	}
+
+	if remaining > 1 {
+		option.Count = remaining - 1
+	}
+	option.Dtstart = time.Time{}
+	return next.UTC(), option.RRuleString(), true, nil
+}
+
+// seriesRootID returns the ID of the first task of the task's recurring
+// series. The first occurrence has no SeriesID of its own.
+func seriesRootID(task *models.Task) uint {
+	if task.SeriesID != nil {
+		return *task.SeriesID
+	}
+	return task.ID
+}
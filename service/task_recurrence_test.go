@@ -0,0 +1,253 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+func utc(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestNextOccurrence(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		due      time.Time
+		allDay   bool
+		timeZone string
+		want     time.Time
+		wantRule string
+		wantDone bool
+	}{
+		{
+			name:     "daily timed",
+			rule:     "FREQ=DAILY",
+			due:      utc(2026, time.October, 14, 12, 0),
+			timeZone: "UTC",
+			want:     utc(2026, time.October, 15, 12, 0),
+			wantRule: "FREQ=DAILY",
+		},
+		{
+			name:     "every other week",
+			rule:     "FREQ=WEEKLY;INTERVAL=2",
+			due:      utc(2026, time.October, 14, 0, 0),
+			allDay:   true,
+			want:     utc(2026, time.October, 28, 0, 0),
+			wantRule: "FREQ=WEEKLY;INTERVAL=2",
+		},
+		{
+			name:     "accepts the RRULE prefix",
+			rule:     "RRULE:FREQ=DAILY",
+			due:      utc(2026, time.October, 14, 0, 0),
+			allDay:   true,
+			want:     utc(2026, time.October, 15, 0, 0),
+			wantRule: "FREQ=DAILY",
+		},
+
+		// COUNT is carried over as the repetitions left after the next one.
+		{
+			name:     "count with several left",
+			rule:     "FREQ=DAILY;COUNT=3",
+			due:      utc(2026, time.October, 14, 0, 0),
+			allDay:   true,
+			want:     utc(2026, time.October, 15, 0, 0),
+			wantRule: "FREQ=DAILY;COUNT=2",
+		},
+		{
+			name:     "count with one left",
+			rule:     "FREQ=DAILY;COUNT=2",
+			due:      utc(2026, time.October, 15, 0, 0),
+			allDay:   true,
+			want:     utc(2026, time.October, 16, 0, 0),
+			wantRule: "FREQ=DAILY;COUNT=1",
+		},
+		{
+			name:     "count exhausted",
+			rule:     "FREQ=DAILY;COUNT=1",
+			due:      utc(2026, time.October, 16, 0, 0),
+			allDay:   true,
+			wantDone: true,
+		},
+
+		// UNTIL ends the series once the next date would fall after it.
+		{
+			name:     "until not reached",
+			rule:     "FREQ=DAILY;UNTIL=20261016T120000Z",
+			due:      utc(2026, time.October, 15, 12, 0),
+			timeZone: "UTC",
+			want:     utc(2026, time.October, 16, 12, 0),
+			wantRule: "FREQ=DAILY;UNTIL=20261016T120000Z",
+		},
+		{
+			name:     "until reached",
+			rule:     "FREQ=DAILY;UNTIL=20261016T120000Z",
+			due:      utc(2026, time.October, 16, 12, 0),
+			timeZone: "UTC",
+			wantDone: true,
+		},
+
+		// BYDAY and nth-weekday rules.
+		{
+			name:     "weekdays midweek",
+			rule:     "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+			due:      utc(2026, time.October, 14, 0, 0),
+			allDay:   true,
+			want:     utc(2026, time.October, 16, 0, 0),
+			wantRule: "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+		},
+		{
+			name:     "weekdays over the weekend",
+			rule:     "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+			due:      utc(2026, time.October, 16, 0, 0),
+			allDay:   true,
+			want:     utc(2026, time.October, 19, 0, 0),
+			wantRule: "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+		},
+		{
+			name:     "second tuesday",
+			rule:     "FREQ=MONTHLY;BYDAY=2TU",
+			due:      utc(2026, time.October, 13, 0, 0),
+			allDay:   true,
+			want:     utc(2026, time.November, 10, 0, 0),
+			wantRule: "FREQ=MONTHLY;BYDAY=+2TU",
+		},
+		{
+			name:     "last friday",
+			rule:     "FREQ=MONTHLY;BYDAY=-1FR",
+			due:      utc(2026, time.October, 30, 0, 0),
+			allDay:   true,
+			want:     utc(2026, time.November, 27, 0, 0),
+			wantRule: "FREQ=MONTHLY;BYDAY=-1FR",
+		},
+		{
+			name:     "the 31st skips shorter months",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=31",
+			due:      utc(2026, time.October, 31, 0, 0),
+			allDay:   true,
+			want:     utc(2026, time.December, 31, 0, 0),
+			wantRule: "FREQ=MONTHLY;BYMONTHDAY=31",
+		},
+
+		// Timed tasks follow the weekday and wall clock of their own time
+		// zone, while all-day ones ignore it.
+		{
+			name:     "weekday in the task's time zone",
+			rule:     "FREQ=WEEKLY;BYDAY=MO",
+			due:      utc(2026, time.October, 20, 5, 0), // Monday 22:00 in Los Angeles
+			timeZone: "America/Los_Angeles",
+			want:     utc(2026, time.October, 27, 5, 0),
+			wantRule: "FREQ=WEEKLY;BYDAY=MO",
+		},
+		{
+			name:     "all-day ignores the time zone",
+			rule:     "FREQ=WEEKLY;BYDAY=MO",
+			due:      utc(2026, time.October, 19, 0, 0),
+			allDay:   true,
+			timeZone: "America/Los_Angeles",
+			want:     utc(2026, time.October, 26, 0, 0),
+			wantRule: "FREQ=WEEKLY;BYDAY=MO",
+		},
+
+		// Across DST changes timed tasks keep their wall-clock time, so their
+		// UTC time shifts by an hour, while all-day ones stay at midnight UTC.
+		{
+			name:     "timed across the start of DST",
+			rule:     "FREQ=DAILY",
+			due:      utc(2026, time.March, 7, 14, 0), // 09:00 EST
+			timeZone: "America/New_York",
+			want:     utc(2026, time.March, 8, 13, 0), // 09:00 EDT
+			wantRule: "FREQ=DAILY",
+		},
+		{
+			name:     "timed across the end of DST",
+			rule:     "FREQ=DAILY",
+			due:      utc(2026, time.October, 31, 13, 0), // 09:00 EDT
+			timeZone: "America/New_York",
+			want:     utc(2026, time.November, 1, 14, 0), // 09:00 EST
+			wantRule: "FREQ=DAILY",
+		},
+		{
+			name:     "all-day across the start of DST",
+			rule:     "FREQ=DAILY",
+			due:      utc(2026, time.March, 7, 0, 0),
+			allDay:   true,
+			timeZone: "America/New_York",
+			want:     utc(2026, time.March, 8, 0, 0),
+			wantRule: "FREQ=DAILY",
+		},
+		{
+			name:     "weekly across the end of DST",
+			rule:     "FREQ=WEEKLY;BYDAY=SA",
+			due:      utc(2026, time.October, 31, 22, 30), // Saturday 18:30 EDT
+			timeZone: "America/New_York",
+			want:     utc(2026, time.November, 7, 23, 30), // Saturday 18:30 EST
+			wantRule: "FREQ=WEEKLY;BYDAY=SA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due := tt.due
+			task := &models.Task{
+				DueDate:        &due,
+				AllDay:         tt.allDay,
+				TimeZone:       tt.timeZone,
+				RecurrenceRule: tt.rule,
+			}
+
+			next, rule, ok, err := nextOccurrence(task)
+			if err != nil {
+				t.Fatalf("nextOccurrence: %v", err)
+			}
+			if tt.wantDone {
+				if ok {
+					t.Errorf("got another occurrence on %v with %q, want the series to be over", next, rule)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("the series ended, want another occurrence")
+			}
+			if !next.Equal(tt.want) {
+				t.Errorf("next = %v, want %v", next, tt.want)
+			}
+			if rule != tt.wantRule {
+				t.Errorf("rule = %q, want %q", rule, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestNextOccurrenceErrors(t *testing.T) {
+	due := utc(2026, time.October, 14, 12, 0)
+
+	tests := []struct {
+		name string
+		task models.Task
+		want error
+	}{
+		{
+			name: "unknown time zone",
+			task: models.Task{DueDate: &due, TimeZone: "Mars/Olympus_Mons", RecurrenceRule: "FREQ=DAILY"},
+			want: ErrInvalidTimeZone,
+		},
+		{
+			name: "malformed rule",
+			task: models.Task{DueDate: &due, TimeZone: "UTC", RecurrenceRule: "FREQ=FORTNIGHTLY"},
+			want: ErrInvalidRecurrence,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := nextOccurrence(&tt.task); !errors.Is(err, tt.want) {
+				t.Errorf("nextOccurrence returned %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -1,89 +1,913 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"time"
 
+	"github.com/RLRama/listario-backend/logger"
 	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/quickadd"
 	"github.com/RLRama/listario-backend/repository"
+	"github.com/RLRama/listario-backend/storage"
 )
 
 var (
-	ErrTaskAccessDenied = errors.New("access to the requested task is denied")
+	ErrTaskAccessDenied    = errors.New("access to the requested task is denied")
+	ErrInvalidTimeZone     = errors.New("invalid time zone")
+	ErrInvalidTags         = errors.New("one or more tags do not exist")
+	ErrInvalidList         = errors.New("list does not exist")
+	ErrInvalidSubtaskOrder = errors.New("the new order must list every subtask exactly once")
+	ErrInvalidMove         = errors.New("neighbours must be distinct siblings of the task, in order")
+	ErrInvalidAssignee     = errors.New("tasks can only be assigned to users who can see them")
+	ErrInvalidStatus       = errors.New("status does not belong to the task's list")
 )
 
 type TaskService interface {
-	CreateTask(userID uint, title, content string) (*models.Task, error)
+	CreateTask(userID uint, workspaceID *uint, req models.CreateTaskRequest) (*models.Task, error)
+	QuickAddTask(userID uint, workspaceID *uint, req models.QuickAddTaskRequest, now time.Time) (*models.Task, *quickadd.Result, error)
+	CreateTaskTree(userID uint, workspaceID *uint, tree TaskTree) (*models.Task, error)
+	CreateTaskTrees(userID uint, workspaceID *uint, trees []TaskTree) ([]models.Task, error)
+	CreateSubtask(parentID, userID uint, req models.CreateTaskRequest) (*models.Task, error)
+	GetSubtasks(parentID, userID uint) ([]models.Task, error)
+	ReorderSubtasks(parentID, userID uint, taskIDs []uint) ([]models.Task, error)
+	MoveTask(taskID, userID uint, req models.MoveTaskRequest) (*models.Task, error)
+	SetRecurrence(taskID, userID uint, rule string) (*models.Task, error)
+	StopRecurrence(taskID, userID uint) (*models.Task, error)
 	GetTask(taskID, userID uint) (*models.Task, error)
-	GetTasksByUser(userID uint) ([]models.Task, error)
-	UpdateTask(taskID, userID uint, title, content string, completed *bool) (*models.Task, error)
+	AssignTask(taskID, userID, assigneeID uint) (*models.Task, error)
+	UnassignTask(taskID, userID uint) (*models.Task, error)
+	AuthorizeTask(taskID, userID uint, level AccessLevel) (*models.Task, error)
+	GetTaskHistory(taskID, userID uint, cursor string, limit int) (*repository.TaskEventPage, error)
+	ListTasks(userID uint, query repository.TaskQuery) (*repository.TaskPage, error)
+	ExportTasks(userID uint, query repository.TaskQuery, write func(task models.Task) error) error
+	GetTaskBuckets(userID uint, workspaceID *uint, now time.Time) (*TaskBuckets, error)
+	SearchTasks(userID uint, workspaceID *uint, text string, limit, offset int) ([]repository.TaskSearchResult, error)
+	UpdateTask(taskID, userID uint, req models.UpdateTaskRequest) (*models.Task, error)
 	DeleteTask(taskID, userID uint) error
+	GetTrash(userID uint) ([]models.Task, error)
+	RestoreTask(taskID, userID uint) (*models.Task, error)
+	PurgeTask(taskID, userID uint) error
+	PurgeTrash(deletedBefore time.Time) (int, error)
+	BatchTasks(userID uint, operations []models.BatchOperation) ([]models.BatchItemResult, error)
+
+	AddDependency(taskID, userID, blockedByID uint) (*models.Task, error)
+	GetDependencies(taskID, userID uint) (blockedBy, blocking []models.Task, err error)
+	RemoveDependency(taskID, userID, blockedByID uint) (*models.Task, error)
 }
 
 type taskService struct {
-	taskRepo repository.TaskRepository
+	taskRepo   repository.TaskRepository
+	userRepo   repository.UserRepository
+	tagRepo    repository.TagRepository
+	listRepo   repository.ListRepository
+	statusRepo repository.StatusRepository
+	blobStore  storage.BlobStore
+	access     *accessPolicy
 }
 
-func NewTaskService(repo repository.TaskRepository) TaskService {
+func NewTaskService(taskRepo repository.TaskRepository, userRepo repository.UserRepository, tagRepo repository.TagRepository, listRepo repository.ListRepository, statusRepo repository.StatusRepository, shareRepo repository.ShareRepository, workspaceRepo repository.WorkspaceRepository, blobStore storage.BlobStore) TaskService {
 	return &taskService{
-		taskRepo: repo,
+		taskRepo:   taskRepo,
+		userRepo:   userRepo,
+		tagRepo:    tagRepo,
+		listRepo:   listRepo,
+		statusRepo: statusRepo,
+		blobStore:  blobStore,
+		access:     newAccessPolicy(taskRepo, listRepo, shareRepo, workspaceRepo),
+	}
+}
+
+// CreateTask creates a task in the workspace, or in the user's personal space
+// when workspaceID is nil.
+func (s *taskService) CreateTask(userID uint, workspaceID *uint, req models.CreateTaskRequest) (*models.Task, error) {
+	level, err := s.access.workspaceAccess(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceID != nil && level < AccessEdit {
+		return nil, ErrWorkspaceAccessDenied
+	}
+
+	var task *models.Task
+	err = s.transaction(func(tx *taskService) error {
+		task, err = tx.createTask(userID, workspaceID, req, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// transaction runs fn with a copy of the service whose task and tag
+// repositories work within a single transaction, so a change and the history
+// events describing it are committed or rolled back together.
+func (s *taskService) transaction(fn func(tx *taskService) error) error {
+	return s.taskRepo.Transaction(func(repo repository.TaskRepository) error {
+		tx := *s
+		tx.taskRepo = repo
+		tx.tagRepo = repo.Tags()
+		return fn(&tx)
+	})
+}
+
+// CreateSubtask adds a task under the parent, in the parent's list and owned by
+// the parent's owner. An open subtask reopens its ancestors, since they are no
+// longer finished.
+func (s *taskService) CreateSubtask(parentID, userID uint, req models.CreateTaskRequest) (*models.Task, error) {
+	parent, err := s.AuthorizeTask(parentID, userID, AccessEdit)
+	if err != nil {
+		return nil, err
 	}
+
+	req.ListID = parent.ListID
+	var task *models.Task
+	err = s.transaction(func(tx *taskService) error {
+		if task, err = tx.createTask(userID, parent.WorkspaceID, req, parent); err != nil {
+			return err
+		}
+		return tx.cascadeCompletion(task, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
 }
 
-func (s *taskService) CreateTask(userID uint, title, content string) (*models.Task, error) {
+func (s *taskService) GetSubtasks(parentID, userID uint) ([]models.Task, error) {
+	parent, err := s.GetTask(parentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.taskRepo.FindChildren(parent.ID)
+}
+
+func (s *taskService) ReorderSubtasks(parentID, userID uint, taskIDs []uint) ([]models.Task, error) {
+	parent, err := s.AuthorizeTask(parentID, userID, AccessEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := s.taskRepo.FindChildren(parent.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(taskIDs) != len(children) {
+		return nil, ErrInvalidSubtaskOrder
+	}
+	remaining := make(map[uint]bool, len(children))
+	for _, child := range children {
+		remaining[child.ID] = true
+	}
+	for _, id := range taskIDs {
+		if !remaining[id] {
+			return nil, ErrInvalidSubtaskOrder
+		}
+		delete(remaining, id)
+	}
+
+	byID := make(map[uint]*models.Task, len(children))
+	for i := range children {
+		byID[children[i].ID] = &children[i]
+	}
+	err = s.transaction(func(tx *taskService) error {
+		if err := tx.taskRepo.SetPositions(taskIDs); err != nil {
+			return err
+		}
+		for i, id := range taskIDs {
+			child := byID[id]
+			before := snapshotTask(child)
+			child.Position = float64(i + 1)
+			if err := tx.recordChange(child, userID, before); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.taskRepo.FindChildren(parent.ID)
+}
+
+// MoveTask gives the task a position between the requested neighbours. Only
+// the moved task is written, unless the neighbours' positions are too close
+// to fit another one between them, in which case its siblings are renumbered
+// first.
+func (s *taskService) MoveTask(taskID, userID uint, req models.MoveTaskRequest) (*models.Task, error) {
+	task, err := s.AuthorizeTask(taskID, userID, AccessEdit)
+	if err != nil {
+		return nil, err
+	}
+	if req.BeforeID == nil && req.AfterID == nil {
+		return nil, ErrInvalidMove
+	}
+
+	err = s.transaction(func(tx *taskService) error {
+		position, err := tx.positionBetween(task, userID, req.BeforeID, req.AfterID)
+		if errors.Is(err, errPositionsExhausted) {
+			if err := tx.renumberSiblings(task); err != nil {
+				return err
+			}
+			position, err = tx.positionBetween(task, userID, req.BeforeID, req.AfterID)
+		}
+		if err != nil {
+			return err
+		}
+
+		before := snapshotTask(task)
+		task.Position = position
+		if err := tx.taskRepo.Update(task); err != nil {
+			return err
+		}
+		return tx.recordChange(task, userID, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.taskRepo.FindByID(task.ID)
+}
+
+// createTask creates a task in the workspace on behalf of the actor. Top-level
+// tasks belong to the actor, while subtasks belong to their parent's owner so
+// a hierarchy never mixes owners.
+func (s *taskService) createTask(actorID uint, workspaceID *uint, req models.CreateTaskRequest, parent *models.Task) (*models.Task, error) {
+	userID := actorID
+	if parent != nil {
+		userID = parent.UserID
+	}
+
+	timeZone := req.TimeZone
+	if timeZone == "" {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		timeZone = user.TimeZone
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = models.PriorityNone
+	}
+
 	task := &models.Task{
-		Title:   title,
-		Content: content,
-		UserID:  userID,
+		Title:       req.Title,
+		Content:     req.Content,
+		Priority:    priority,
+		AllDay:      req.AllDay,
+		TimeZone:    timeZone,
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+	}
+
+	if err := setDueDate(task, req.DueDate); err != nil {
+		return nil, err
+	}
+
+	if req.RecurrenceRule != "" {
+		if task.DueDate == nil {
+			return nil, ErrRecurrenceNeedsDueDate
+		}
+		rule, err := normalizeRecurrenceRule(req.RecurrenceRule)
+		if err != nil {
+			return nil, err
+		}
+		task.RecurrenceRule = rule
+	}
+
+	tags, err := s.resolveTags(userID, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+	task.Tags = tags
+
+	if task.ListID, err = s.resolveListID(userID, workspaceID, req.ListID); err != nil {
+		return nil, err
+	}
+
+	if parent != nil {
+		task.ParentID = &parent.ID
+	}
+	if task.Position, err = s.taskRepo.NextPosition(task); err != nil {
+		return nil, err
 	}
 
 	if err := s.taskRepo.Create(task); err != nil {
 		return nil, err
 	}
+	if err := s.recordCreation(task, actorID); err != nil {
+		return nil, err
+	}
 	return task, nil
 }
 
 func (s *taskService) GetTask(taskID, userID uint) (*models.Task, error) {
+	return s.AuthorizeTask(taskID, userID, AccessView)
+}
+
+// AuthorizeTask loads the task if the user has at least the given access to
+// it.
+func (s *taskService) AuthorizeTask(taskID, userID uint, level AccessLevel) (*models.Task, error) {
 	task, err := s.taskRepo.FindByID(taskID)
 	if err != nil {
 		return nil, err
 	}
 
-	if task.UserID != userID {
-		return nil, ErrTaskAccessDenied
+	if _, err := s.authorize(task, userID, level); err != nil {
+		return nil, err
 	}
 	return task, nil
 }
 
-func (s *taskService) GetTasksByUser(userID uint) ([]models.Task, error) {
-	return s.taskRepo.FindByUser(userID)
+// authorize checks that the user has at least the given access to the task,
+// returning the access they have.
+func (s *taskService) authorize(task *models.Task, userID uint, level AccessLevel) (AccessLevel, error) {
+	granted, err := s.access.taskAccess(task, userID)
+	if err != nil {
+		return AccessNone, err
+	}
+	if granted < level {
+		return granted, ErrTaskAccessDenied
+	}
+	return granted, nil
 }
 
-func (s *taskService) UpdateTask(taskID, userID uint, title, content string, completed *bool) (*models.Task, error) {
-	task, err := s.GetTask(taskID, userID)
+// ListTasks pages through the user's own tasks, or the tasks assigned to them.
+// When filtering by a list the user can view, it includes every task in that
+// list, whoever owns it.
+func (s *taskService) ListTasks(userID uint, query repository.TaskQuery) (*repository.TaskPage, error) {
+	query.UserID = userID
+	if query.ListID != nil {
+		list, err := s.listRepo.FindByID(*query.ListID)
+		if err != nil && !errors.Is(err, repository.ErrListNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			level, err := s.access.listAccess(list, userID)
+			if err != nil {
+				return nil, err
+			}
+			query.AnyOwner = level >= AccessView
+		}
+	}
+	return s.taskRepo.FindPage(query)
+}
+
+func (s *taskService) GetTaskBuckets(userID uint, workspaceID *uint, now time.Time) (*TaskBuckets, error) {
+	tasks, err := s.taskRepo.FindByUser(userID, workspaceID, repository.TaskSortPriority)
 	if err != nil {
 		return nil, err
 	}
+	return groupTasksByDueDate(tasks, now), nil
+}
 
-	if title != "" {
-		task.Title = title
+func (s *taskService) SearchTasks(userID uint, workspaceID *uint, text string, limit, offset int) ([]repository.TaskSearchResult, error) {
+	return s.taskRepo.Search(userID, workspaceID, text, limit, offset)
+}
+
+func (s *taskService) UpdateTask(taskID, userID uint, req models.UpdateTaskRequest) (*models.Task, error) {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, err
 	}
-	if content != "" || (content == "" && task.Content != "") {
-		task.Content = content
+	level, err := s.authorize(task, userID, AccessAssignee)
+	if err != nil {
+		return nil, err
 	}
-	if completed != nil {
-		task.Completed = *completed
+	if level < AccessEdit {
+		// Assignees without edit access may only complete or reopen the task,
+		// or move it to another status.
+		allowed := models.UpdateTaskRequest{Completed: req.Completed, StatusID: req.StatusID, Force: req.Force}
+		if (req.Completed == nil && req.StatusID == nil) || req != allowed {
+			return nil, ErrTaskAccessDenied
+		}
+		var status *models.TaskStatus
+		if req.StatusID != nil {
+			if status, err = s.resolveStatus(task.ListID, *req.StatusID); err != nil {
+				return nil, err
+			}
+		}
+		err = s.transaction(func(tx *taskService) error {
+			if status != nil {
+				return tx.setStatus(task, status, req.Force, userID)
+			}
+			return tx.setCompleted(task, *req.Completed, req.Force, userID)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return s.taskRepo.FindByID(task.ID)
 	}
+	before := snapshotTask(task)
+	wasCompleted := task.Completed
+	previousListID := task.ListID
 
-	if err := s.taskRepo.Update(task); err != nil {
+	if req.Title != "" {
+		task.Title = req.Title
+	}
+	if req.Content != "" || (req.Content == "" && task.Content != "") {
+		task.Content = req.Content
+	}
+	if req.StatusID != nil {
+		// The status decides whether the task is completed, whatever
+		// completed says.
+		listID := task.ListID
+		if req.ListID != nil {
+			listID = req.ListID
+		}
+		status, err := s.resolveStatus(listID, *req.StatusID)
+		if err != nil {
+			return nil, err
+		}
+		completed := status.IsDone()
+		req.Completed = &completed
+		task.StatusID = &status.ID
+	}
+	if req.Completed != nil {
+		if *req.Completed && !task.Completed && task.Blocked && !req.Force {
+			return nil, ErrTaskBlocked
+		}
+		task.Completed = *req.Completed
+	}
+	if req.Priority != "" {
+		task.Priority = req.Priority
+	}
+	if req.TimeZone != "" {
+		task.TimeZone = req.TimeZone
+	}
+	if req.AllDay != nil {
+		task.AllDay = *req.AllDay
+	}
+
+	dueDate := task.DueDate
+	if req.DueDate != nil {
+		dueDate = req.DueDate
+	}
+	if req.ClearDueDate {
+		dueDate = nil
+	}
+	if err := setDueDate(task, dueDate); err != nil {
+		return nil, err
+	}
+	if task.RecurrenceRule != "" && task.DueDate == nil {
+		return nil, ErrRecurrenceNeedsDueDate
+	}
+
+	if req.ListID != nil {
+		// Like deleting, moving a task out of where it was shared is left to
+		// its owner.
+		if task.UserID != userID && (task.ListID == nil || *req.ListID != *task.ListID) {
+			return nil, ErrTaskAccessDenied
+		}
+		if task.ListID, err = s.resolveListID(task.UserID, task.WorkspaceID, req.ListID); err != nil {
+			return nil, err
+		}
+	}
+
+	var tags []models.Tag
+	if req.TagIDs != nil {
+		if tags, err = s.resolveTags(task.UserID, *req.TagIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.transaction(func(tx *taskService) error {
+		if !sameID(task.ListID, previousListID) {
+			if err := tx.placeLast(task); err != nil {
+				return err
+			}
+		}
+		if err := tx.taskRepo.Update(task); err != nil {
+			return err
+		}
+		if req.TagIDs != nil {
+			if err := tx.taskRepo.ReplaceTags(task, tags); err != nil {
+				return err
+			}
+			task.Tags = tags
+		}
+		if err := tx.recordChange(task, userID, before); err != nil {
+			return err
+		}
+
+		if task.Completed != wasCompleted {
+			if err := tx.cascadeCompletion(task, userID); err != nil {
+				return err
+			}
+		}
+		if task.Completed && !wasCompleted && task.RecurrenceRule != "" {
+			if err := tx.spawnNextOccurrence(task, userID); err != nil {
+				return err
+			}
+		}
+		if task.ListID != nil && (previousListID == nil || *task.ListID != *previousListID) {
+			return tx.moveSubtasksWith(task)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.taskRepo.FindByID(task.ID)
+}
+
+// AssignTask delegates the task to a user who can already see it, such as its
+// owner or someone it is shared with.
+func (s *taskService) AssignTask(taskID, userID, assigneeID uint) (*models.Task, error) {
+	task, err := s.AuthorizeTask(taskID, userID, AccessEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	unassigned := *task
+	unassigned.AssigneeID = nil
+	level, err := s.access.taskAccess(&unassigned, assigneeID)
+	if err != nil {
+		return nil, err
+	}
+	if level < AccessView {
+		return nil, ErrInvalidAssignee
+	}
+
+	return s.setAssignee(task, &assigneeID, userID)
+}
+
+// UnassignTask removes the task's assignee, which editors and the assignee
+// themselves may do.
+func (s *taskService) UnassignTask(taskID, userID uint) (*models.Task, error) {
+	task, err := s.AuthorizeTask(taskID, userID, AccessAssignee)
+	if err != nil {
+		return nil, err
+	}
+	return s.setAssignee(task, nil, userID)
+}
+
+func (s *taskService) setAssignee(task *models.Task, assigneeID *uint, actorID uint) (*models.Task, error) {
+	before := snapshotTask(task)
+	task.AssigneeID = assigneeID
+	err := s.transaction(func(tx *taskService) error {
+		if err := tx.taskRepo.Update(task); err != nil {
+			return err
+		}
+		return tx.recordChange(task, actorID, before)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return task, nil
 }
 
 func (s *taskService) DeleteTask(taskID, userID uint) error {
-	task, err := s.GetTask(taskID, userID)
+	task, err := s.AuthorizeTask(taskID, userID, AccessOwner)
+	if err != nil {
+		return err
+	}
+	return s.transaction(func(tx *taskService) error {
+		if err := tx.taskRepo.Delete(task.ID); err != nil {
+			return err
+		}
+		return tx.recordEvent(task.ID, userID, models.TaskEventDeleted, nil)
+	})
+}
+
+func (s *taskService) GetTrash(userID uint) ([]models.Task, error) {
+	return s.taskRepo.FindTrash(userID)
+}
+
+// RestoreTask brings a task and its subtasks back from the trash. A task whose
+// parent is still trashed (or gone) becomes a top-level task, and one whose
+// list no longer exists goes to the inbox.
+func (s *taskService) RestoreTask(taskID, userID uint) (*models.Task, error) {
+	trashed, err := s.getTrashedTask(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.transaction(func(tx *taskService) error {
+		if err := tx.taskRepo.Restore(trashed.ID); err != nil {
+			return err
+		}
+		task, err := tx.taskRepo.FindByID(trashed.ID)
+		if err != nil {
+			return err
+		}
+		before := snapshotTask(task)
+
+		orphaned := false
+		if task.ParentID != nil {
+			if _, err := tx.taskRepo.FindByID(*task.ParentID); errors.Is(err, repository.ErrTaskNotFound) {
+				task.ParentID, orphaned = nil, true
+			} else if err != nil {
+				return err
+			}
+		}
+
+		listID, err := tx.resolveListID(task.UserID, task.WorkspaceID, task.ListID)
+		if errors.Is(err, ErrInvalidList) {
+			listID, err = tx.resolveListID(task.UserID, task.WorkspaceID, nil)
+		}
+		if err != nil {
+			return err
+		}
+		listChanged := !sameID(listID, task.ListID)
+		task.ListID = listID
+
+		if orphaned || listChanged {
+			if err := tx.placeLast(task); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.taskRepo.Update(task); err != nil {
+			return err
+		}
+		if err := tx.moveSubtasksWith(task); err != nil {
+			return err
+		}
+		return tx.recordEvent(task.ID, userID, models.TaskEventRestored, diffSnapshots(before, snapshotTask(task)))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.taskRepo.FindByID(trashed.ID)
+}
+
+// PurgeTask permanently deletes a trashed task and its subtasks.
+func (s *taskService) PurgeTask(taskID, userID uint) error {
+	task, err := s.getTrashedTask(taskID, userID)
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	err = s.taskRepo.Transaction(func(repo repository.TaskRepository) error {
+		ids, err := repo.FindSubtreeIDs(task.ID)
+		if err != nil {
+			return err
+		}
+		keys, err = repo.Purge(ids)
+		return err
+	})
 	if err != nil {
 		return err
 	}
-	return s.taskRepo.Delete(task.ID)
+	s.deleteBlobs(keys)
+	return nil
+}
+
+// PurgeTrash permanently deletes every task trashed before the given time.
+func (s *taskService) PurgeTrash(deletedBefore time.Time) (int, error) {
+	var purged int
+	var keys []string
+	err := s.taskRepo.Transaction(func(repo repository.TaskRepository) error {
+		ids, err := repo.FindTrashedBefore(deletedBefore)
+		if err != nil {
+			return err
+		}
+		purged = len(ids)
+		keys, err = repo.Purge(ids)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.deleteBlobs(keys)
+	return purged, nil
+}
+
+// deleteBlobs deletes the blobs of purged attachments. It only runs once the
+// purge has committed, so no attachment is ever left pointing at a deleted
+// blob. A blob that fails to delete is merely orphaned, so the failure is
+// logged rather than failing the purge that already happened.
+func (s *taskService) deleteBlobs(keys []string) {
+	for _, key := range keys {
+		if err := s.blobStore.Delete(context.Background(), key); err != nil {
+			logger.Warn().Err(err).Str("key", key).Msg("Failed to delete the blob of a purged attachment")
+		}
+	}
+}
+
+func (s *taskService) getTrashedTask(taskID, userID uint) (*models.Task, error) {
+	task, err := s.taskRepo.FindTrashedByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authorize(task, userID, AccessOwner); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// SetRecurrence makes the task recur, or changes the rule of the series it
+// already belongs to. The new rule applies to every open occurrence.
+func (s *taskService) SetRecurrence(taskID, userID uint, rule string) (*models.Task, error) {
+	task, err := s.AuthorizeTask(taskID, userID, AccessEdit)
+	if err != nil {
+		return nil, err
+	}
+	if task.DueDate == nil {
+		return nil, ErrRecurrenceNeedsDueDate
+	}
+
+	normalized, err := normalizeRecurrenceRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	before := snapshotTask(task)
+	task.RecurrenceRule = normalized
+	err = s.transaction(func(tx *taskService) error {
+		if err := tx.taskRepo.Update(task); err != nil {
+			return err
+		}
+		if err := tx.recordChange(task, userID, before); err != nil {
+			return err
+		}
+		return tx.taskRepo.UpdateSeriesRule(seriesRootID(task), normalized)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.taskRepo.FindByID(task.ID)
+}
+
+// StopRecurrence ends the task's series: no open occurrence will spawn a new
+// one when completed.
+func (s *taskService) StopRecurrence(taskID, userID uint) (*models.Task, error) {
+	task, err := s.AuthorizeTask(taskID, userID, AccessEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	before := snapshotTask(task)
+	task.RecurrenceRule = ""
+	err = s.transaction(func(tx *taskService) error {
+		if err := tx.taskRepo.Update(task); err != nil {
+			return err
+		}
+		if err := tx.recordChange(task, userID, before); err != nil {
+			return err
+		}
+		return tx.taskRepo.UpdateSeriesRule(seriesRootID(task), "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.taskRepo.FindByID(task.ID)
+}
+
+// spawnNextOccurrence creates the task's next occurrence in its series, unless
+// the series has ended or the occurrence already exists (e.g. the task was
+// reopened and completed again).
+func (s *taskService) spawnNextOccurrence(task *models.Task, actorID uint) error {
+	dueDate, rule, ok, err := nextOccurrence(task)
+	if err != nil || !ok {
+		return err
+	}
+
+	rootID := seriesRootID(task)
+	exists, err := s.taskRepo.HasOccurrenceAfter(rootID, *task.DueDate)
+	if err != nil || exists {
+		return err
+	}
+
+	next := &models.Task{
+		Title:          task.Title,
+		Content:        task.Content,
+		Priority:       task.Priority,
+		DueDate:        &dueDate,
+		AllDay:         task.AllDay,
+		TimeZone:       task.TimeZone,
+		UserID:         task.UserID,
+		WorkspaceID:    task.WorkspaceID,
+		ListID:         task.ListID,
+		ParentID:       task.ParentID,
+		RecurrenceRule: rule,
+		SeriesID:       &rootID,
+		AssigneeID:     task.AssigneeID,
+		Tags:           task.Tags,
+	}
+	if next.Position, err = s.taskRepo.NextPosition(next); err != nil {
+		return err
+	}
+	if err := s.taskRepo.Create(next); err != nil {
+		return err
+	}
+	return s.recordCreation(next, actorID)
+}
+
+// cascadeCompletion keeps a task hierarchy consistent after a task's
+// completion changes: completing a task completes every subtask below it, and
+// an open task reopens every task above it.
+func (s *taskService) cascadeCompletion(task *models.Task, actorID uint) error {
+	var ids []uint
+	var err error
+	if task.Completed {
+		ids, err = s.taskRepo.FindDescendantIDs(task.ID)
+	} else {
+		ids, err = s.taskRepo.FindAncestorIDs(task.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	changed, err := s.taskRepo.SetCompleted(ids, task.Completed)
+	if err != nil {
+		return err
+	}
+	return s.recordCompletions(changed, actorID, task.Completed)
+}
+
+// moveSubtasksWith puts every subtask of the task in the task's list.
+func (s *taskService) moveSubtasksWith(task *models.Task) error {
+	ids, err := s.taskRepo.FindDescendantIDs(task.ID)
+	if err != nil {
+		return err
+	}
+	return s.taskRepo.SetListID(ids, *task.ListID)
+}
+
+// resolveTags loads the given tags, making sure every one of them exists and
+// belongs to the user.
+func (s *taskService) resolveTags(userID uint, tagIDs []uint) ([]models.Tag, error) {
+	ids := uniqueIDs(tagIDs)
+	tags, err := s.tagRepo.FindByIDs(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(ids) {
+		return nil, ErrInvalidTags
+	}
+	return tags, nil
+}
+
+// resolveListID checks that the user may add tasks to the list and that it is
+// in the given workspace. With no list given, personal tasks fall back to the
+// user's inbox while workspace tasks are left without a list.
+func (s *taskService) resolveListID(userID uint, workspaceID *uint, listID *uint) (*uint, error) {
+	if listID == nil {
+		if workspaceID != nil {
+			return nil, nil
+		}
+		inbox, err := ensureInbox(s.listRepo, userID)
+		if err != nil {
+			return nil, err
+		}
+		return &inbox.ID, nil
+	}
+
+	list, err := s.findWritableList(userID, *listID)
+	if err != nil {
+		return nil, err
+	}
+	if !sameID(list.WorkspaceID, workspaceID) {
+		return nil, ErrInvalidList
+	}
+	return &list.ID, nil
+}
+
+// resolveStatus loads the status, making sure it belongs to the list.
+func (s *taskService) resolveStatus(listID *uint, statusID uint) (*models.TaskStatus, error) {
+	status, err := s.statusRepo.FindByID(statusID)
+	if err != nil {
+		if errors.Is(err, repository.ErrStatusNotFound) {
+			return nil, ErrInvalidStatus
+		}
+		return nil, err
+	}
+	if listID == nil || status.ListID != *listID {
+		return nil, ErrInvalidStatus
+	}
+	return status, nil
+}
+
+// findWritableList loads the list if the user may add tasks to it.
+func (s *taskService) findWritableList(userID, listID uint) (*models.List, error) {
+	list, err := s.listRepo.FindByID(listID)
+	if err != nil {
+		if errors.Is(err, repository.ErrListNotFound) {
+			return nil, ErrInvalidList
+		}
+		return nil, err
+	}
+	level, err := s.access.listAccess(list, userID)
+	if err != nil {
+		return nil, err
+	}
+	if level < AccessEdit {
+		return nil, ErrInvalidList
+	}
+	return list, nil
+}
+
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+// fakeTaskRepository keeps tasks and recorded events in memory. Only the
+// methods the tests reach are implemented; calling any other panics.
+type fakeTaskRepository struct {
+	repository.TaskRepository
+	tasks   map[uint]*models.Task
+	created []*models.Task
+	events  []models.TaskEvent
+}
+
+func (r *fakeTaskRepository) FindByID(id uint) (*models.Task, error) {
+	task, ok := r.tasks[id]
+	if !ok {
+		return nil, repository.ErrTaskNotFound
+	}
+	copied := *task
+	return &copied, nil
+}
+
+func (r *fakeTaskRepository) FindAncestorIDs(id uint) ([]uint, error) {
+	return nil, nil
+}
+
+func (r *fakeTaskRepository) Create(task *models.Task) error {
+	task.ID = uint(100 + len(r.created))
+	r.created = append(r.created, task)
+	return nil
+}
+
+func (r *fakeTaskRepository) NextPosition(task *models.Task) (float64, error) {
+	return 1, nil
+}
+
+func (r *fakeTaskRepository) HasOccurrenceAfter(rootID uint, dueDate time.Time) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeTaskRepository) RecordEvents(events ...models.TaskEvent) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func TestSpawnNextOccurrenceKeepsWorkspace(t *testing.T) {
+	repo := &fakeTaskRepository{}
+	s := &taskService{taskRepo: repo}
+
+	workspaceID, listID := uint(7), uint(3)
+	due := time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC)
+	task := &models.Task{
+		Title:          "Water the office plants",
+		DueDate:        &due,
+		AllDay:         true,
+		Completed:      true,
+		UserID:         1,
+		WorkspaceID:    &workspaceID,
+		ListID:         &listID,
+		RecurrenceRule: "FREQ=WEEKLY",
+	}
+	task.ID = 10
+
+	if err := s.spawnNextOccurrence(task, 2); err != nil {
+		t.Fatalf("spawnNextOccurrence: %v", err)
+	}
+
+	if len(repo.created) != 1 {
+		t.Fatalf("created %d tasks, want the next occurrence", len(repo.created))
+	}
+	next := repo.created[0]
+	if next.WorkspaceID == nil || *next.WorkspaceID != workspaceID {
+		t.Errorf("next occurrence is in workspace %v, want %d", next.WorkspaceID, workspaceID)
+	}
+	if next.ListID == nil || *next.ListID != listID {
+		t.Errorf("next occurrence is in list %v, want %d", next.ListID, listID)
+	}
+	if want := due.AddDate(0, 0, 7); next.DueDate == nil || !next.DueDate.Equal(want) {
+		t.Errorf("next occurrence is due %v, want %v", next.DueDate, want)
+	}
+	if next.SeriesID == nil || *next.SeriesID != task.ID {
+		t.Errorf("next occurrence is in series %v, want %d", next.SeriesID, task.ID)
+	}
+}
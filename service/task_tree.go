@@ -0,0 +1,136 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/RLRama/listario-backend/models"
+)
+
+// TaskTree is a task to create along with its subtasks, in order. Tags are
+// given by name and created for the tasks' owner when missing. Completed
+// tasks are created as they are, without completing their subtasks or
+// spawning another occurrence.
+type TaskTree struct {
+	Task      models.CreateTaskRequest
+	TagNames  []string
+	Completed bool
+	Subtasks  []TaskTree
+}
+
+// CreateTaskTree creates the whole tree within a single transaction, so it is
+// either created in full or not at all. Subtasks land in their top-level
+// task's list, like those added with CreateSubtask.
+func (s *taskService) CreateTaskTree(userID uint, workspaceID *uint, tree TaskTree) (*models.Task, error) {
+	roots, err := s.CreateTaskTrees(userID, workspaceID, []TaskTree{tree})
+	if err != nil {
+		return nil, err
+	}
+	return &roots[0], nil
+}
+
+// CreateTaskTrees creates several trees like CreateTaskTree, all within the
+// same transaction, and returns their top-level tasks.
+func (s *taskService) CreateTaskTrees(userID uint, workspaceID *uint, trees []TaskTree) ([]models.Task, error) {
+	level, err := s.access.workspaceAccess(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceID != nil && level < AccessEdit {
+		return nil, ErrWorkspaceAccessDenied
+	}
+
+	rootIDs := make([]uint, len(trees))
+	err = s.transaction(func(tx *taskService) error {
+		// Missing tags are created in the transaction too, so a failed
+		// import leaves none behind.
+		tagIDs, err := tx.resolveTreeTags(userID, trees)
+		if err != nil {
+			return err
+		}
+
+		for i, tree := range trees {
+			root, err := tx.createTaskTree(userID, workspaceID, tree, tagIDs, nil)
+			if err != nil {
+				return err
+			}
+			rootIDs[i] = root.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roots := make([]models.Task, len(rootIDs))
+	for i, id := range rootIDs {
+		root, err := s.taskRepo.FindByID(id)
+		if err != nil {
+			return nil, err
+		}
+		roots[i] = *root
+	}
+	return roots, nil
+}
+
+func (s *taskService) createTaskTree(userID uint, workspaceID *uint, tree TaskTree, tagIDs map[string]uint, parent *models.Task) (*models.Task, error) {
+	req := tree.Task
+	for _, name := range tree.TagNames {
+		req.TagIDs = append(req.TagIDs, tagIDs[strings.ToLower(name)])
+	}
+	if parent != nil {
+		req.ListID = parent.ListID
+	}
+
+	task, err := s.createTask(userID, workspaceID, req, parent)
+	if err != nil {
+		return nil, err
+	}
+	if tree.Completed {
+		before := snapshotTask(task)
+		task.Completed = true
+		if err := s.taskRepo.Update(task); err != nil {
+			return nil, err
+		}
+		if err := s.recordChange(task, userID, before); err != nil {
+			return nil, err
+		}
+	}
+	for _, subtree := range tree.Subtasks {
+		if _, err := s.createTaskTree(userID, workspaceID, subtree, tagIDs, task); err != nil {
+			return nil, err
+		}
+	}
+	return task, nil
+}
+
+// resolveTreeTags returns the IDs of the tags named anywhere in the trees,
+// keyed by their lower-cased names.
+func (s *taskService) resolveTreeTags(userID uint, trees []TaskTree) (map[string]uint, error) {
+	var names []string
+	seen := make(map[string]bool)
+	var collect func(tree TaskTree)
+	collect = func(tree TaskTree) {
+		for _, name := range tree.TagNames {
+			if key := strings.ToLower(name); !seen[key] {
+				seen[key] = true
+				names = append(names, name)
+			}
+		}
+		for _, subtree := range tree.Subtasks {
+			collect(subtree)
+		}
+	}
+	for _, tree := range trees {
+		collect(tree)
+	}
+
+	ids, err := s.resolveTagNames(userID, names)
+	if err != nil {
+		return nil, err
+	}
+	tagIDs := make(map[string]uint, len(names))
+	for i, name := range names {
+		tagIDs[strings.ToLower(name)] = ids[i]
+	}
+	return tagIDs, nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+var (
+	ErrTemplateAccessDenied = errors.New("access to the requested template is denied")
+)
+
+// TemplateService manages task templates. A template is saved from an
+// existing task and its subtasks, and lives in the active workspace or the
+// user's personal space. Members of a workspace can see and use its templates,
+// while only their creator or the workspace's admins may delete them.
+type TemplateService interface {
+	SaveTemplate(taskID, userID uint, workspaceID *uint, req models.SaveTemplateRequest) (*models.TaskTemplate, error)
+	GetTemplates(userID uint, workspaceID *uint) ([]models.TaskTemplate, error)
+	GetTemplate(templateID, userID uint) (*models.TaskTemplate, error)
+	DeleteTemplate(templateID, userID uint) error
+	// InstantiateTemplate creates the template's task tree in the template's
+	// workspace or the user's personal space, returning its top-level task.
+	InstantiateTemplate(templateID, userID uint, req models.InstantiateTemplateRequest, now time.Time) (*models.Task, error)
+}
+
+type templateService struct {
+	templateRepo repository.TemplateRepository
+	userRepo     repository.UserRepository
+	taskService  TaskService
+	access       *accessPolicy
+}
+
+func NewTemplateService(templateRepo repository.TemplateRepository, userRepo repository.UserRepository, taskRepo repository.TaskRepository, listRepo repository.ListRepository, shareRepo repository.ShareRepository, workspaceRepo repository.WorkspaceRepository, taskService TaskService) TemplateService {
+	return &templateService{
+		templateRepo: templateRepo,
+		userRepo:     userRepo,
+		taskService:  taskService,
+		access:       newAccessPolicy(taskRepo, listRepo, shareRepo, workspaceRepo),
+	}
+}
+
+// SaveTemplate captures the task, with its subtasks, as a template. Due dates
+// become offsets from when the task was created.
+func (s *templateService) SaveTemplate(taskID, userID uint, workspaceID *uint, req models.SaveTemplateRequest) (*models.TaskTemplate, error) {
+	level, err := s.access.workspaceAccess(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceID != nil && level < AccessEdit {
+		return nil, ErrWorkspaceAccessDenied
+	}
+
+	task, err := s.taskService.GetTask(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	root, err := s.templateTask(task, userID, task.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &models.TaskTemplate{
+		Name:        req.Name,
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		Root:        *root,
+	}
+	if err := s.templateRepo.Create(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// templateTask captures the task and its subtasks, with due dates relative to
+// start.
+func (s *templateService) templateTask(task *models.Task, userID uint, start time.Time) (*models.TemplateTask, error) {
+	node := &models.TemplateTask{
+		Title:    task.Title,
+		Content:  task.Content,
+		Priority: task.Priority,
+		AllDay:   task.AllDay,
+		Tags:     make([]string, len(task.Tags)),
+	}
+	for i, tag := range task.Tags {
+		node.Tags[i] = tag.Name
+	}
+	if task.DueDate != nil {
+		offset := int(task.DueDate.Sub(dueOrigin(task.AllDay, start, task.TimeZone)).Minutes())
+		node.DueOffsetMinutes = &offset
+	}
+
+	subtasks, err := s.taskService.GetSubtasks(task.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subtasks {
+		subtask, err := s.templateTask(&subtasks[i], userID, start)
+		if err != nil {
+			return nil, err
+		}
+		node.Subtasks = append(node.Subtasks, *subtask)
+	}
+	return node, nil
+}
+
+// dueOrigin is the moment due offsets count from: start itself for timed
+// tasks, and the start of its day in the time zone for all-day ones, kept as
+// midnight UTC like all-day due dates are.
+func dueOrigin(allDay bool, start time.Time, timeZone string) time.Time {
+	if !allDay {
+		return start
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	year, month, day := start.In(location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *templateService) GetTemplates(userID uint, workspaceID *uint) ([]models.TaskTemplate, error) {
+	if workspaceID != nil {
+		return s.templateRepo.FindByWorkspace(*workspaceID)
+	}
+	return s.templateRepo.FindByUser(userID)
+}
+
+func (s *templateService) GetTemplate(templateID, userID uint) (*models.TaskTemplate, error) {
+	return s.authorizeTemplate(templateID, userID, AccessView)
+}
+
+func (s *templateService) DeleteTemplate(templateID, userID uint) error {
+	template, err := s.authorizeTemplate(templateID, userID, AccessOwner)
+	if err != nil {
+		return err
+	}
+	return s.templateRepo.Delete(template.ID)
+}
+
+func (s *templateService) InstantiateTemplate(templateID, userID uint, req models.InstantiateTemplateRequest, now time.Time) (*models.Task, error) {
+	template, err := s.authorizeTemplate(templateID, userID, AccessView)
+	if err != nil {
+		return nil, err
+	}
+
+	timeZone := req.TimeZone
+	if timeZone == "" {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		timeZone = user.TimeZone
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return nil, ErrInvalidTimeZone
+	}
+	start := now
+	if req.StartAt != nil {
+		start = *req.StartAt
+	}
+
+	tree := taskTree(template.Root, start, timeZone)
+	if req.Title != "" {
+		tree.Task.Title = req.Title
+	}
+	tree.Task.ListID = req.ListID
+	return s.taskService.CreateTaskTree(userID, template.WorkspaceID, tree)
+}
+
+// taskTree turns the template task and its subtasks into tasks to create, with
+// due dates counted from start.
+func taskTree(node models.TemplateTask, start time.Time, timeZone string) TaskTree {
+	tree := TaskTree{
+		Task: models.CreateTaskRequest{
+			Title:    node.Title,
+			Content:  node.Content,
+			Priority: node.Priority,
+			AllDay:   node.AllDay,
+			TimeZone: timeZone,
+		},
+		TagNames: node.Tags,
+	}
+	if node.DueOffsetMinutes != nil {
+		dueDate := dueOrigin(node.AllDay, start, timeZone).Add(time.Duration(*node.DueOffsetMinutes) * time.Minute)
+		tree.Task.DueDate = &dueDate
+	}
+	for _, subtask := range node.Subtasks {
+		tree.Subtasks = append(tree.Subtasks, taskTree(subtask, start, timeZone))
+	}
+	return tree
+}
+
+// authorizeTemplate loads the template if the user has at least the given
+// access to it.
+func (s *templateService) authorizeTemplate(templateID, userID uint, level AccessLevel) (*models.TaskTemplate, error) {
+	template, err := s.templateRepo.FindByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := AccessOwner
+	if template.UserID != userID {
+		if granted, err = s.access.workspaceAccess(template.WorkspaceID, userID); err != nil {
+			return nil, err
+		}
+	}
+	if granted < level {
+		return nil, ErrTemplateAccessDenied
+	}
+	return template, nil
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+var (
+	ErrTimeEntryAccessDenied = errors.New("access to the requested time entry is denied")
+	ErrInvalidTimeEntry      = errors.New("time entries must end after they start, and not in the future")
+	ErrNoRunningTimer        = errors.New("no timer is running on this task")
+	ErrInvalidReportPeriod   = errors.New("reports must start on or before their last day and cover at most 366 days")
+)
+
+// maxReportDays caps how many days a time report covers.
+const maxReportDays = 366
+
+// TimeEntryService tracks the time users spend on tasks, with timers or
+// entries added afterwards. Anyone who may complete a task can track time on
+// it and anyone who can see it can see the time tracked, while only the user
+// who tracked an entry may change or delete it.
+type TimeEntryService interface {
+	// StartTimer starts a timer on the task, stopping the user's running
+	// timer on any other task. A timer already running on the task is kept.
+	StartTimer(taskID, userID uint, req models.StartTimerRequest, now time.Time) (*models.TimeEntry, error)
+	StopTimer(taskID, userID uint, now time.Time) (*models.TimeEntry, error)
+	GetRunningTimer(userID uint) (*models.TimeEntry, error)
+	CreateTimeEntry(taskID, userID uint, req models.CreateTimeEntryRequest, now time.Time) (*models.TimeEntry, error)
+	GetTimeEntries(taskID, userID uint) ([]models.TimeEntry, error)
+	UpdateTimeEntry(entryID, userID uint, req models.UpdateTimeEntryRequest, now time.Time) (*models.TimeEntry, error)
+	DeleteTimeEntry(entryID, userID uint) error
+	// GetTimeReport totals the user's tracked time on each day from the first
+	// to the last one, inclusive, as calendar days in the time zone or the
+	// user's own. The period defaults to the week ending today.
+	GetTimeReport(userID uint, first, last *time.Time, timeZone string, now time.Time) (*TimeReport, error)
+}
+
+// TimeReport is the time a user tracked per day over a period, broken down by
+// task.
+type TimeReport struct {
+	First    time.Time
+	Last     time.Time
+	TimeZone string
+	Total    time.Duration
+	Days     []TimeReportDay
+}
+
+type TimeReportDay struct {
+	Date  time.Time
+	Total time.Duration
+	Tasks []TimeReportTask
+}
+
+type TimeReportTask struct {
+	Task  models.Task
+	Total time.Duration
+}
+
+type timeEntryService struct {
+	timeEntryRepo repository.TimeEntryRepository
+	userRepo      repository.UserRepository
+	taskService   TaskService
+}
+
+func NewTimeEntryService(timeEntryRepo repository.TimeEntryRepository, userRepo repository.UserRepository, taskService TaskService) TimeEntryService {
+	return &timeEntryService{
+		timeEntryRepo: timeEntryRepo,
+		userRepo:      userRepo,
+		taskService:   taskService,
+	}
+}
+
+func (s *timeEntryService) StartTimer(taskID, userID uint, req models.StartTimerRequest, now time.Time) (*models.TimeEntry, error) {
+	task, err := s.taskService.AuthorizeTask(taskID, userID, AccessAssignee)
+	if err != nil {
+		return nil, err
+	}
+
+	running, err := s.timeEntryRepo.FindRunning(userID)
+	if err != nil && !errors.Is(err, repository.ErrTimeEntryNotFound) {
+		return nil, err
+	}
+	if err == nil && running.TaskID == task.ID {
+		return running, nil
+	}
+
+	entry := &models.TimeEntry{
+		TaskID:    task.ID,
+		UserID:    userID,
+		StartedAt: now,
+		Note:      req.Note,
+	}
+	if err := s.timeEntryRepo.StartTimer(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// StopTimer stops the user's timer on the task. Since the timer is the user's
+// own, it can be stopped even once they can no longer see the task.
+func (s *timeEntryService) StopTimer(taskID, userID uint, now time.Time) (*models.TimeEntry, error) {
+	entry, err := s.timeEntryRepo.FindRunning(userID)
+	if errors.Is(err, repository.ErrTimeEntryNotFound) {
+		return nil, ErrNoRunningTimer
+	}
+	if err != nil {
+		return nil, err
+	}
+	if entry.TaskID != taskID {
+		return nil, ErrNoRunningTimer
+	}
+
+	entry.EndedAt = &now
+	if err := s.timeEntryRepo.Update(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *timeEntryService) GetRunningTimer(userID uint) (*models.TimeEntry, error) {
+	return s.timeEntryRepo.FindRunning(userID)
+}
+
+func (s *timeEntryService) CreateTimeEntry(taskID, userID uint, req models.CreateTimeEntryRequest, now time.Time) (*models.TimeEntry, error) {
+	task, err := s.taskService.AuthorizeTask(taskID, userID, AccessAssignee)
+	if err != nil {
+		return nil, err
+	}
+	if !req.EndedAt.After(req.StartedAt) || req.EndedAt.After(now) {
+		return nil, ErrInvalidTimeEntry
+	}
+
+	entry := &models.TimeEntry{
+		TaskID:    task.ID,
+		UserID:    userID,
+		StartedAt: req.StartedAt,
+		EndedAt:   &req.EndedAt,
+		Note:      req.Note,
+	}
+	if err := s.timeEntryRepo.Create(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *timeEntryService) GetTimeEntries(taskID, userID uint) ([]models.TimeEntry, error) {
+	task, err := s.taskService.GetTask(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.timeEntryRepo.FindByTask(task.ID)
+}
+
+// UpdateTimeEntry changes one of the user's entries. Setting the end of a
+// running timer stops it.
+func (s *timeEntryService) UpdateTimeEntry(entryID, userID uint, req models.UpdateTimeEntryRequest, now time.Time) (*models.TimeEntry, error) {
+	entry, err := s.timeEntryRepo.FindByID(entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.UserID != userID {
+		return nil, ErrTimeEntryAccessDenied
+	}
+
+	if req.StartedAt != nil {
+		entry.StartedAt = *req.StartedAt
+	}
+	if req.EndedAt != nil {
+		entry.EndedAt = req.EndedAt
+	}
+	if req.Note != nil {
+		entry.Note = *req.Note
+	}
+
+	if entry.EndedAt == nil {
+		if entry.StartedAt.After(now) {
+			return nil, ErrInvalidTimeEntry
+		}
+	} else if !entry.EndedAt.After(entry.StartedAt) || entry.EndedAt.After(now) {
+		return nil, ErrInvalidTimeEntry
+	}
+
+	if err := s.timeEntryRepo.Update(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *timeEntryService) DeleteTimeEntry(entryID, userID uint) error {
+	entry, err := s.timeEntryRepo.FindByID(entryID)
+	if err != nil {
+		return err
+	}
+	if entry.UserID != userID {
+		return ErrTimeEntryAccessDenied
+	}
+	return s.timeEntryRepo.Delete(entry.ID)
+}
+
+func (s *timeEntryService) GetTimeReport(userID uint, first, last *time.Time, timeZone string, now time.Time) (*TimeReport, error) {
+	if timeZone == "" {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		timeZone = user.TimeZone
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, ErrInvalidTimeZone
+	}
+
+	// Days are counted in the report's time zone, so they may be 23 or 25
+	// hours long around daylight saving changes.
+	year, month, day := now.In(location).Date()
+	if last != nil {
+		year, month, day = last.Date()
+	}
+	to := time.Date(year, month, day+1, 0, 0, 0, 0, location)
+	from := to.AddDate(0, 0, -7)
+	if first != nil {
+		from = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, location)
+	}
+	if !to.After(from) || to.After(from.AddDate(0, 0, maxReportDays)) {
+		return nil, ErrInvalidReportPeriod
+	}
+
+	entries, err := s.timeEntryRepo.FindByUser(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TimeReport{First: from, Last: to.AddDate(0, 0, -1), TimeZone: timeZone}
+	for dayStart := from; dayStart.Before(to); dayStart = dayStart.AddDate(0, 0, 1) {
+		dayEnd := dayStart.AddDate(0, 0, 1)
+		day := TimeReportDay{Date: dayStart}
+		for _, entry := range entries {
+			spent := overlap(entry, dayStart, dayEnd, now)
+			if spent <= 0 {
+				continue
+			}
+			day.Total += spent
+			day.Tasks = addTaskTime(day.Tasks, entry.Task, spent)
+		}
+		report.Total += day.Total
+		report.Days = append(report.Days, day)
+	}
+	return report, nil
+}
+
+// overlap is how much of the entry falls between from and to, counting a
+// running entry up to now.
+func overlap(entry models.TimeEntry, from, to, now time.Time) time.Duration {
+	end := now
+	if entry.EndedAt != nil {
+		end = *entry.EndedAt
+	}
+	start := entry.StartedAt
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	return end.Sub(start)
+}
+
+func addTaskTime(tasks []TimeReportTask, task models.Task, spent time.Duration) []TimeReportTask {
+	for i := range tasks {
+		if tasks[i].Task.ID == task.ID {
+			tasks[i].Total += spent
+			return tasks
+		}
+	}
+	return append(tasks, TimeReportTask{Task: task, Total: spent})
+}
@@ -16,43 +16,57 @@ var (
 )
 
 type UserService interface {
-	Register(username, email, password string) (*models.User, error)
+	Register(username, email, password, timeZone string) (*models.User, error)
 	Login(email, password string) (jwt.TokenPair, error)
 	RefreshToken(userID uint) (jwt.TokenPair, error)
+	SwitchWorkspace(userID uint, workspaceID *uint) (jwt.TokenPair, error)
 	GetUserDetails(userID uint) (*models.User, error)
-	UpdateUserDetails(userID uint, username, email string) (*models.User, error)
+	UpdateUserDetails(userID uint, username, email, timeZone string) (*models.User, error)
 }
 
 type userService struct {
 	userRepo           repository.UserRepository
+	listRepo           repository.ListRepository
+	workspaceRepo      repository.WorkspaceRepository
 	signer             *jwt.Signer
 	refreshTokenMaxAge time.Duration
 }
 
-func NewUserService(repo repository.UserRepository, signer *jwt.Signer, refreshTokenMaxAge time.Duration) UserService {
+func NewUserService(repo repository.UserRepository, listRepo repository.ListRepository, workspaceRepo repository.WorkspaceRepository, signer *jwt.Signer, refreshTokenMaxAge time.Duration) UserService {
 	return &userService{
 		userRepo:           repo,
+		listRepo:           listRepo,
+		workspaceRepo:      workspaceRepo,
 		signer:             signer,
 		refreshTokenMaxAge: refreshTokenMaxAge,
 	}
 }
 
-func (s *userService) Register(username, email, password string) (*models.User, error) {
+func (s *userService) Register(username, email, password, timeZone string) (*models.User, error) {
 	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
 		return nil, err
 	}
 
+	if timeZone == "" {
+		timeZone = defaultTimeZone
+	}
+
 	user := &models.User{
 		Username: username,
 		Email:    email,
 		Password: hashedPassword,
+		TimeZone: timeZone,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
 		return nil, err
 	}
 
+	if _, err := ensureInbox(s.listRepo, user.ID); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
@@ -69,19 +83,43 @@ func (s *userService) Login(email, password string) (jwt.TokenPair, error) {
 		return jwt.TokenPair{}, ErrInvalidCredentials
 	}
 
-	return s.generateTokenPair(user.ID)
+	return s.generateTokenPair(user)
 }
 
 func (s *userService) RefreshToken(userID uint) (jwt.TokenPair, error) {
-	if _, err := s.userRepo.FindByID(userID); err != nil {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return jwt.TokenPair{}, err
+	}
+	return s.generateTokenPair(user)
+}
+
+// SwitchWorkspace makes the workspace the user's active one, or their personal
+// space when workspaceID is nil, and issues tokens for it.
+func (s *userService) SwitchWorkspace(userID uint, workspaceID *uint) (jwt.TokenPair, error) {
+	if workspaceID != nil {
+		if _, err := s.workspaceRepo.FindMember(*workspaceID, userID); err != nil {
+			if errors.Is(err, repository.ErrMemberNotFound) {
+				return jwt.TokenPair{}, ErrWorkspaceAccessDenied
+			}
+			return jwt.TokenPair{}, err
+		}
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
 		return jwt.TokenPair{}, err
 	}
-	return s.generateTokenPair(userID)
+	user.ActiveWorkspaceID = workspaceID
+	if err := s.userRepo.Update(user); err != nil {
+		return jwt.TokenPair{}, err
+	}
+	return s.generateTokenPair(user)
 }
 
-func (s *userService) generateTokenPair(userID uint) (jwt.TokenPair, error) {
-	accessClaims := models.UserClaims{UserID: userID}
-	refreshClaims := jwt.Claims{Subject: strconv.FormatUint(uint64(userID), 10)}
+func (s *userService) generateTokenPair(user *models.User) (jwt.TokenPair, error) {
+	accessClaims := models.UserClaims{UserID: user.ID, WorkspaceID: user.ActiveWorkspaceID}
+	refreshClaims := jwt.Claims{Subject: strconv.FormatUint(uint64(user.ID), 10)}
 
 	return s.signer.NewTokenPair(accessClaims, refreshClaims, s.refreshTokenMaxAge)
 }
@@ -90,7 +128,7 @@ func (s *userService) GetUserDetails(userID uint) (*models.User, error) {
 	return s.userRepo.FindByID(userID)
 }
 
-func (s *userService) UpdateUserDetails(userID uint, username, email string) (*models.User, error) {
+func (s *userService) UpdateUserDetails(userID uint, username, email, timeZone string) (*models.User, error) {
 	if email != "" {
 		existingUser, err := s.userRepo.FindByEmail(email)
 		if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
@@ -112,6 +150,9 @@ func (s *userService) UpdateUserDetails(userID uint, username, email string) (*m
 	if email != "" {
 		user.Email = email
 	}
+	if timeZone != "" {
+		user.TimeZone = timeZone
+	}
 
 	if err := s.userRepo.Update(user); err != nil {
 		return nil, err
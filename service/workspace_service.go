@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/models"
+	"github.com/RLRama/listario-backend/notify"
+	"github.com/RLRama/listario-backend/repository"
+)
+
+const (
+	// InvitationTTL is how long an invitation can be accepted for after it is
+	// sent.
+	InvitationTTL = 7 * 24 * time.Hour
+
+	invitationEmailTimeout = 30 * time.Second
+)
+
+var (
+	ErrWorkspaceAccessDenied = errors.New("access to the requested workspace is denied")
+	ErrCannotChangeOwner     = errors.New("the workspace owner's membership cannot be changed")
+	ErrInvitationExpired     = errors.New("invitation has expired")
+)
+
+// WorkspaceService manages workspaces, their members and invitations. Any
+// member can see the workspace and who is in it; its owner and admins manage
+// members and invitations, but only the owner can make or manage admins.
+type WorkspaceService interface {
+	CreateWorkspace(userID uint, req models.CreateWorkspaceRequest) (*models.WorkspaceMember, error)
+	GetWorkspaces(userID uint) ([]models.WorkspaceMember, error)
+	GetWorkspace(workspaceID, userID uint) (*models.WorkspaceMember, error)
+	UpdateWorkspace(workspaceID, userID uint, req models.UpdateWorkspaceRequest) (*models.WorkspaceMember, error)
+
+	GetMembers(workspaceID, userID uint) ([]models.WorkspaceMember, error)
+	UpdateMember(workspaceID, userID, memberID uint, req models.UpdateMemberRequest) (*models.WorkspaceMember, error)
+	RemoveMember(workspaceID, userID, memberID uint) error
+
+	InviteMember(workspaceID, userID uint, req models.InviteMemberRequest) (*models.WorkspaceInvitation, error)
+	GetInvitations(workspaceID, userID uint) ([]models.WorkspaceInvitation, error)
+	RevokeInvitation(workspaceID, userID, invitationID uint) error
+	GetMyInvitations(userID uint) ([]models.WorkspaceInvitation, error)
+	AcceptInvitation(token string, userID uint) (*models.WorkspaceMember, error)
+	DeclineInvitation(token string, userID uint) error
+}
+
+type workspaceService struct {
+	workspaceRepo  repository.WorkspaceRepository
+	invitationRepo repository.InvitationRepository
+	userRepo       repository.UserRepository
+	mailer         notify.Notifier
+}
+
+// NewWorkspaceService creates the service. Invitations are emailed through
+// mailer, which may be nil when email isn't set up; invitees can then only
+// find them under GET /invitations once they sign up with the invited
+// address.
+func NewWorkspaceService(workspaceRepo repository.WorkspaceRepository, invitationRepo repository.InvitationRepository, userRepo repository.UserRepository, mailer notify.Notifier) WorkspaceService {
+	return &workspaceService{
+		workspaceRepo:  workspaceRepo,
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		mailer:         mailer,
+	}
+}
+
+func (s *workspaceService) CreateWorkspace(userID uint, req models.CreateWorkspaceRequest) (*models.WorkspaceMember, error) {
+	workspace := &models.Workspace{
+		Name:    req.Name,
+		OwnerID: userID,
+	}
+	if err := s.workspaceRepo.Create(workspace); err != nil {
+		return nil, err
+	}
+	return s.GetWorkspace(workspace.ID, userID)
+}
+
+func (s *workspaceService) GetWorkspaces(userID uint) ([]models.WorkspaceMember, error) {
+	return s.workspaceRepo.FindMemberships(userID)
+}
+
+// GetWorkspace returns the user's membership of the workspace, along with the
+// workspace itself.
+func (s *workspaceService) GetWorkspace(workspaceID, userID uint) (*models.WorkspaceMember, error) {
+	workspace, err := s.workspaceRepo.FindByID(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.workspaceRepo.FindMember(workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMemberNotFound) {
+			return nil, ErrWorkspaceAccessDenied
+		}
+		return nil, err
+	}
+	member.Workspace = *workspace
+	return member, nil
+}
+
+func (s *workspaceService) UpdateWorkspace(workspaceID, userID uint, req models.UpdateWorkspaceRequest) (*models.WorkspaceMember, error) {
+	member, err := s.getManager(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	member.Workspace.Name = req.Name
+	if err := s.workspaceRepo.Update(&member.Workspace); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *workspaceService) GetMembers(workspaceID, userID uint) ([]models.WorkspaceMember, error) {
+	if _, err := s.GetWorkspace(workspaceID, userID); err != nil {
+		return nil, err
+	}
+	return s.workspaceRepo.FindMembers(workspaceID)
+}
+
+func (s *workspaceService) UpdateMember(workspaceID, userID, memberID uint, req models.UpdateMemberRequest) (*models.WorkspaceMember, error) {
+	manager, err := s.getManager(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.workspaceRepo.FindMember(workspaceID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCanManage(manager, member.Role, req.Role); err != nil {
+		return nil, err
+	}
+
+	member.Role = req.Role
+	if err := s.workspaceRepo.UpdateMember(member); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(member.UserID)
+	if err != nil {
+		return nil, err
+	}
+	member.User = *user
+	return member, nil
+}
+
+// RemoveMember removes the member from the workspace. Members other than the
+// owner may also remove themselves to leave it.
+func (s *workspaceService) RemoveMember(workspaceID, userID, memberID uint) error {
+	if memberID == userID {
+		member, err := s.GetWorkspace(workspaceID, userID)
+		if err != nil {
+			return err
+		}
+		if member.Role == models.WorkspaceRoleOwner {
+			return ErrCannotChangeOwner
+		}
+		return s.workspaceRepo.RemoveMember(workspaceID, userID)
+	}
+
+	manager, err := s.getManager(workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	member, err := s.workspaceRepo.FindMember(workspaceID, memberID)
+	if err != nil {
+		return err
+	}
+	if err := checkCanManage(manager, member.Role, ""); err != nil {
+		return err
+	}
+	return s.workspaceRepo.RemoveMember(workspaceID, memberID)
+}
+
+// InviteMember invites the email to the workspace, renewing the pending
+// invitation if there already is one.
+func (s *workspaceService) InviteMember(workspaceID, userID uint, req models.InviteMemberRequest) (*models.WorkspaceInvitation, error) {
+	manager, err := s.getManager(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCanManage(manager, "", req.Role); err != nil {
+		return nil, err
+	}
+
+	invitee, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
+	}
+	if invitee != nil {
+		_, err := s.workspaceRepo.FindMember(workspaceID, invitee.ID)
+		if err == nil {
+			return nil, repository.ErrAlreadyMember
+		}
+		if !errors.Is(err, repository.ErrMemberNotFound) {
+			return nil, err
+		}
+	}
+
+	token, err := newInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.invitationRepo.FindPending(workspaceID, req.Email)
+	if err != nil && !errors.Is(err, repository.ErrInvitationNotFound) {
+		return nil, err
+	}
+	if invitation == nil {
+		invitation = &models.WorkspaceInvitation{
+			WorkspaceID: workspaceID,
+			Email:       req.Email,
+			Status:      models.InvitationPending,
+		}
+	}
+	invitation.Role = req.Role
+	invitation.Token = token
+	invitation.InvitedByID = userID
+	invitation.ExpiresAt = time.Now().Add(InvitationTTL)
+
+	if invitation.ID == 0 {
+		err = s.invitationRepo.Create(invitation)
+	} else {
+		err = s.invitationRepo.Update(invitation)
+	}
+	if err != nil {
+		return nil, err
+	}
+	invitation.Workspace = manager.Workspace
+
+	// The invitation stands even if the email can't be sent, since the
+	// invitee can still find it once signed up.
+	if err := s.sendInvitation(invitation, userID); err != nil {
+		logger.Warn().Err(err).Uint("invitationID", invitation.ID).Msg("Failed to email invitation")
+	}
+	return invitation, nil
+}
+
+// sendInvitation emails the invitation to the invitee, if email is set up.
+func (s *workspaceService) sendInvitation(invitation *models.WorkspaceInvitation, inviterID uint) error {
+	if s.mailer == nil {
+		return nil
+	}
+	inviter, err := s.userRepo.FindByID(inviterID)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("%s invited you to join the workspace %q on Listario as %s.\n\n"+
+		"Sign up or log in with this email address to see the invitation, or accept it with this token:\n\n%s\n\n"+
+		"The invitation expires on %s.\n",
+		inviter.Username, invitation.Workspace.Name, invitation.Role, invitation.Token,
+		invitation.ExpiresAt.UTC().Format("January 2, 2006 at 15:04 UTC"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), invitationEmailTimeout)
+	defer cancel()
+	return s.mailer.Notify(ctx, notify.Message{
+		Email:   invitation.Email,
+		Subject: fmt.Sprintf("You're invited to %s on Listario", invitation.Workspace.Name),
+		Body:    body,
+	})
+}
+
+func (s *workspaceService) GetInvitations(workspaceID, userID uint) ([]models.WorkspaceInvitation, error) {
+	if _, err := s.getManager(workspaceID, userID); err != nil {
+		return nil, err
+	}
+	return s.invitationRepo.FindPendingByWorkspace(workspaceID, time.Now())
+}
+
+func (s *workspaceService) RevokeInvitation(workspaceID, userID, invitationID uint) error {
+	if _, err := s.getManager(workspaceID, userID); err != nil {
+		return err
+	}
+	invitation, err := s.invitationRepo.FindByID(invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.WorkspaceID != workspaceID || invitation.Status != models.InvitationPending {
+		return repository.ErrInvitationNotFound
+	}
+	return s.invitationRepo.Delete(invitation.ID)
+}
+
+func (s *workspaceService) GetMyInvitations(userID uint) ([]models.WorkspaceInvitation, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.invitationRepo.FindPendingByEmail(user.Email, time.Now())
+}
+
+func (s *workspaceService) AcceptInvitation(token string, userID uint) (*models.WorkspaceMember, error) {
+	invitation, err := s.getInvitation(token, userID)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		return nil, ErrInvitationExpired
+	}
+
+	member := &models.WorkspaceMember{
+		WorkspaceID: invitation.WorkspaceID,
+		UserID:      userID,
+		Role:        invitation.Role,
+	}
+	if err := s.invitationRepo.Accept(invitation, member); err != nil {
+		return nil, err
+	}
+	member.Workspace = invitation.Workspace
+	return member, nil
+}
+
+func (s *workspaceService) DeclineInvitation(token string, userID uint) error {
+	invitation, err := s.getInvitation(token, userID)
+	if err != nil {
+		return err
+	}
+	invitation.Status = models.InvitationDeclined
+	return s.invitationRepo.Update(invitation)
+}
+
+// getInvitation loads the pending invitation with the token, if it was sent
+// to the user's email. Invitations for anyone else are reported as not found
+// so tokens can't be probed.
+func (s *workspaceService) getInvitation(token string, userID uint) (*models.WorkspaceInvitation, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	invitation, err := s.invitationRepo.FindByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(invitation.Email, user.Email) || invitation.Status != models.InvitationPending {
+		return nil, repository.ErrInvitationNotFound
+	}
+	return invitation, nil
+}
+
+// getManager returns the user's membership of the workspace if they are its
+// owner or an admin.
+func (s *workspaceService) getManager(workspaceID, userID uint) (*models.WorkspaceMember, error) {
+	member, err := s.GetWorkspace(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role != models.WorkspaceRoleOwner && member.Role != models.WorkspaceRoleAdmin {
+		return nil, ErrWorkspaceAccessDenied
+	}
+	return member, nil
+}
+
+// checkCanManage checks that the manager may change a membership from the
+// current role to the new one; either is empty when a member is being added
+// or removed. Nobody can change the owner, and only the owner can manage
+// admins.
+func checkCanManage(manager *models.WorkspaceMember, current, next models.WorkspaceRole) error {
+	if current == models.WorkspaceRoleOwner {
+		return ErrCannotChangeOwner
+	}
+	if manager.Role != models.WorkspaceRoleOwner && (current == models.WorkspaceRoleAdmin || next == models.WorkspaceRoleAdmin) {
+		return ErrWorkspaceAccessDenied
+	}
+	return nil
+}
+
+func newInvitationToken() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(random), nil
+}
@@ -0,0 +1,307 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKeyID     = "AKIDEXAMPLE"
+	testSecretAccessKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+var authorizationPattern = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=([^/]+)/(\d{8})/([^/]+)/s3/aws4_request, SignedHeaders=([^,]+), Signature=([0-9a-f]{64})$`)
+
+// fakeS3 is a bucket served over HTTP that checks the SigV4 signature of every
+// request the way S3 does, recomputing it from the request as received.
+type fakeS3 struct {
+	t       *testing.T
+	region  string
+	mu      sync.Mutex
+	objects map[string]fakeObject
+	paths   []string
+	hosts   []string
+}
+
+type fakeObject struct {
+	body        []byte
+	contentType string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path, _, _ := strings.Cut(r.RequestURI, "?")
+	f.paths = append(f.paths, path)
+	f.hosts = append(f.hosts, r.Host)
+	if err := f.verify(r, path); err != nil {
+		f.t.Errorf("%s %s: %v", r.Method, path, err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if len(r.TransferEncoding) > 0 {
+			f.t.Errorf("PUT %s: sent with transfer encoding %v, want a content length", path, r.TransferEncoding)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if int64(len(body)) != r.ContentLength {
+			f.t.Errorf("PUT %s: got %d bytes, want the declared %d", path, len(body), r.ContentLength)
+		}
+		f.objects[path] = fakeObject{body: body, contentType: r.Header.Get("Content-Type")}
+	case http.MethodGet:
+		object, ok := f.objects[path]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(object.body)
+	case http.MethodDelete:
+		if _, ok := f.objects[path]; !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		delete(f.objects, path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unexpected method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeS3) verify(r *http.Request, path string) error {
+	match := authorizationPattern.FindStringSubmatch(r.Header.Get("Authorization"))
+	if match == nil {
+		return errors.New("malformed Authorization header " + r.Header.Get("Authorization"))
+	}
+	accessKeyID, date, region, signedHeaders, signature := match[1], match[2], match[3], match[4], match[5]
+	if accessKeyID != testAccessKeyID {
+		return errors.New("signed with access key " + accessKeyID)
+	}
+	if region != f.region {
+		return errors.New("signed for region " + region)
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	signedAt, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil {
+		return errors.New("invalid X-Amz-Date " + amzDate)
+	}
+	if signedAt.Format("20060102") != date {
+		return errors.New("credential scope date doesn't match X-Amz-Date")
+	}
+	if skew := time.Since(signedAt); skew < -time.Minute || skew > time.Minute {
+		return errors.New("X-Amz-Date is too far from now")
+	}
+	if r.Header.Get("X-Amz-Content-Sha256") != unsignedPayload {
+		return errors.New("payload isn't marked as unsigned")
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range strings.Split(signedHeaders, ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		if value == "" {
+			return errors.New("signed header " + name + " is missing")
+		}
+		canonicalHeaders.WriteString(name + ":" + value + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		path,
+		r.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashedRequest[:])
+
+	key := hmacSHA256([]byte("AWS4"+testSecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	if want := hex.EncodeToString(hmacSHA256(key, stringToSign)); signature != want {
+		return errors.New("signature doesn't match the request")
+	}
+	return nil
+}
+
+// newTestS3Store starts a fake bucket and returns a store pointed at it. With
+// virtual-hosted style addressing the bucket is part of the host name, so the
+// store's client dials the fake server whatever host it asks for.
+func newTestS3Store(t *testing.T, usePathStyle bool) (*S3Store, *fakeS3) {
+	t.Helper()
+	fake := &fakeS3{t: t, region: "eu-west-1", objects: map[string]fakeObject{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:        server.URL,
+		Region:          fake.region,
+		Bucket:          "listario",
+		AccessKeyID:     testAccessKeyID,
+		SecretAccessKey: testSecretAccessKey,
+		UsePathStyle:    usePathStyle,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverAddr := server.Listener.Addr().String()
+	store.client = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, serverAddr)
+		},
+	}}
+	return store, fake
+}
+
+func TestS3StoreSignsRequests(t *testing.T) {
+	tests := []struct {
+		name         string
+		usePathStyle bool
+		key          string
+		wantPath     string
+		wantHost     string
+	}{
+		{
+			name:         "path style",
+			usePathStyle: true,
+			key:          "tasks/1/report.pdf",
+			wantPath:     "/listario/tasks/1/report.pdf",
+		},
+		{
+			name:         "virtual-hosted style",
+			usePathStyle: false,
+			key:          "tasks/1/report.pdf",
+			wantPath:     "/tasks/1/report.pdf",
+			wantHost:     "listario.",
+		},
+		{
+			name:         "escaped key",
+			usePathStyle: true,
+			key:          "tasks/2/résumé v2+final.pdf",
+			wantPath:     "/listario/tasks/2/r%C3%A9sum%C3%A9%20v2%2Bfinal.pdf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fake := newTestS3Store(t, tt.usePathStyle)
+			ctx := context.Background()
+
+			content := "quarterly numbers"
+			if err := store.Put(ctx, tt.key, strings.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			if got := fake.objects[tt.wantPath].contentType; got != "application/pdf" {
+				t.Errorf("stored content type = %q, want application/pdf", got)
+			}
+
+			body, err := store.Get(ctx, tt.key)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			got, err := io.ReadAll(body)
+			body.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != content {
+				t.Errorf("Get returned %q, want %q", got, content)
+			}
+
+			if err := store.Delete(ctx, tt.key); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if len(fake.objects) != 0 {
+				t.Errorf("objects left after Delete: %v", fake.objects)
+			}
+
+			for i, path := range fake.paths {
+				if path != tt.wantPath {
+					t.Errorf("request %d went to path %q, want %q", i, path, tt.wantPath)
+				}
+				if !strings.HasPrefix(fake.hosts[i], tt.wantHost) {
+					t.Errorf("request %d went to host %q, want it to start with %q", i, fake.hosts[i], tt.wantHost)
+				}
+			}
+		})
+	}
+}
+
+func TestS3StoreEmptyPut(t *testing.T) {
+	store, fake := newTestS3Store(t, true)
+
+	if err := store.Put(context.Background(), "tasks/1/empty.txt", strings.NewReader(""), 0, ""); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	object, ok := fake.objects["/listario/tasks/1/empty.txt"]
+	if !ok || len(object.body) != 0 {
+		t.Errorf("stored object = %+v, %v, want an empty object", object, ok)
+	}
+}
+
+func TestS3StoreMissingObject(t *testing.T) {
+	store, _ := newTestS3Store(t, true)
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, "tasks/1/missing.txt"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Get of a missing object returned %v, want ErrBlobNotFound", err)
+	}
+	if err := store.Delete(ctx, "tasks/1/missing.txt"); err != nil {
+		t.Errorf("Delete of a missing object returned %v, want nil", err)
+	}
+}
+
+func TestS3StoreSignature(t *testing.T) {
+	store, err := NewS3Store(S3Config{
+		Endpoint:        "http://minio.internal:9000",
+		Region:          "eu-west-1",
+		Bucket:          "listario",
+		AccessKeyID:     testAccessKeyID,
+		SecretAccessKey: testSecretAccessKey,
+		UsePathStyle:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := store.newRequest(context.Background(), http.MethodPut, "tasks/7/notes + plans.txt", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.sign(req, time.Date(2026, 3, 14, 10, 15, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20260314/eu-west-1/s3/aws4_request, " +
+		"SignedHeaders=host;x-amz-content-sha256;x-amz-date, " +
+		"Signature=559030b43c1153913fcc258a5e9ea706c6adf1eedf65f11832f636e3d0e86c52"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %q\nwant %q", got, want)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20260314T101500Z" {
+		t.Errorf("X-Amz-Date = %q, want 20260314T101500Z", got)
+	}
+	if got := req.URL.EscapedPath(); got != "/listario/tasks/7/notes%20%2B%20plans.txt" {
+		t.Errorf("path = %q, want /listario/tasks/7/notes%%20%%2B%%20plans.txt", got)
+	}
+}
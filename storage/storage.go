@@ -0,0 +1,59 @@
+// Package storage keeps the contents of uploaded files, outside the database.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+var (
+	ErrBlobNotFound = errors.New("blob not found")
+)
+
+// BlobStore stores opaque blobs under keys made of path-like segments, such
+// as "tasks/12/3f9a...". Deleting a missing blob is not an error, so cleanup
+// can safely be retried.
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+const defaultLocalDir = "./data/blobs"
+
+// NewFromEnv builds the blob store selected by STORAGE_DRIVER: "local" (the
+// default) keeps blobs under STORAGE_LOCAL_DIR, and "s3" keeps them in an
+// S3-compatible bucket configured by the S3_* variables.
+func NewFromEnv() (BlobStore, error) {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "local":
+		dir := os.Getenv("STORAGE_LOCAL_DIR")
+		if dir == "" {
+			dir = defaultLocalDir
+		}
+		return NewLocalStore(dir)
+	case "s3":
+		usePathStyle := false
+		if value := os.Getenv("S3_USE_PATH_STYLE"); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("S3_USE_PATH_STYLE must be true or false, got %q", value)
+			}
+			usePathStyle = parsed
+		}
+		return NewS3Store(S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			UsePathStyle:    usePathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q, expected local or s3", driver)
+	}
+}
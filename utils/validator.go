@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"net/url"
+	"strings"
 	"unicode"
 
+	"github.com/RLRama/listario-backend/models"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -14,6 +17,8 @@ func NewCustomValidator() *CustomValidator {
 	v := validator.New()
 
 	v.RegisterValidation("password", validatePassword)
+	v.RegisterValidation("priority", validatePriority)
+	v.RegisterValidation("https_url", validateHTTPSURL)
 
 	return &CustomValidator{
 		validator: v,
@@ -53,3 +58,13 @@ func validatePassword(fl validator.FieldLevel) bool {
 
 	return hasUpper && hasLower && hasNumber && hasSpecial
 }
+
+func validatePriority(fl validator.FieldLevel) bool {
+	return models.TaskPriority(fl.Field().String()).IsValid()
+}
+
+// validateHTTPSURL accepts absolute https URLs with a host.
+func validateHTTPSURL(fl validator.FieldLevel) bool {
+	u, err := url.Parse(fl.Field().String())
+	return err == nil && strings.EqualFold(u.Scheme, "https") && u.Host != ""
+}
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/RLRama/listario-backend/logger"
+)
+
+// Group runs background workers until their shared context is cancelled and
+// lets the caller wait for them to finish before shutting down.
+type Group struct {
+	ctx context.Context
+	wg  sync.WaitGroup
+}
+
+func NewGroup(ctx context.Context) *Group {
+	return &Group{ctx: ctx}
+}
+
+// Go starts run in its own goroutine. A worker that panics is logged and
+// restarted, so one bad run doesn't stop it for the rest of the process.
+func (g *Group) Go(name string, run func(ctx context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		logger.Info().Str("worker", name).Msg("Starting background worker")
+		for !g.runOnce(name, run) {
+			select {
+			case <-g.ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+		logger.Info().Str("worker", name).Msg("Background worker stopped")
+	}()
+}
+
+// runOnce runs the worker, reporting false if it panicked.
+func (g *Group) runOnce(name string, run func(ctx context.Context)) (finished bool) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.Error().Str("worker", name).Interface("panic", recovered).Msg("Background worker panicked, restarting it")
+		}
+	}()
+	run(g.ctx)
+	return true
+}
+
+// Wait blocks until every worker has returned, or the timeout passes,
+// reporting whether they all stopped in time.
+func (g *Group) Wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/service"
+)
+
+// ReminderPollInterval is the longest the scheduler sleeps between looking
+// for due reminders, which bounds how late it notices reminders scheduled by
+// other processes or moved by a change to their task's due date.
+const ReminderPollInterval = 30 * time.Second
+
+// ReminderScheduler delivers reminders as they come due. Pending reminders
+// live in the database, so those that came due while no scheduler was running
+// are delivered as soon as one starts.
+type ReminderScheduler struct {
+	reminderService service.ReminderService
+	pollInterval    time.Duration
+}
+
+func NewReminderScheduler(reminderService service.ReminderService, pollInterval time.Duration) *ReminderScheduler {
+	return &ReminderScheduler{
+		reminderService: reminderService,
+		pollInterval:    pollInterval,
+	}
+}
+
+// Run delivers due reminders and then sleeps until the next one comes due, a
+// new one is scheduled or the poll interval passes, until the context is
+// cancelled.
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-s.reminderService.Scheduled():
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		}
+
+		timer.Reset(s.deliver(ctx))
+	}
+}
+
+// deliver delivers the reminders that are due and returns how long to wait
+// before looking again.
+func (s *ReminderScheduler) deliver(ctx context.Context) time.Duration {
+	for {
+		sent, err := s.reminderService.DeliverDueReminders(ctx, time.Now())
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to deliver due reminders")
+			return s.pollInterval
+		}
+		if sent > 0 {
+			logger.Info().Int("reminders", sent).Msg("Delivered due reminders")
+		}
+		// A full batch may have left more reminders due.
+		if sent == 0 || ctx.Err() != nil {
+			break
+		}
+	}
+
+	next, err := s.reminderService.NextReminderAt()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to find the next reminder")
+		return s.pollInterval
+	}
+	if next == nil {
+		return s.pollInterval
+	}
+	// Waiting at least a second keeps a reminder that keeps failing to be
+	// claimed from spinning the loop.
+	return min(max(time.Until(*next), time.Second), s.pollInterval)
+}
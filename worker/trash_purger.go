@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/RLRama/listario-backend/logger"
+	"github.com/RLRama/listario-backend/service"
+)
+
+const (
+	DefaultTrashRetentionDays = 30
+	TrashPurgeInterval        = time.Hour
+)
+
+// TrashPurger permanently deletes tasks that have been in the trash for longer
+// than the retention period.
+type TrashPurger struct {
+	taskService service.TaskService
+	retention   time.Duration
+	interval    time.Duration
+}
+
+func NewTrashPurger(taskService service.TaskService, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		taskService: taskService,
+		retention:   retention,
+		interval:    interval,
+	}
+}
+
+// Run purges the trash right away and then once every interval, until the
+// context is cancelled.
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *TrashPurger) purge() {
+	cutoff := time.Now().Add(-p.retention)
+	purged, err := p.taskService.PurgeTrash(cutoff)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to purge expired tasks from the trash")
+		return
+	}
+	if purged > 0 {
+		logger.Info().Int("tasks", purged).Time("deletedBefore", cutoff).Msg("Purged expired tasks from the trash")
+	}
+}
+
+// TrashRetentionFromEnv reads how long deleted tasks stay in the trash from
+// TRASH_RETENTION_DAYS, defaulting to DefaultTrashRetentionDays.
+func TrashRetentionFromEnv() (time.Duration, error) {
+	days := DefaultTrashRetentionDays
+	if value := os.Getenv("TRASH_RETENTION_DAYS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return 0, fmt.Errorf("TRASH_RETENTION_DAYS must be a positive number of days, got %q", value)
+		}
+		days = parsed
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}